@@ -59,6 +59,18 @@ type EngineConfig struct {
 
 	// MaxPlayersPerRoom - максимальное количество игроков в комнате
 	MaxPlayersPerRoom int
+
+	// ReconcileInterval - интервал сверки стеков игроков с журналом фишек
+	ReconcileInterval time.Duration
+
+	// MaxSitOutOrbits - сколько кругов игрок может пропустить, прежде чем его поднимут из-за стола
+	MaxSitOutOrbits int
+
+	// SeatOfferTimeout - сколько игрок из листа ожидания может думать над предложенным местом
+	SeatOfferTimeout time.Duration
+
+	// RunItTimeout - сколько игроки в олл-ине могут думать над предложением прогнать доску несколько раз
+	RunItTimeout time.Duration
 }
 
 // Load - загружает конфигурацию из переменных окружения с дефолтными значениями
@@ -111,6 +123,18 @@ func Load() *Config {
 
 			// Максимум игроков в комнате: по умолчанию 9
 			MaxPlayersPerRoom: getEnvAsInt("ENGINE_MAX_PLAYERS", 9),
+
+			// Интервал сверки журнала фишек: по умолчанию 5 минут
+			ReconcileInterval: getEnvAsDuration("ENGINE_RECONCILE_INTERVAL", 5*time.Minute),
+
+			// Максимум пропущенных кругов в sit out: по умолчанию 3
+			MaxSitOutOrbits: getEnvAsInt("ENGINE_MAX_SIT_OUT_ORBITS", 3),
+
+			// Время на принятие места из листа ожидания: по умолчанию 30 секунд
+			SeatOfferTimeout: getEnvAsDuration("ENGINE_SEAT_OFFER_TIMEOUT", 30*time.Second),
+
+			// Время на ответ о нескольких прогонах доски: по умолчанию 10 секунд
+			RunItTimeout: getEnvAsDuration("ENGINE_RUN_IT_TIMEOUT", 10*time.Second),
 		},
 	}
 }
@@ -180,6 +204,26 @@ func (c *Config) Validate() error {
 		return ErrInvalidMinPlayers
 	}
 
+	// Проверяем, что интервал сверки журнала больше 0
+	if c.Engine.ReconcileInterval <= 0 {
+		return ErrInvalidReconcileInterval
+	}
+
+	// Проверяем, что лимит пропущенных кругов положительный
+	if c.Engine.MaxSitOutOrbits < 1 {
+		return ErrInvalidMaxSitOutOrbits
+	}
+
+	// Проверяем, что время на принятие места положительное
+	if c.Engine.SeatOfferTimeout <= 0 {
+		return ErrInvalidSeatOfferTimeout
+	}
+
+	// Проверяем, что время на ответ о прогонах доски положительное
+	if c.Engine.RunItTimeout <= 0 {
+		return ErrInvalidRunItTimeout
+	}
+
 	// Всё корректно
 	return nil
 }
@@ -190,6 +234,11 @@ var (
 	ErrInvalidRedisDB       = NewConfigError("redis DB must be between 0 and 15")
 	ErrInvalidCheckInterval = NewConfigError("check interval must be greater than 0")
 	ErrInvalidMinPlayers    = NewConfigError("minimum players must be at least 2")
+
+	ErrInvalidReconcileInterval = NewConfigError("reconcile interval must be greater than 0")
+	ErrInvalidMaxSitOutOrbits   = NewConfigError("max sit-out orbits must be at least 1")
+	ErrInvalidSeatOfferTimeout  = NewConfigError("seat offer timeout must be greater than 0")
+	ErrInvalidRunItTimeout      = NewConfigError("run it timeout must be greater than 0")
 )
 
 // ConfigError - кастомный тип ошибки конфигурации
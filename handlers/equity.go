@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/services"
+	"poker-engine/utils"
+)
+
+// EquityHandler - обработчик административного запроса эквити
+// Считает эквити по произвольным картам или по текущей раздаче комнаты
+type EquityHandler struct {
+	// equityCalculator - сервис расчета эквити
+	equityCalculator *services.EquityCalculator
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewEquityHandler - создает новый экземпляр EquityHandler
+func NewEquityHandler(equityCalculator *services.EquityCalculator) *EquityHandler {
+	return &EquityHandler{
+		equityCalculator: equityCalculator,
+		logger:           utils.NewLogger("Equity"),
+	}
+}
+
+// Handle - рассчитывает эквити по известным картам
+// Параметры:
+//   - request: руки 2-10 игроков, открытая доска, вышедшие карты и количество прогонов Монте-Карло
+//
+// Возвращает эквити каждой руки в порядке запроса
+func (h *EquityHandler) Handle(request *models.EquityRequest) (*models.EquityResult, error) {
+	if request == nil {
+		return nil, services.ErrInvalidEquityRequest
+	}
+
+	h.logger.Debugf("Расчет эквити: %d рук, доска %v, вышедшие карты %v", len(request.Players), request.Board, request.Dead)
+
+	result, err := h.equityCalculator.Calculate(request)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчета эквити: %w", err)
+	}
+
+	return result, nil
+}
+
+// HandleRoom - рассчитывает эквити игроков текущей раздачи комнаты
+// Параметры:
+//   - clubID: ID клуба
+//   - roomID: ID комнаты
+//   - iterations: количество прогонов Монте-Карло (0 - по умолчанию)
+//
+// Возвращает эквити каждого игрока, не сбросившего карты
+func (h *EquityHandler) HandleRoom(clubID, roomID string, iterations int) (*models.EquityResult, error) {
+	result, err := h.equityCalculator.CalculateRoom(clubID, roomID, iterations)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка расчета эквити в комнате %s:%s: %w", clubID, roomID, err)
+	}
+
+	return result, nil
+}
@@ -110,6 +110,10 @@ func (h *GameStartHandler) Handle(clubID, roomID string, playersCount int) error
 	// Опционально: можно сбросить pot и current_bet (если они не были сброшены ранее)
 	pipe.HSet(ctx, gameStateKey, "pot", 0)
 	pipe.HSet(ctx, gameStateKey, "current_bet", 0)
+	pipe.HSet(ctx, gameStateKey, "last_raise", 0)
+	pipe.HSet(ctx, gameStateKey, "raise_count", 0)
+	pipe.HSet(ctx, gameStateKey, "boards", "[]")
+	pipe.HSet(ctx, gameStateKey, "run_count", 0)
 
 	// Выполняем все команды
 	_, err = pipe.Exec(ctx)
@@ -216,12 +220,17 @@ func (h *GameStartHandler) PrepareGameStart(clubID, roomID string) error {
 		return err
 	}
 
-	// Устанавливаем начальную позицию дилера (обычно 0)
-	// В будущем можно сделать ротацию
-	dealerPosition := 0
-	if game != nil && game.DealerPosition >= 0 {
-		// Если игра уже была, берем следующую позицию
-		dealerPosition = (game.DealerPosition + 1) % len(playerIDs)
+	// Дилер переходит на следующее занятое место по часовой стрелке
+	// (номера мест могут идти с пропусками, поэтому берем их из turn_order)
+	seats, err := h.gameStateService.GetSeatedPlayers(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	dealerPosition := models.NextOccupiedSeat(seats, -1)
+	if game != nil && game.DealerPosition >= 0 && game.GameID != "" {
+		// Если игра уже была, берем следующее занятое место
+		dealerPosition = models.NextOccupiedSeat(seats, game.DealerPosition)
 	}
 
 	// Обновляем позицию дилера
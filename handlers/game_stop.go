@@ -93,10 +93,15 @@ func (h *GameStopHandler) Handle(clubID, roomID, previousPhase, reason string) e
 	// 3. Сбрасываем игровые параметры
 	pipe.HSet(ctx, gameStateKey, "pot", 0)
 	pipe.HSet(ctx, gameStateKey, "current_bet", 0)
+	pipe.HSet(ctx, gameStateKey, "last_raise", 0)
+	pipe.HSet(ctx, gameStateKey, "raise_count", 0)
 	pipe.HSet(ctx, gameStateKey, "current_player_position", "")
 
 	// 4. Очищаем общие карты
 	pipe.HSet(ctx, gameStateKey, "community_cards", "[]")
+	pipe.HSet(ctx, gameStateKey, "boards", "[]")
+	pipe.HSet(ctx, gameStateKey, "run_count", 0)
+	pipe.HSet(ctx, gameStateKey, "bomb_pot", false)
 
 	// Выполняем все команды
 	_, err = pipe.Exec(ctx)
@@ -176,12 +181,11 @@ func (h *GameStopHandler) HandleWithCleanup(clubID, roomID, previousPhase, reaso
 	deckKey := keys.RoomDeck(clubID, roomID)
 	h.redis.Del(deckKey)
 
-	// Сбрасываем банки
-	potsKey := keys.RoomPots(clubID, roomID)
-	h.redis.HMSet(potsKey, map[string]interface{}{
-		"main_pot":  0,
-		"side_pots": "[]",
-	})
+	// Удаляем действия незавершенной раздачи, чтобы они не попали в статистику
+	h.redis.Del(keys.RoomHandActions(clubID, roomID))
+
+	// Удаляем банки
+	h.redis.Del(keys.RoomPots(clubID, roomID))
 
 	h.logger.Infof("Выполнена полная очистка данных игры %s:%s", clubID, roomID)
 
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/services"
+	"poker-engine/utils"
+)
+
+// PlayerStatsHandler - обработчик запросов статистики игроков клуба (для HUD и рейтингов)
+type PlayerStatsHandler struct {
+	// playerStats - сервис статистики игроков
+	playerStats *services.PlayerStatsService
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewPlayerStatsHandler - создает новый экземпляр PlayerStatsHandler
+func NewPlayerStatsHandler(playerStats *services.PlayerStatsService) *PlayerStatsHandler {
+	return &PlayerStatsHandler{
+		playerStats: playerStats,
+		logger:      utils.NewLogger("PlayerStats"),
+	}
+}
+
+// Handle - возвращает статистику игрока в клубе
+// Параметры:
+//   - clubID: ID клуба
+//   - userID: ID игрока
+//
+// Возвращает счетчики и рассчитанные показатели: VPIP, PFR, 3-бет, фолд на 3-бет, AF, WTSD, W$SD
+func (h *PlayerStatsHandler) Handle(clubID, userID string) (*models.PlayerStatsReport, error) {
+	report, err := h.playerStats.GetStats(clubID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статистики игрока %s: %w", userID, err)
+	}
+
+	return report, nil
+}
+
+// HandleLeaderboard - возвращает рейтинг игроков клуба по чистому выигрышу
+// Параметры:
+//   - clubID: ID клуба
+//   - limit: количество игроков (0 - по умолчанию)
+//
+// Возвращает статистику игроков в порядке убывания чистого выигрыша
+func (h *PlayerStatsHandler) HandleLeaderboard(clubID string, limit int) ([]*models.PlayerStatsReport, error) {
+	h.logger.Debugf("Запрос рейтинга клуба %s (игроков: %d)", clubID, limit)
+
+	reports, err := h.playerStats.GetLeaderboard(clubID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения рейтинга клуба %s: %w", clubID, err)
+	}
+
+	return reports, nil
+}
@@ -26,6 +26,9 @@ type Application struct {
 	gameStateService *services.GameStateService
 	actionLogger     *services.ActionLogger
 	roomMonitor      *services.RoomMonitor
+	chipLedger       *services.ChipLedger
+	ledgerReconciler *services.LedgerReconciler
+	waitingList      *services.WaitingListProcessor
 
 	// logger - главный логгер
 	logger *utils.Logger
@@ -88,10 +91,28 @@ func NewApplication() (*Application, error) {
 	actionLogger := services.NewActionLogger(redis)
 	logger.Success("  ✓ ActionLogger")
 
+	// Создаем часы блайндов и ротацию смешанных игр (применяются в начале каждой раздачи)
+	blindClock := services.NewBlindClockService(redis, gameStateService, actionLogger)
+	mixedGameRotator := services.NewMixedGameRotator(redis, gameStateService, actionLogger)
+
 	// Создаем мониторинг комнат
-	roomMonitor := services.NewRoomMonitor(redis, &cfg.Engine, gameStateService, actionLogger)
+	roomMonitor := services.NewRoomMonitor(redis, &cfg.Engine, gameStateService, blindClock, mixedGameRotator, actionLogger)
 	logger.Success("  ✓ RoomMonitor")
 
+	// Создаем журнал фишек и сверку стеков
+	chipLedger := services.NewChipLedger(redis, gameStateService)
+	logger.Success("  ✓ ChipLedger")
+
+	ledgerReconciler := services.NewLedgerReconciler(redis, chipLedger, gameStateService, actionLogger, cfg.Engine.ReconcileInterval)
+	logger.Success("  ✓ LedgerReconciler")
+
+	// Создаем посадку за стол и листы ожидания
+	seatManager := services.NewSeatManager(redis, gameStateService, actionLogger)
+	buyInManager := services.NewBuyInManager(redis, gameStateService, chipLedger, seatManager, actionLogger)
+	waitingListService := services.NewWaitingListService(redis, gameStateService, buyInManager, seatManager, actionLogger, cfg.Engine.SeatOfferTimeout)
+	waitingListProcessor := services.NewWaitingListProcessor(redis, waitingListService, cfg.Engine.CheckInterval)
+	logger.Success("  ✓ WaitingListProcessor")
+
 	logger.Success("Все сервисы инициализированы")
 
 	// === ШАГ 4: НАСТРОЙКА GRACEFUL SHUTDOWN ===
@@ -104,6 +125,9 @@ func NewApplication() (*Application, error) {
 		gameStateService: gameStateService,
 		actionLogger:     actionLogger,
 		roomMonitor:      roomMonitor,
+		chipLedger:       chipLedger,
+		ledgerReconciler: ledgerReconciler,
+		waitingList:      waitingListProcessor,
 		logger:           logger,
 		ctx:              ctx,
 		cancelFunc:       cancel,
@@ -120,6 +144,12 @@ func (app *Application) Start() error {
 	// Запускаем мониторинг комнат в отдельной горутине
 	go app.roomMonitor.Start()
 
+	// Запускаем сверку журнала фишек в отдельной горутине
+	go app.ledgerReconciler.Start()
+
+	// Запускаем обработку листов ожидания в отдельной горутине
+	go app.waitingList.Start()
+
 	// Ждем сигнала завершения
 	<-app.shutdownChan
 
@@ -148,6 +178,14 @@ func (app *Application) Shutdown() error {
 		app.roomMonitor.Stop()
 		app.logger.Success("  ✓ Мониторинг остановлен")
 
+		app.logger.Info("Останавливаем сверку журнала фишек...")
+		app.ledgerReconciler.Stop()
+		app.logger.Success("  ✓ Сверка остановлена")
+
+		app.logger.Info("Останавливаем обработку листов ожидания...")
+		app.waitingList.Stop()
+		app.logger.Success("  ✓ Листы ожидания остановлены")
+
 		// === ШАГ 2: ЗАКРЫТИЕ REDIS ===
 		app.logger.Info("Закрываем соединение с Redis...")
 		if err := app.redis.Close(); err != nil {
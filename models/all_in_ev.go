@@ -0,0 +1,122 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AllInSnapshot - эквити игроков, зафиксированное в момент олл-ина до ривера
+// Хранится до вскрытия: по нему рассчитывается ожидаемый выигрыш каждого игрока
+type AllInSnapshot struct {
+	// ID раздачи, в которой произошел олл-ин
+	GameID string `json:"game_id"`
+
+	// Руки участников, открытая доска и вышедшие из игры карты на момент олл-ина
+	Request EquityRequest `json:"request"`
+
+	// Эквити каждого участника в общем банке
+	Equity []PlayerEquity `json:"equity"`
+
+	// Эквити претендентов каждого банка (основного и боковых), рассчитанное в момент олл-ина,
+	// чтобы на вскрытии банки делились по тем же числам, что были показаны игрокам
+	Pots []AllInPotEquity `json:"pots,omitempty"`
+
+	// Время олл-ина (Unix timestamp в миллисекундах)
+	Timestamp int64 `json:"timestamp"`
+}
+
+// AllInPotEquity - эквити претендентов одного банка на момент олл-ина
+type AllInPotEquity struct {
+	// Претенденты банка (по возрастанию ID)
+	Players []string `json:"players"`
+
+	// Доля банка, ожидаемая каждым претендентом
+	Equity map[string]float64 `json:"equity"`
+}
+
+// PotEquity - эквити претендентов банка, зафиксированное в момент олл-ина
+// Банк с единственным претендентом целиком принадлежит ему
+func (s *AllInSnapshot) PotEquity(players []string) (map[string]float64, bool) {
+	if len(players) == 1 {
+		return map[string]float64{players[0]: 1}, true
+	}
+
+	key := allInPotKey(players)
+	for _, pot := range s.Pots {
+		if allInPotKey(pot.Players) == key {
+			return pot.Equity, true
+		}
+	}
+	return nil, false
+}
+
+// AllInEligibleSets - составы претендентов банков по ставкам оставшихся в раздаче игроков
+// Банк каждого уровня ставок разыгрывают игроки, поставившие не меньше этого уровня
+// (так же банки нарезает BuildPots); игрок, не поставивший ничего сверх анте, претендует
+// только на банк мертвых фишек. Возвращаются только составы из двух и более игроков,
+// ID в каждом составе по возрастанию
+func AllInEligibleSets(bets map[string]int) [][]string {
+	levels := make([]int, 0, len(bets))
+	seen := make(map[int]bool)
+	for _, bet := range bets {
+		if !seen[bet] {
+			seen[bet] = true
+			levels = append(levels, bet)
+		}
+	}
+	sort.Ints(levels)
+
+	var sets [][]string
+	for _, level := range levels {
+		var players []string
+		for userID, bet := range bets {
+			if bet >= level {
+				players = append(players, userID)
+			}
+		}
+		if len(players) < 2 {
+			break
+		}
+		sort.Strings(players)
+		sets = append(sets, players)
+	}
+
+	return sets
+}
+
+// allInPotKey - ключ состава претендентов банка (не зависит от порядка ID)
+func allInPotKey(players []string) string {
+	sorted := append([]string{}, players...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// AllInEVStats - накопленные итоги раздач игрока, в которых олл-ин случился до ривера
+// Net - фактический результат (выигрыш минус ставки), EVNet - результат, который игрок
+// получил бы, если бы каждый банк делился по эквити на момент олл-ина
+type AllInEVStats struct {
+	UserID string `json:"user_id"`
+	Hands  int    `json:"hands"`
+	Net    int    `json:"net"`
+	EVNet  int    `json:"ev_net"`
+}
+
+// NewAllInEVStatsFromRedis - создает AllInEVStats из данных Redis hash
+func NewAllInEVStatsFromRedis(userID string, data map[string]string) *AllInEVStats {
+	hands, _ := strconv.Atoi(data["hands"])
+	net, _ := strconv.Atoi(data["net"])
+	evNet, _ := strconv.Atoi(data["ev_net"])
+
+	return &AllInEVStats{
+		UserID: userID,
+		Hands:  hands,
+		Net:    net,
+		EVNet:  evNet,
+	}
+}
+
+// Luck - насколько фактический результат выше ожидаемого (отрицательное - игроку не везет)
+func (s *AllInEVStats) Luck() int {
+	return s.Net - s.EVNet
+}
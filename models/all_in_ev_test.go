@@ -0,0 +1,61 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllInEligibleSets(t *testing.T) {
+	tests := []struct {
+		name string
+		bets map[string]int
+		want [][]string
+	}{
+		{
+			name: "равные ставки - один банк",
+			bets: map[string]int{"a": 100, "b": 100},
+			want: [][]string{{"a", "b"}},
+		},
+		{
+			name: "короткий олл-ин - основной и боковой банк",
+			bets: map[string]int{"a": 50, "b": 200, "c": 200},
+			want: [][]string{{"a", "b", "c"}, {"b", "c"}},
+		},
+		{
+			name: "неуравненный излишек не образует разыгрываемого банка",
+			bets: map[string]int{"a": 50, "b": 120, "c": 300},
+			want: [][]string{{"a", "b", "c"}, {"b", "c"}},
+		},
+		{
+			name: "олл-ин на анте - банк мертвых фишек",
+			bets: map[string]int{"a": 0, "b": 80, "c": 80},
+			want: [][]string{{"a", "b", "c"}, {"b", "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AllInEligibleSets(tt.bets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AllInEligibleSets(%v) = %v, want %v", tt.bets, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllInSnapshotPotEquity(t *testing.T) {
+	snapshot := &AllInSnapshot{
+		Pots: []AllInPotEquity{
+			{Players: []string{"a", "b"}, Equity: map[string]float64{"a": 0.8, "b": 0.2}},
+		},
+	}
+
+	if equity, ok := snapshot.PotEquity([]string{"b", "a"}); !ok || equity["a"] != 0.8 {
+		t.Errorf("PotEquity(b, a) = %v, %v; ожидается зафиксированное эквити", equity, ok)
+	}
+	if equity, ok := snapshot.PotEquity([]string{"c"}); !ok || equity["c"] != 1 {
+		t.Errorf("PotEquity(c) = %v, %v; единственный претендент получает весь банк", equity, ok)
+	}
+	if _, ok := snapshot.PotEquity([]string{"a", "c"}); ok {
+		t.Error("PotEquity(a, c): состав не зафиксирован")
+	}
+}
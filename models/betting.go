@@ -0,0 +1,258 @@
+package models
+
+// BettingStructure - структура ставок комнаты
+type BettingStructure string
+
+// Константы структур ставок
+const (
+	// BettingNoLimit - безлимит: можно поставить весь стек
+	BettingNoLimit BettingStructure = "no_limit"
+
+	// BettingPotLimit - пот-лимит: максимальное повышение равно банку после колла
+	BettingPotLimit BettingStructure = "pot_limit"
+
+	// BettingFixedLimit - фикс-лимит: ставки и повышения фиксированного размера
+	// (малая ставка на префлопе и флопе, большая - на терне и ривере)
+	BettingFixedLimit BettingStructure = "fixed_limit"
+)
+
+// ForcedBetType - вид обязательных ставок в начале раздачи
+type ForcedBetType string
+
+// Константы видов обязательных ставок
+const (
+	// ForcedBetsBlinds - малый и большой блайнды
+	ForcedBetsBlinds ForcedBetType = "blinds"
+
+	// ForcedBetsButtonAnte - анте дилера за весь стол вместо блайндов (шорт-дек)
+	ForcedBetsButtonAnte ForcedBetType = "button_ante"
+)
+
+// ParseForcedBetType - парсит строку в ForcedBetType (по умолчанию - блайнды)
+func ParseForcedBetType(forcedBets string) ForcedBetType {
+	if ForcedBetType(forcedBets) == ForcedBetsButtonAnte {
+		return ForcedBetsButtonAnte
+	}
+	return ForcedBetsBlinds
+}
+
+// AnteType - вид анте в раздачах с блайндами
+type AnteType string
+
+// Константы видов анте
+const (
+	// AnteNone - без анте
+	AnteNone AnteType = "none"
+
+	// AntePerPlayer - анте ставит каждый игрок в раздаче
+	AntePerPlayer AnteType = "per_player"
+
+	// AnteButton - анте за весь стол ставит дилер
+	AnteButton AnteType = "button"
+
+	// AnteBigBlind - анте за весь стол ставит большой блайнд
+	AnteBigBlind AnteType = "big_blind"
+)
+
+// ParseAnteType - парсит строку в AnteType (по умолчанию - без анте)
+func ParseAnteType(anteType string) AnteType {
+	switch at := AnteType(anteType); at {
+	case AntePerPlayer, AnteButton, AnteBigBlind:
+		return at
+	default:
+		return AnteNone
+	}
+}
+
+// IsTableAnte - проверяет, ставит ли анте один игрок за весь стол
+func (at AnteType) IsTableAnte() bool {
+	return at == AnteButton || at == AnteBigBlind
+}
+
+// DefaultRaiseCap - максимум ставок в раунде торговли фикс-лимита (ставка + 3 повышения)
+const DefaultRaiseCap = 4
+
+// IsValid - проверяет, что структура ставок поддерживается
+func (bs BettingStructure) IsValid() bool {
+	switch bs {
+	case BettingNoLimit, BettingPotLimit, BettingFixedLimit:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseBettingStructure - парсит строку в BettingStructure
+// Если значение не задано - берется стандартная структура для разновидности покера:
+// пот-лимит для омахи, фикс-лимит для стада, безлимит для остальных
+func ParseBettingStructure(structure string, gameType GameType) BettingStructure {
+	bs := BettingStructure(structure)
+	if bs.IsValid() {
+		return bs
+	}
+	if gameType.UsesOmahaRules() {
+		return BettingPotLimit
+	}
+	if gameType.IsStud() {
+		return BettingFixedLimit
+	}
+	return BettingNoLimit
+}
+
+// BetLimits - допустимые суммы для хода игрока
+// Все суммы "до" (raise to) - итоговая ставка игрока в текущем раунде торговли
+type BetLimits struct {
+	// Сколько нужно доставить, чтобы уравнять текущую ставку
+	CallAmount int `json:"call_amount"`
+
+	// Минимальная итоговая ставка при повышении
+	MinRaiseTo int `json:"min_raise_to"`
+
+	// Максимальная итоговая ставка при повышении
+	MaxRaiseTo int `json:"max_raise_to"`
+}
+
+// LegalActions - допустимые действия игрока и суммы для них
+type LegalActions struct {
+	BetLimits
+
+	// Допустимые действия
+	Actions []PlayerAction `json:"actions"`
+}
+
+// Allows - проверяет, допустимо ли действие
+func (la LegalActions) Allows(action PlayerAction) bool {
+	for _, a := range la.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// BettingContext - состояние торговли, необходимое для расчета допустимых действий
+type BettingContext struct {
+	// Структура ставок комнаты
+	Structure BettingStructure
+
+	// Все фишки в банке, включая ставки текущего раунда
+	Pot int
+
+	// Текущая ставка раунда и размер последнего повышения
+	CurrentBet int
+	LastRaise  int
+
+	// Большой блайнд (минимальная ставка в безлимите и пот-лимите)
+	BigBlind int
+
+	// Размер ставки в фикс-лимите для текущего раунда (малая или большая ставка)
+	FixedBetSize int
+
+	// Количество ставок и повышений в раунде и их максимум (фикс-лимит)
+	RaiseCount int
+	RaiseCap   int
+
+	// В раздаче осталось два игрока (в фикс-лимите ограничение повышений снимается)
+	HeadsUp bool
+
+	// Ставка игрока в текущем раунде и его стек (без текущей ставки)
+	PlayerBet int
+	Stack     int
+}
+
+// PotLimitMaxRaiseTo - максимальная итоговая ставка в пот-лимите
+// Игрок может повысить на размер банка после своего колла:
+// maxRaiseTo = currentBet + (pot + callAmount)
+// Параметры:
+//   - pot: все фишки в банке, включая ставки текущего раунда
+//   - currentBet: текущая ставка раунда
+//   - playerBet: ставка игрока в текущем раунде
+func PotLimitMaxRaiseTo(pot, currentBet, playerBet int) int {
+	callAmount := currentBet - playerBet
+	if callAmount < 0 {
+		callAmount = 0
+	}
+	return currentBet + pot + callAmount
+}
+
+// MinRaiseTo - минимальная итоговая ставка при повышении
+// Повышение должно быть не меньше предыдущего повышения в раунде и не меньше большого блайнда
+func MinRaiseTo(currentBet, lastRaise, bigBlind int) int {
+	increment := lastRaise
+	if increment < bigBlind {
+		increment = bigBlind
+	}
+	return currentBet + increment
+}
+
+// CalculateBetLimits - рассчитывает допустимые суммы хода игрока
+// Максимум и минимум ограничены стеком игрока (all-in)
+func CalculateBetLimits(bc BettingContext) BetLimits {
+	allInTo := bc.PlayerBet + bc.Stack
+
+	limits := BetLimits{
+		CallAmount: minInt(bc.CurrentBet-bc.PlayerBet, bc.Stack),
+		MinRaiseTo: MinRaiseTo(bc.CurrentBet, bc.LastRaise, bc.BigBlind),
+		MaxRaiseTo: allInTo,
+	}
+	if limits.CallAmount < 0 {
+		limits.CallAmount = 0
+	}
+
+	switch bc.Structure {
+	case BettingPotLimit:
+		limits.MaxRaiseTo = minInt(PotLimitMaxRaiseTo(bc.Pot, bc.CurrentBet, bc.PlayerBet), allInTo)
+	case BettingFixedLimit:
+		// В фикс-лимите повышение ровно на размер ставки раунда.
+		// Ставка меньше размера ставки раунда (bring-in в стаде) дополняется до полной
+		limits.MinRaiseTo = bc.CurrentBet + bc.FixedBetSize
+		if bc.CurrentBet < bc.FixedBetSize {
+			limits.MinRaiseTo = bc.FixedBetSize
+		}
+		limits.MaxRaiseTo = minInt(limits.MinRaiseTo, allInTo)
+	}
+
+	// Если стека не хватает на минимальный рейз - можно пойти all-in на меньшую сумму
+	if limits.MinRaiseTo > allInTo {
+		limits.MinRaiseTo = allInTo
+	}
+
+	return limits
+}
+
+// CalculateLegalActions - рассчитывает допустимые действия игрока и суммы для них
+func CalculateLegalActions(bc BettingContext) LegalActions {
+	limits := CalculateBetLimits(bc)
+	legal := LegalActions{BetLimits: limits, Actions: []PlayerAction{}}
+
+	if bc.Stack <= 0 {
+		return legal
+	}
+
+	toCall := bc.CurrentBet - bc.PlayerBet
+	if toCall > 0 {
+		legal.Actions = append(legal.Actions, ActionFold, ActionCall)
+	} else {
+		legal.Actions = append(legal.Actions, ActionCheck)
+	}
+
+	// Повысить можно, если после колла остаются фишки и не достигнут лимит повышений
+	capReached := bc.Structure == BettingFixedLimit && !bc.HeadsUp && bc.RaiseCap > 0 && bc.RaiseCount >= bc.RaiseCap
+	canRaise := bc.Stack > toCall && !capReached && limits.MaxRaiseTo > bc.CurrentBet
+
+	if canRaise {
+		if bc.CurrentBet == 0 {
+			legal.Actions = append(legal.Actions, ActionBet)
+		} else {
+			legal.Actions = append(legal.Actions, ActionRaise)
+		}
+	}
+
+	// All-in доступен, если весь стек укладывается в лимит или это колл на весь стек
+	allInTo := bc.PlayerBet + bc.Stack
+	if bc.Stack <= toCall || (canRaise && allInTo <= limits.MaxRaiseTo) {
+		legal.Actions = append(legal.Actions, ActionAllIn)
+	}
+
+	return legal
+}
@@ -0,0 +1,242 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// BlindLevel - уровень расписания блайндов
+// Уровень заканчивается по времени (DurationMinutes) или по количеству раздач (Hands),
+// в зависимости от того, что наступит раньше. Перерыв (IsBreak) идет только по времени,
+// раздачи во время перерыва не начинаются
+type BlindLevel struct {
+	SmallBlind int `json:"small_blind"`
+	BigBlind   int `json:"big_blind"`
+
+	// Анте с каждого игрока (0 - без анте)
+	Ante int `json:"ante,omitempty"`
+
+	// Продолжительность уровня в минутах (0 - уровень не ограничен по времени)
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+
+	// Количество раздач на уровне (0 - уровень не ограничен по раздачам)
+	Hands int `json:"hands,omitempty"`
+
+	// Перерыв вместо уровня блайндов
+	IsBreak bool `json:"break,omitempty"`
+}
+
+// DurationMillis - продолжительность уровня в миллисекундах
+func (l BlindLevel) DurationMillis() int64 {
+	return int64(l.DurationMinutes) * 60 * 1000
+}
+
+// IsValid - проверяет уровень: у перерыва должна быть продолжительность,
+// у обычного уровня - положительные блайнды
+func (l BlindLevel) IsValid() bool {
+	if l.DurationMinutes < 0 || l.Hands < 0 || l.Ante < 0 {
+		return false
+	}
+	if l.IsBreak {
+		return l.DurationMinutes > 0
+	}
+	return l.SmallBlind > 0 && l.BigBlind >= l.SmallBlind
+}
+
+// BlindClock - часы уровней блайндов комнаты
+// Хранят расписание и абсолютное время начала текущего уровня,
+// поэтому после перезапуска движка уровень вычисляется заново по текущему времени
+type BlindClock struct {
+	// Расписание уровней
+	Levels []BlindLevel
+
+	// Номер текущего уровня (с нуля)
+	LevelIndex int
+
+	// Время начала текущего уровня (Unix timestamp в миллисекундах)
+	LevelStartedAt int64
+
+	// Сколько раздач начато на текущем уровне
+	LevelHands int
+}
+
+// BlindClockInfo - структура часов блайндов из Redis
+// Соответствует hash "club:{clubId}:room:{roomId}:blind_clock"
+type BlindClockInfo struct {
+	Levels         string `json:"levels"` // JSON массив
+	LevelIndex     string `json:"level_index"`
+	LevelStartedAt string `json:"level_started_at"`
+	LevelHands     string `json:"level_hands"`
+}
+
+// NewBlindClockFromRedis - создает BlindClock из данных Redis hash
+func NewBlindClockFromRedis(data map[string]string) (*BlindClock, error) {
+	levelIndex, _ := strconv.Atoi(data["level_index"])
+	levelStartedAt, _ := strconv.ParseInt(data["level_started_at"], 10, 64)
+	levelHands, _ := strconv.Atoi(data["level_hands"])
+
+	var levels []BlindLevel
+	if data["levels"] != "" {
+		if err := json.Unmarshal([]byte(data["levels"]), &levels); err != nil {
+			return nil, err
+		}
+	}
+
+	return &BlindClock{
+		Levels:         levels,
+		LevelIndex:     levelIndex,
+		LevelStartedAt: levelStartedAt,
+		LevelHands:     levelHands,
+	}, nil
+}
+
+// ToRedisHash - преобразует BlindClock в map для сохранения в Redis hash
+func (c *BlindClock) ToRedisHash() map[string]interface{} {
+	levelsJSON, _ := json.Marshal(c.Levels)
+
+	return map[string]interface{}{
+		"levels":           string(levelsJSON),
+		"level_index":      c.LevelIndex,
+		"level_started_at": c.LevelStartedAt,
+		"level_hands":      c.LevelHands,
+	}
+}
+
+// CurrentLevel - текущий уровень расписания
+// После последнего уровня блайнды больше не растут
+func (c *BlindClock) CurrentLevel() BlindLevel {
+	if len(c.Levels) == 0 {
+		return BlindLevel{}
+	}
+	if c.LevelIndex >= len(c.Levels) {
+		return c.Levels[len(c.Levels)-1]
+	}
+	return c.Levels[c.LevelIndex]
+}
+
+// NextLevel - следующий уровень расписания (nil, если текущий уровень последний)
+func (c *BlindClock) NextLevel() *BlindLevel {
+	if c.LevelIndex+1 >= len(c.Levels) {
+		return nil
+	}
+	return &c.Levels[c.LevelIndex+1]
+}
+
+// CurrentBlinds - последний уровень блайндов, не являющийся перерывом
+// Во время перерыва возвращает уровень, сыгранный перед ним
+func (c *BlindClock) CurrentBlinds() BlindLevel {
+	for i := c.LevelIndex; i >= 0; i-- {
+		if i < len(c.Levels) && !c.Levels[i].IsBreak {
+			return c.Levels[i]
+		}
+	}
+	return BlindLevel{}
+}
+
+// BlindLevelNumber - номер текущего уровня блайндов без учета перерывов (с единицы)
+// Во время перерыва - номер уровня, сыгранного перед ним
+func (c *BlindClock) BlindLevelNumber() int {
+	number := 0
+	for i := 0; i <= c.LevelIndex && i < len(c.Levels); i++ {
+		if !c.Levels[i].IsBreak {
+			number++
+		}
+	}
+	return number
+}
+
+// IsOnBreak - проверяет, идет ли перерыв
+func (c *BlindClock) IsOnBreak() bool {
+	return c.CurrentLevel().IsBreak
+}
+
+// Advance - переводит часы на уровень, который идет в момент nowMillis
+// Уровни по времени сменяются подряд (начало следующего = конец предыдущего),
+// уровень по раздачам - в момент проверки. Возвращает true, если уровень сменился
+func (c *BlindClock) Advance(nowMillis int64) bool {
+	changed := false
+	for c.LevelIndex < len(c.Levels)-1 {
+		level := c.Levels[c.LevelIndex]
+
+		duration := level.DurationMillis()
+		if duration > 0 && nowMillis >= c.LevelStartedAt+duration {
+			c.LevelStartedAt += duration
+		} else if !level.IsBreak && level.Hands > 0 && c.LevelHands >= level.Hands {
+			c.LevelStartedAt = nowMillis
+		} else {
+			break
+		}
+
+		c.LevelIndex++
+		c.LevelHands = 0
+		changed = true
+	}
+	return changed
+}
+
+// MillisToNextLevel - сколько миллисекунд осталось до следующего уровня
+// -1, если уровень последний или не ограничен по времени
+func (c *BlindClock) MillisToNextLevel(nowMillis int64) int64 {
+	duration := c.CurrentLevel().DurationMillis()
+	if c.NextLevel() == nil || duration <= 0 {
+		return -1
+	}
+
+	left := c.LevelStartedAt + duration - nowMillis
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// HandsToNextLevel - сколько раздач осталось до следующего уровня
+// -1, если уровень последний или не ограничен по раздачам
+func (c *BlindClock) HandsToNextLevel() int {
+	level := c.CurrentLevel()
+	if c.NextLevel() == nil || level.IsBreak || level.Hands <= 0 {
+		return -1
+	}
+
+	left := level.Hands - c.LevelHands
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// BlindClockStatus - состояние часов блайндов для клиентов
+type BlindClockStatus struct {
+	// Номер текущего уровня (с единицы)
+	Level int `json:"level"`
+
+	// Номер уровня блайндов без учета перерывов
+	BlindLevel int `json:"blind_level"`
+
+	// Текущий уровень и действующие блайнды (во время перерыва - блайнды уровня перед ним)
+	Current BlindLevel `json:"current"`
+	Blinds  BlindLevel `json:"blinds"`
+
+	// Следующий уровень (nil, если текущий последний)
+	Next *BlindLevel `json:"next,omitempty"`
+
+	// Идет ли перерыв
+	OnBreak bool `json:"on_break"`
+
+	// Время до следующего уровня в миллисекундах и количество раздач (-1 - не ограничено)
+	MillisToNextLevel int64 `json:"millis_to_next_level"`
+	HandsToNextLevel  int   `json:"hands_to_next_level"`
+}
+
+// Status - состояние часов на момент nowMillis
+func (c *BlindClock) Status(nowMillis int64) *BlindClockStatus {
+	return &BlindClockStatus{
+		Level:             c.LevelIndex + 1,
+		BlindLevel:        c.BlindLevelNumber(),
+		Current:           c.CurrentLevel(),
+		Blinds:            c.CurrentBlinds(),
+		Next:              c.NextLevel(),
+		OnBreak:           c.IsOnBreak(),
+		MillisToNextLevel: c.MillisToNextLevel(nowMillis),
+		HandsToNextLevel:  c.HandsToNextLevel(),
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"sort"
+)
+
+// KnockoutShares - кто выбил участника userID в раздаче и сколько каждый выиграл из его последнего банка
+// Последний банк участника - банк с наименьшим количеством претендентов, в который он еще вошел:
+// в него ушли последние фишки участника. Все остальные претенденты этого банка покрывали стек
+// участника, а выбившими считаются победители банка (старшей и младшей половины, на всех прогонах).
+// Если банк поделен, баунти делится пропорционально выигрышу из этого банка.
+// Возвращает пустой map, если участник ни в одном банке не проиграл фишки другим игрокам
+func KnockoutShares(userID string, pots []PotSettlement) map[string]int {
+	var sizes []int
+	seen := make(map[int]bool)
+	for _, settlement := range pots {
+		pot := SidePot{Amount: settlement.Amount, EligiblePlayers: settlement.EligiblePlayers}
+		if !pot.IsContested() || !pot.IsEligible(userID) || seen[len(pot.EligiblePlayers)] {
+			continue
+		}
+		seen[len(pot.EligiblePlayers)] = true
+		sizes = append(sizes, len(pot.EligiblePlayers))
+	}
+	sort.Ints(sizes)
+
+	// Если в банке нет выигрыша других игроков (например, весь банк ушел в рейк),
+	// выбившими считаются победители следующего по размеру банка
+	for _, size := range sizes {
+		shares := make(map[string]int)
+		for _, settlement := range pots {
+			pot := SidePot{Amount: settlement.Amount, EligiblePlayers: settlement.EligiblePlayers}
+			if len(pot.EligiblePlayers) != size || !pot.IsEligible(userID) {
+				continue
+			}
+			for winner, share := range settlement.HighShares {
+				if winner != userID && share > 0 {
+					shares[winner] += share
+				}
+			}
+			for winner, share := range settlement.LowShares {
+				if winner != userID && share > 0 {
+					shares[winner] += share
+				}
+			}
+		}
+		if len(shares) > 0 {
+			return shares
+		}
+	}
+
+	return map[string]int{}
+}
+
+// SplitBounty - делит баунти между выбившими пропорционально их долям
+// Результат округлен до целых фишек так, что в сумме дает bounty
+func SplitBounty(bounty int, shares map[string]int) map[string]int {
+	userIDs := make([]string, 0, len(shares))
+	total := 0
+	for userID, share := range shares {
+		userIDs = append(userIDs, userID)
+		total += share
+	}
+	sort.Strings(userIDs)
+
+	result := make(map[string]int, len(userIDs))
+	if bounty <= 0 || total <= 0 {
+		return result
+	}
+
+	parts := make([]float64, len(userIDs))
+	for i, userID := range userIDs {
+		parts[i] = float64(bounty) * float64(shares[userID]) / float64(total)
+	}
+	for i, amount := range roundShares(parts, bounty) {
+		result[userIDs[i]] = amount
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKnockoutShares(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		pots   []PotSettlement
+		want   map[string]int
+	}{
+		{
+			name:   "выбит единственным соперником",
+			userID: "a",
+			pots: []PotSettlement{
+				{Amount: 200, EligiblePlayers: []string{"a", "b"}, HighShares: map[string]int{"b": 200}},
+			},
+			want: map[string]int{"b": 200},
+		},
+		{
+			name:   "выбивает победитель последнего банка участника, а не бокового",
+			userID: "a",
+			pots: []PotSettlement{
+				{Amount: 300, EligiblePlayers: []string{"a", "b", "c"}, HighShares: map[string]int{"c": 300}},
+				{Amount: 400, EligiblePlayers: []string{"b", "c"}, HighShares: map[string]int{"b": 400}},
+			},
+			want: map[string]int{"c": 300},
+		},
+		{
+			name:   "поделенный банк",
+			userID: "a",
+			pots: []PotSettlement{
+				{Amount: 300, EligiblePlayers: []string{"a", "b", "c"}, HighShares: map[string]int{"b": 150, "c": 150}},
+			},
+			want: map[string]int{"b": 150, "c": 150},
+		},
+		{
+			name:   "хай-лоу: выбивают победители обеих половин",
+			userID: "a",
+			pots: []PotSettlement{
+				{
+					Amount:          200,
+					EligiblePlayers: []string{"a", "b", "c"},
+					HighShares:      map[string]int{"b": 100},
+					LowShares:       map[string]int{"c": 100},
+				},
+			},
+			want: map[string]int{"b": 100, "c": 100},
+		},
+		{
+			name:   "участник выиграл свой банк",
+			userID: "a",
+			pots: []PotSettlement{
+				{Amount: 300, EligiblePlayers: []string{"a", "b", "c"}, HighShares: map[string]int{"a": 300}},
+				{Amount: 400, EligiblePlayers: []string{"b", "c"}, HighShares: map[string]int{"b": 400}},
+			},
+			want: map[string]int{},
+		},
+		{
+			name:   "возврат неуравненной ставки не выбивает",
+			userID: "a",
+			pots: []PotSettlement{
+				{Amount: 100, EligiblePlayers: []string{"b"}, HighShares: map[string]int{"b": 100}},
+			},
+			want: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KnockoutShares(tt.userID, tt.pots); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KnockoutShares(%s) = %v, want %v", tt.userID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitBounty(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounty int
+		shares map[string]int
+		want   map[string]int
+	}{
+		{"один выбивший", 100, map[string]int{"b": 300}, map[string]int{"b": 100}},
+		{"пропорционально выигрышу", 100, map[string]int{"b": 100, "c": 200}, map[string]int{"b": 33, "c": 67}},
+		{"поровну с остатком", 101, map[string]int{"b": 150, "c": 150}, map[string]int{"b": 51, "c": 50}},
+		{"без баунти", 0, map[string]int{"b": 150}, map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitBounty(tt.bounty, tt.shares); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitBounty(%d, %v) = %v, want %v", tt.bounty, tt.shares, got, tt.want)
+			}
+		})
+	}
+}
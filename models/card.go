@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+)
+
+// Card - разобранная карта
+// Строковое представление карты: достоинство + масть, например "AH", "TD", "2C"
+type Card struct {
+	// Достоинство: 2-14 (11 - валет, 12 - дама, 13 - король, 14 - туз)
+	Rank int
+
+	// Масть: "H", "D", "C", "S"
+	Suit string
+}
+
+// Достоинства карт в числовом виде
+const (
+	RankTen   = 10
+	RankJack  = 11
+	RankQueen = 12
+	RankKing  = 13
+	RankAce   = 14
+)
+
+// rankSymbols - символы достоинств по возрастанию (индекс = достоинство - 2)
+const rankSymbols = "23456789TJQKA"
+
+// cardRanks - соответствие символа достоинства его числовому значению
+var cardRanks = map[byte]int{
+	'2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
+	'T': RankTen, 'J': RankJack, 'Q': RankQueen, 'K': RankKing, 'A': RankAce,
+}
+
+// ParseCard - разбирает строку карты ("AH") в Card
+func ParseCard(card string) (Card, error) {
+	if len(card) != 2 {
+		return Card{}, fmt.Errorf("некорректная карта: %q", card)
+	}
+
+	rank, ok := cardRanks[card[0]]
+	if !ok {
+		return Card{}, fmt.Errorf("некорректное достоинство карты: %q", card)
+	}
+
+	suit := card[1:]
+	switch suit {
+	case "H", "D", "C", "S":
+	default:
+		return Card{}, fmt.Errorf("некорректная масть карты: %q", card)
+	}
+
+	return Card{Rank: rank, Suit: suit}, nil
+}
+
+// ParseCards - разбирает список карт
+func ParseCards(cards []string) ([]Card, error) {
+	parsed := make([]Card, len(cards))
+	for i, card := range cards {
+		c, err := ParseCard(card)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = c
+	}
+	return parsed, nil
+}
+
+// String - возвращает строковое представление карты ("AH")
+func (c Card) String() string {
+	if c.Rank < 2 || c.Rank > RankAce {
+		return "?" + c.Suit
+	}
+	return string(rankSymbols[c.Rank-2]) + c.Suit
+}
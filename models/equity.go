@@ -0,0 +1,86 @@
+package models
+
+// Ограничения расчета эквити
+const (
+	// MinEquityPlayers, MaxEquityPlayers - допустимое количество рук в расчете
+	MinEquityPlayers = 2
+	MaxEquityPlayers = 10
+
+	// ExactEquityBoards - до этого количества возможных досок эквити считается точным перебором,
+	// иначе - методом Монте-Карло
+	ExactEquityBoards = 50000
+
+	// DefaultEquityIterations, MaxEquityIterations - количество прогонов Монте-Карло
+	// по умолчанию и максимальное
+	DefaultEquityIterations = 20000
+	MaxEquityIterations     = 1000000
+)
+
+// EquityPlayer - известная рука игрока в расчете эквити
+type EquityPlayer struct {
+	// ID игрока (необязателен для расчета по произвольным картам)
+	UserID string `json:"user_id,omitempty"`
+
+	// Закрытые карты игрока в формате колоды ("AH", "TD")
+	Cards []string `json:"cards"`
+}
+
+// EquityRequest - запрос на расчет эквити
+type EquityRequest struct {
+	// Разновидность покера (с общими картами: холдем, омаха, омаха хай-лоу, шорт-дек)
+	GameType GameType `json:"game_type"`
+
+	// Руки игроков (от 2 до 10)
+	Players []EquityPlayer `json:"players"`
+
+	// Открытые общие карты (0, 3, 4 или 5)
+	Board []string `json:"board,omitempty"`
+
+	// Вышедшие из игры карты (сброшенные руки, сожженные карты), которые не могут прийти на доску
+	Dead []string `json:"dead,omitempty"`
+
+	// Количество прогонов Монте-Карло (0 - DefaultEquityIterations)
+	Iterations int `json:"iterations,omitempty"`
+}
+
+// PlayerEquity - эквити руки игрока
+type PlayerEquity struct {
+	UserID string   `json:"user_id,omitempty"`
+	Cards  []string `json:"cards"`
+
+	// Вероятность забрать банк целиком и вероятность разделить банк
+	Win float64 `json:"win"`
+	Tie float64 `json:"tie"`
+
+	// Ожидаемая доля банка (с учетом дележа и хай-лоу)
+	Equity float64 `json:"equity"`
+}
+
+// EquityResult - результат расчета эквити
+type EquityResult struct {
+	// Эквити каждой руки в порядке запроса
+	Players []PlayerEquity `json:"players"`
+
+	// Количество просчитанных досок
+	Boards int `json:"boards"`
+
+	// Точный перебор (false - Монте-Карло)
+	Exact bool `json:"exact"`
+}
+
+// CountCombinations - количество сочетаний из n по k
+// Ограничивается значением limit+1, чтобы не переполняться на больших колодах
+func CountCombinations(n, k, limit int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+
+	count := 1
+	for i := 1; i <= k; i++ {
+		count = count * (n - k + i) / i
+		if count > limit {
+			return limit + 1
+		}
+	}
+	return count
+}
@@ -26,6 +26,21 @@ const (
 	// GamePhaseRiver - фаза после раздачи пятой общей карты
 	GamePhaseRiver GamePhase = "river"
 
+	// GamePhaseThirdStreet - стад: две закрытые и одна открытая карта, торговлю открывает bring-in
+	GamePhaseThirdStreet GamePhase = "third_street"
+
+	// GamePhaseFourthStreet - стад: четвертая карта (открытая)
+	GamePhaseFourthStreet GamePhase = "fourth_street"
+
+	// GamePhaseFifthStreet - стад: пятая карта (открытая), ставки переходят на большую ставку
+	GamePhaseFifthStreet GamePhase = "fifth_street"
+
+	// GamePhaseSixthStreet - стад: шестая карта (открытая)
+	GamePhaseSixthStreet GamePhase = "sixth_street"
+
+	// GamePhaseSeventhStreet - стад: седьмая карта (закрытая)
+	GamePhaseSeventhStreet GamePhase = "seventh_street"
+
 	// GamePhaseShowdown - вскрытие карт и определение победителя
 	GamePhaseShowdown GamePhase = "showdown"
 
@@ -33,6 +48,9 @@ const (
 	GamePhaseFinished GamePhase = "finished"
 )
 
+// MaxCommunityCards - количество общих карт на полной доске (флоп, терн и ривер)
+const MaxCommunityCards = 5
+
 // Game - полная структура игры
 type Game struct {
 	// Уникальный ID игры
@@ -53,6 +71,12 @@ type Game struct {
 	// Текущая ставка в раунде (для call)
 	CurrentBet int
 
+	// Размер последнего повышения в раунде (минимальный шаг следующего рейза)
+	LastRaise int
+
+	// Количество ставок и повышений в текущем раунде торговли (лимит повышений фикс-лимита)
+	RaiseCount int
+
 	// Позиция дилера (seat number)
 	DealerPosition int
 
@@ -62,6 +86,9 @@ type Game struct {
 	// Позиция большого блайнда
 	BigBlindPosition *int
 
+	// Позиция последнего стрэддла (ходит последним на префлопе)
+	StraddlePosition *int
+
 	// Позиция текущего игрока (чей ход)
 	CurrentPlayerPosition *int
 
@@ -69,13 +96,32 @@ type Game struct {
 	RoundNumber int
 
 	// Общие карты на столе (community cards)
+	// При нескольких прогонах доски - карты первого прогона
 	CommunityCards []string
 
+	// Сколько раз прогоняется доска (run it twice), 0 или 1 - один раз
+	RunCount int
+
+	// Доски всех прогонов (заполняются только при нескольких прогонах)
+	Boards [][]string
+
+	// Текущая раздача - бомб-пот (все ставят анте, торговля начинается с флопа)
+	IsBombPot bool
+
+	// Бомб-пот назначен владельцем или голосованием на следующую раздачу
+	BombPotPending bool
+
 	// Время начала игры
 	StartedAt *time.Time
 
 	// Боковые банки (side pots) для all-in ситуаций
 	SidePots []SidePot
+
+	// Смешанная игра: текущая разновидность, ее индекс в ротации
+	// и сколько раздач осталось до смены (включая текущую)
+	CurrentVariant     GameType
+	VariantIndex       int
+	HandsUntilRotation int
 }
 
 // GameState - структура состояния игры из Redis
@@ -85,13 +131,23 @@ type GameState struct {
 	Phase                 GamePhase `json:"phase"`
 	Pot                   string    `json:"pot"`
 	CurrentBet            string    `json:"current_bet"`
+	LastRaise             string    `json:"last_raise"`
+	RaiseCount            string    `json:"raise_count"`
 	DealerPosition        string    `json:"dealer_position"`
 	SmallBlindPosition    string    `json:"small_blind_position"`
 	BigBlindPosition      string    `json:"big_blind_position"`
+	StraddlePosition      string    `json:"straddle_position"`
 	CurrentPlayerPosition string    `json:"current_player_position"`
 	RoundNumber           string    `json:"round_number"`
 	CommunityCards        string    `json:"community_cards"` // JSON массив
-	StartedAt             string    `json:"started_at"`      // ISO 8601
+	RunCount              string    `json:"run_count"`
+	Boards                string    `json:"boards"` // JSON массив массивов
+	BombPot               string    `json:"bomb_pot"`
+	BombPotPending        string    `json:"bomb_pot_pending"`
+	StartedAt             string    `json:"started_at"` // ISO 8601
+	CurrentVariant        string    `json:"current_variant"`
+	VariantIndex          string    `json:"variant_index"`
+	HandsUntilRotation    string    `json:"hands_until_rotation"`
 }
 
 // SidePot - структура для бокового банка (когда игрок идет all-in)
@@ -108,11 +164,18 @@ func NewGameFromRedis(data map[string]string) (*Game, error) {
 	// Парсим числовые поля
 	pot, _ := strconv.Atoi(data["pot"])
 	currentBet, _ := strconv.Atoi(data["current_bet"])
+	lastRaise, _ := strconv.Atoi(data["last_raise"])
+	raiseCount, _ := strconv.Atoi(data["raise_count"])
+	variantIndex, _ := strconv.Atoi(data["variant_index"])
+	handsUntilRotation, _ := strconv.Atoi(data["hands_until_rotation"])
 	dealerPosition, _ := strconv.Atoi(data["dealer_position"])
 	roundNumber, _ := strconv.Atoi(data["round_number"])
+	runCount, _ := strconv.Atoi(data["run_count"])
+	isBombPot := data["bomb_pot"] == "true" || data["bomb_pot"] == "1"
+	bombPotPending := data["bomb_pot_pending"] == "true" || data["bomb_pot_pending"] == "1"
 
 	// Парсим nullable позиции
-	var smallBlindPos, bigBlindPos, currentPlayerPos *int
+	var smallBlindPos, bigBlindPos, straddlePos, currentPlayerPos *int
 
 	if data["small_blind_position"] != "" && data["small_blind_position"] != "null" {
 		pos, _ := strconv.Atoi(data["small_blind_position"])
@@ -124,6 +187,11 @@ func NewGameFromRedis(data map[string]string) (*Game, error) {
 		bigBlindPos = &pos
 	}
 
+	if data["straddle_position"] != "" && data["straddle_position"] != "null" {
+		pos, _ := strconv.Atoi(data["straddle_position"])
+		straddlePos = &pos
+	}
+
 	if data["current_player_position"] != "" && data["current_player_position"] != "null" {
 		pos, _ := strconv.Atoi(data["current_player_position"])
 		currentPlayerPos = &pos
@@ -135,6 +203,12 @@ func NewGameFromRedis(data map[string]string) (*Game, error) {
 		json.Unmarshal([]byte(data["community_cards"]), &communityCards)
 	}
 
+	// Парсим доски прогонов из JSON
+	var boards [][]string
+	if data["boards"] != "" {
+		json.Unmarshal([]byte(data["boards"]), &boards)
+	}
+
 	// Парсим started_at
 	var startedAt *time.Time
 	if data["started_at"] != "" && data["started_at"] != "null" {
@@ -149,13 +223,23 @@ func NewGameFromRedis(data map[string]string) (*Game, error) {
 		Phase:                 GamePhase(data["phase"]),
 		Pot:                   pot,
 		CurrentBet:            currentBet,
+		LastRaise:             lastRaise,
+		RaiseCount:            raiseCount,
 		DealerPosition:        dealerPosition,
 		SmallBlindPosition:    smallBlindPos,
 		BigBlindPosition:      bigBlindPos,
+		StraddlePosition:      straddlePos,
 		CurrentPlayerPosition: currentPlayerPos,
 		RoundNumber:           roundNumber,
 		CommunityCards:        communityCards,
+		RunCount:              runCount,
+		Boards:                boards,
+		IsBombPot:             isBombPot,
+		BombPotPending:        bombPotPending,
 		StartedAt:             startedAt,
+		CurrentVariant:        GameType(data["current_variant"]),
+		VariantIndex:          variantIndex,
+		HandsUntilRotation:    handsUntilRotation,
 	}, nil
 }
 
@@ -166,8 +250,17 @@ func (g *Game) ToRedisHash() map[string]interface{} {
 		"phase":           string(g.Phase),
 		"pot":             g.Pot,
 		"current_bet":     g.CurrentBet,
+		"last_raise":      g.LastRaise,
+		"raise_count":     g.RaiseCount,
 		"dealer_position": g.DealerPosition,
 		"round_number":    g.RoundNumber,
+		"run_count":       g.RunCount,
+		"bomb_pot":        g.IsBombPot,
+
+		"current_variant":      string(g.CurrentVariant),
+		"variant_index":        g.VariantIndex,
+		"hands_until_rotation": g.HandsUntilRotation,
+		"bomb_pot_pending":     g.BombPotPending,
 	}
 
 	// Добавляем nullable поля
@@ -183,6 +276,12 @@ func (g *Game) ToRedisHash() map[string]interface{} {
 		hash["big_blind_position"] = ""
 	}
 
+	if g.StraddlePosition != nil {
+		hash["straddle_position"] = *g.StraddlePosition
+	} else {
+		hash["straddle_position"] = ""
+	}
+
 	if g.CurrentPlayerPosition != nil {
 		hash["current_player_position"] = *g.CurrentPlayerPosition
 	} else {
@@ -193,6 +292,10 @@ func (g *Game) ToRedisHash() map[string]interface{} {
 	cardsJSON, _ := json.Marshal(g.CommunityCards)
 	hash["community_cards"] = string(cardsJSON)
 
+	// Доски прогонов как JSON
+	boardsJSON, _ := json.Marshal(g.Boards)
+	hash["boards"] = string(boardsJSON)
+
 	// Started at
 	if g.StartedAt != nil {
 		hash["started_at"] = g.StartedAt.Format(time.RFC3339)
@@ -220,17 +323,53 @@ func (g *Game) IsFinished() bool {
 	return g.Phase == GamePhaseFinished
 }
 
+// IsPastThirdStreet - проверяет, прошла ли раздача стада дальше третьей улицы
+// Вскрытие в стаде наступает только после седьмой улицы или после олл-ина с раздачей оставшихся улиц
+func (g *Game) IsPastThirdStreet() bool {
+	switch g.Phase {
+	case GamePhaseFourthStreet, GamePhaseFifthStreet, GamePhaseSixthStreet, GamePhaseSeventhStreet,
+		GamePhaseShowdown, GamePhaseFinished:
+		return true
+	default:
+		return false
+	}
+}
+
+// PreflopLastToAct - место игрока, который ходит последним на префлопе:
+// последний стрэддл, а без стрэддла - большой блайнд
+func (g *Game) PreflopLastToAct() *int {
+	if g.StraddlePosition != nil {
+		return g.StraddlePosition
+	}
+	return g.BigBlindPosition
+}
+
 // CanTransitionTo - проверяет, можно ли перейти к указанной фазе
 func (g *Game) CanTransitionTo(nextPhase GamePhase) bool {
 	// Определяем допустимые переходы между фазами
 	validTransitions := map[GamePhase][]GamePhase{
-		GamePhaseWaiting:  {GamePhasePreFlop},
+		GamePhaseWaiting:  {GamePhasePreFlop, GamePhaseThirdStreet},
 		GamePhasePreFlop:  {GamePhaseFlop, GamePhaseShowdown, GamePhaseFinished},
 		GamePhaseFlop:     {GamePhaseTurn, GamePhaseShowdown, GamePhaseFinished},
 		GamePhaseTurn:     {GamePhaseRiver, GamePhaseShowdown, GamePhaseFinished},
 		GamePhaseRiver:    {GamePhaseShowdown, GamePhaseFinished},
-		GamePhaseShowdown: {GamePhaseFinished, GamePhasePreFlop},
-		GamePhaseFinished: {GamePhaseWaiting, GamePhasePreFlop},
+		GamePhaseShowdown: {GamePhaseFinished, GamePhasePreFlop, GamePhaseThirdStreet},
+		GamePhaseFinished: {GamePhaseWaiting, GamePhasePreFlop, GamePhaseThirdStreet},
+
+		// Улицы стада
+		GamePhaseThirdStreet:   {GamePhaseFourthStreet, GamePhaseShowdown, GamePhaseFinished},
+		GamePhaseFourthStreet:  {GamePhaseFifthStreet, GamePhaseShowdown, GamePhaseFinished},
+		GamePhaseFifthStreet:   {GamePhaseSixthStreet, GamePhaseShowdown, GamePhaseFinished},
+		GamePhaseSixthStreet:   {GamePhaseSeventhStreet, GamePhaseShowdown, GamePhaseFinished},
+		GamePhaseSeventhStreet: {GamePhaseShowdown, GamePhaseFinished},
+	}
+
+	// Бомб-пот пропускает префлоп: все ставят анте, и раздача начинается с флопа
+	if g.IsBombPot && nextPhase == GamePhaseFlop {
+		switch g.Phase {
+		case GamePhaseWaiting, GamePhaseShowdown, GamePhaseFinished:
+			return true
+		}
 	}
 
 	allowedPhases, exists := validTransitions[g.Phase]
@@ -251,6 +390,10 @@ func (g *Game) CanTransitionTo(nextPhase GamePhase) bool {
 func (g *Game) NextPhase() GamePhase {
 	switch g.Phase {
 	case GamePhaseWaiting:
+		// Бомб-пот начинается сразу с флопа
+		if g.IsBombPot {
+			return GamePhaseFlop
+		}
 		return GamePhasePreFlop
 	case GamePhasePreFlop:
 		return GamePhaseFlop
@@ -260,6 +403,16 @@ func (g *Game) NextPhase() GamePhase {
 		return GamePhaseRiver
 	case GamePhaseRiver:
 		return GamePhaseShowdown
+	case GamePhaseThirdStreet:
+		return GamePhaseFourthStreet
+	case GamePhaseFourthStreet:
+		return GamePhaseFifthStreet
+	case GamePhaseFifthStreet:
+		return GamePhaseSixthStreet
+	case GamePhaseSixthStreet:
+		return GamePhaseSeventhStreet
+	case GamePhaseSeventhStreet:
+		return GamePhaseShowdown
 	case GamePhaseShowdown:
 		return GamePhaseFinished
 	case GamePhaseFinished:
@@ -332,6 +485,25 @@ func (g *Game) GetCommunityCardsCount() int {
 // ClearCommunityCards - очищает общие карты (начало новой раздачи)
 func (g *Game) ClearCommunityCards() {
 	g.CommunityCards = []string{}
+	g.Boards = nil
+	g.RunCount = 0
+}
+
+// GetRunCount - сколько раз прогоняется доска (не меньше одного)
+func (g *Game) GetRunCount() int {
+	if g.RunCount < 1 {
+		return 1
+	}
+	return g.RunCount
+}
+
+// GetBoards - доски всех прогонов
+// Без нескольких прогонов - единственная доска из общих карт
+func (g *Game) GetBoards() [][]string {
+	if len(g.Boards) > 1 {
+		return g.Boards
+	}
+	return [][]string{g.CommunityCards}
 }
 
 // === МЕТОДЫ ДЛЯ РАБОТЫ С РАУНДАМИ ===
@@ -0,0 +1,101 @@
+package models
+
+// GameType - разновидность покера
+type GameType string
+
+// Константы разновидностей покера
+const (
+	// GameTypeHoldem - техасский холдем (2 закрытые карты, любые 5 из 7)
+	GameTypeHoldem GameType = "holdem"
+
+	// GameTypeOmaha - пот-лимит омаха (4 закрытые карты, ровно 2 из руки и 3 со стола)
+	GameTypeOmaha GameType = "omaha"
+
+	// GameTypeOmahaHiLo - омаха хай-лоу 8 or better (банк делится между старшей и младшей рукой)
+	GameTypeOmahaHiLo GameType = "omaha_hilo"
+
+	// GameTypeShortDeck - шорт-дек холдем (колода 36 карт без двоек-пятерок,
+	// флеш старше фулл-хауса, A-6-7-8-9 - стрит)
+	GameTypeShortDeck GameType = "short_deck"
+
+	// GameTypeStud - семикарточный стад (без общих карт, у каждого игрока открытые и закрытые карты)
+	GameTypeStud GameType = "stud"
+
+	// GameTypeRazz - рэзз (семикарточный стад на младшую руку от туза до пятерки)
+	GameTypeRazz GameType = "razz"
+
+	// GameTypeStudHiLo - стад хай-лоу 8 or better (банк делится между старшей и младшей рукой)
+	GameTypeStudHiLo GameType = "stud_hilo"
+)
+
+// ShortDeckMinRank - младшее достоинство карты в колоде шорт-дека (шестерка)
+const ShortDeckMinRank = 6
+
+// HoleCardsCount - возвращает количество закрытых карт, которые получает игрок при первой раздаче
+// В стаде кроме двух закрытых игрок получает одну открытую карту (см. StudStreetCards)
+func (gt GameType) HoleCardsCount() int {
+	switch gt {
+	case GameTypeOmaha, GameTypeOmahaHiLo:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// IsValid - проверяет, что разновидность покера поддерживается
+func (gt GameType) IsValid() bool {
+	switch gt {
+	case GameTypeHoldem, GameTypeOmaha, GameTypeOmahaHiLo, GameTypeShortDeck, GameTypeStud, GameTypeRazz, GameTypeStudHiLo:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesOmahaRules - проверяет, составляется ли рука ровно из 2 карт игрока и 3 общих карт
+func (gt GameType) UsesOmahaRules() bool {
+	return gt == GameTypeOmaha || gt == GameTypeOmahaHiLo
+}
+
+// IsHiLo - проверяет, делится ли банк между старшей и младшей рукой
+func (gt GameType) IsHiLo() bool {
+	return gt == GameTypeOmahaHiLo || gt == GameTypeStudHiLo
+}
+
+// IsStud - проверяет, играется ли разновидность по правилам стада (без общих карт)
+func (gt GameType) IsStud() bool {
+	return gt == GameTypeStud || gt == GameTypeRazz || gt == GameTypeStudHiLo
+}
+
+// IsRazz - проверяет, выигрывает ли банк младшая рука от туза до пятерки (рэзз)
+func (gt GameType) IsRazz() bool {
+	return gt == GameTypeRazz
+}
+
+// IsShortDeck - проверяет, играется ли разновидность укороченной колодой
+func (gt GameType) IsShortDeck() bool {
+	return gt == GameTypeShortDeck
+}
+
+// MinCardRank - младшее достоинство карты в колоде разновидности
+func (gt GameType) MinCardRank() int {
+	if gt.IsShortDeck() {
+		return ShortDeckMinRank
+	}
+	return 2
+}
+
+// DeckSize - количество карт в колоде разновидности (4 масти × число достоинств)
+func (gt GameType) DeckSize() int {
+	return 4 * (RankAce - gt.MinCardRank() + 1)
+}
+
+// ParseGameType - парсит строку в GameType
+// Пустое или неизвестное значение - холдем (так работали все комнаты до появления поля)
+func ParseGameType(gameType string) GameType {
+	gt := GameType(gameType)
+	if !gt.IsValid() {
+		return GameTypeHoldem
+	}
+	return gt
+}
@@ -0,0 +1,265 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HandCategory - комбинация покерной руки (от старшей карты до стрит-флеша)
+type HandCategory int
+
+// Константы комбинаций (по возрастанию силы)
+const (
+	HandHighCard HandCategory = iota
+	HandPair
+	HandTwoPair
+	HandThreeOfAKind
+	HandStraight
+	HandFlush
+	HandFullHouse
+	HandFourOfAKind
+	HandStraightFlush
+)
+
+// handCategoryNames - названия комбинаций для логов и истории раздач
+var handCategoryNames = map[HandCategory]string{
+	HandHighCard:      "high_card",
+	HandPair:          "pair",
+	HandTwoPair:       "two_pair",
+	HandThreeOfAKind:  "three_of_a_kind",
+	HandStraight:      "straight",
+	HandFlush:         "flush",
+	HandFullHouse:     "full_house",
+	HandFourOfAKind:   "four_of_a_kind",
+	HandStraightFlush: "straight_flush",
+}
+
+// String - возвращает название комбинации
+func (hc HandCategory) String() string {
+	return handCategoryNames[hc]
+}
+
+// HandValue - сила руки из пяти карт
+type HandValue struct {
+	// Комбинация
+	Category HandCategory `json:"category"`
+
+	// Достоинства для сравнения рук одной комбинации (по убыванию значимости)
+	// Например, для фулл-хауса KKK77: [13, 7]
+	Ranks []int `json:"ranks"`
+
+	// Пять карт, составляющих руку
+	Cards []string `json:"cards"`
+
+	// Рука оценена по правилам шорт-дека (флеш старше фулл-хауса)
+	ShortDeck bool `json:"short_deck,omitempty"`
+}
+
+// strength - старшинство комбинации с учетом правил шорт-дека
+// В шорт-деке флеш собрать сложнее, чем фулл-хаус, поэтому они меняются местами
+func (h HandValue) strength() int {
+	if h.ShortDeck {
+		switch h.Category {
+		case HandFlush:
+			return int(HandFullHouse)
+		case HandFullHouse:
+			return int(HandFlush)
+		}
+	}
+	return int(h.Category)
+}
+
+// Compare - сравнивает две руки
+// Возвращает 1 если рука h сильнее, -1 если слабее, 0 при равенстве
+func (h HandValue) Compare(other HandValue) int {
+	if h.strength() != other.strength() {
+		if h.strength() > other.strength() {
+			return 1
+		}
+		return -1
+	}
+
+	for i := 0; i < len(h.Ranks) && i < len(other.Ranks); i++ {
+		if h.Ranks[i] != other.Ranks[i] {
+			if h.Ranks[i] > other.Ranks[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	return 0
+}
+
+// EvaluateFive - определяет силу руки ровно из пяти карт
+func EvaluateFive(cards []Card) HandValue {
+	return evaluateFive(cards, false)
+}
+
+// EvaluateShortDeckFive - определяет силу руки из пяти карт по правилам шорт-дека
+// Туз замыкает младший стрит A-6-7-8-9, флеш старше фулл-хауса
+func EvaluateShortDeckFive(cards []Card) HandValue {
+	return evaluateFive(cards, true)
+}
+
+// evaluateFive - определяет силу руки ровно из пяти карт
+func evaluateFive(cards []Card, shortDeck bool) HandValue {
+	counts := make(map[int]int, 5)
+	isFlush := true
+	for i, c := range cards {
+		counts[c.Rank]++
+		if i > 0 && c.Suit != cards[0].Suit {
+			isFlush = false
+		}
+	}
+
+	// Группы одинаковых достоинств: сначала по размеру группы, затем по достоинству
+	groups := make([]int, 0, len(counts))
+	for rank := range counts {
+		groups = append(groups, rank)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if counts[groups[i]] != counts[groups[j]] {
+			return counts[groups[i]] > counts[groups[j]]
+		}
+		return groups[i] > groups[j]
+	})
+
+	straightHigh := 0
+	if len(groups) == 5 {
+		sorted := append([]int(nil), groups...)
+		sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+		if sorted[0]-sorted[4] == 4 {
+			straightHigh = sorted[0]
+		} else if sorted[0] == RankAce && sorted[1] == 5 {
+			// Колесо: A-2-3-4-5, туз играет как единица
+			straightHigh = 5
+		} else if shortDeck && sorted[0] == RankAce && sorted[1] == 9 && sorted[4] == ShortDeckMinRank {
+			// Младший стрит шорт-дека: A-6-7-8-9, туз играет вместо пятерки
+			straightHigh = 9
+		}
+	}
+
+	value := HandValue{Cards: cardStrings(cards), ShortDeck: shortDeck}
+
+	switch {
+	case straightHigh > 0 && isFlush:
+		value.Category, value.Ranks = HandStraightFlush, []int{straightHigh}
+	case counts[groups[0]] == 4:
+		value.Category, value.Ranks = HandFourOfAKind, groups
+	case counts[groups[0]] == 3 && len(groups) == 2:
+		value.Category, value.Ranks = HandFullHouse, groups
+	case isFlush:
+		value.Category, value.Ranks = HandFlush, groups
+	case straightHigh > 0:
+		value.Category, value.Ranks = HandStraight, []int{straightHigh}
+	case counts[groups[0]] == 3:
+		value.Category, value.Ranks = HandThreeOfAKind, groups
+	case counts[groups[0]] == 2 && len(groups) == 3:
+		value.Category, value.Ranks = HandTwoPair, groups
+	case counts[groups[0]] == 2:
+		value.Category, value.Ranks = HandPair, groups
+	default:
+		value.Category, value.Ranks = HandHighCard, groups
+	}
+
+	return value
+}
+
+// EvaluateBest - лучшая рука из пяти карт среди любых карт (холдем: 2 карты игрока + 5 общих)
+func EvaluateBest(cards []Card) (HandValue, error) {
+	return evaluateBest(cards, EvaluateFive)
+}
+
+// EvaluateBestShortDeck - лучшая рука из пяти карт по правилам шорт-дека
+func EvaluateBestShortDeck(cards []Card) (HandValue, error) {
+	return evaluateBest(cards, EvaluateShortDeckFive)
+}
+
+// evaluateBest - лучшая рука из пяти карт среди любых карт с заданной функцией оценки
+func evaluateBest(cards []Card, evaluate func([]Card) HandValue) (HandValue, error) {
+	if len(cards) < 5 {
+		return HandValue{}, fmt.Errorf("для оценки руки нужно минимум 5 карт, получено %d", len(cards))
+	}
+
+	var best HandValue
+	found := false
+	for _, combo := range Combinations(len(cards), 5) {
+		value := evaluate(pickCards(cards, combo))
+		if !found || value.Compare(best) > 0 {
+			best, found = value, true
+		}
+	}
+
+	return best, nil
+}
+
+// EvaluateOmaha - лучшая рука в омахе: ровно 2 карты игрока и ровно 3 общие карты
+func EvaluateOmaha(hole, board []Card) (HandValue, error) {
+	if len(hole) < 2 || len(board) < 3 {
+		return HandValue{}, fmt.Errorf("для оценки руки в омахе нужно минимум 2 карты игрока и 3 общие карты")
+	}
+
+	var best HandValue
+	found := false
+	for _, holeCombo := range Combinations(len(hole), 2) {
+		for _, boardCombo := range Combinations(len(board), 3) {
+			five := append(pickCards(hole, holeCombo), pickCards(board, boardCombo)...)
+			value := EvaluateFive(five)
+			if !found || value.Compare(best) > 0 {
+				best, found = value, true
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// Combinations - возвращает все сочетания k индексов из n (в лексикографическом порядке)
+func Combinations(n, k int) [][]int {
+	if k > n || k <= 0 {
+		return nil
+	}
+
+	var result [][]int
+	combo := make([]int, k)
+	for i := range combo {
+		combo[i] = i
+	}
+
+	for {
+		result = append(result, append([]int(nil), combo...))
+
+		// Ищем самый правый индекс, который можно увеличить
+		i := k - 1
+		for i >= 0 && combo[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return result
+		}
+
+		combo[i]++
+		for j := i + 1; j < k; j++ {
+			combo[j] = combo[j-1] + 1
+		}
+	}
+}
+
+// pickCards - выбирает карты по индексам
+func pickCards(cards []Card, indexes []int) []Card {
+	picked := make([]Card, len(indexes))
+	for i, idx := range indexes {
+		picked[i] = cards[idx]
+	}
+	return picked
+}
+
+// cardStrings - преобразует карты в строковое представление
+func cardStrings(cards []Card) []string {
+	result := make([]string, len(cards))
+	for i, c := range cards {
+		result[i] = c.String()
+	}
+	return result
+}
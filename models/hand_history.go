@@ -0,0 +1,106 @@
+package models
+
+// ShowdownHand - рука игрока на вскрытии
+type ShowdownHand struct {
+	// ID игрока
+	UserID string `json:"user_id"`
+
+	// Закрытые карты игрока
+	Cards []string `json:"cards"`
+
+	// Старшая рука
+	High HandValue `json:"high"`
+
+	// Младшая рука (только в хай-лоу, nil если младшая рука не прошла)
+	Low *LowHandValue `json:"low,omitempty"`
+}
+
+// HandHistoryPlayer - участник раздачи в истории
+type HandHistoryPlayer struct {
+	// ID игрока
+	UserID string `json:"user_id"`
+
+	// Место за столом
+	Seat int `json:"seat"`
+
+	// Закрытые карты (показываются только для дошедших до вскрытия)
+	Cards []string `json:"cards,omitempty"`
+
+	// Открытые карты (стад, видны всем и для сбросивших игроков)
+	UpCards []string `json:"up_cards,omitempty"`
+
+	// Сколько игрок поставил за раздачу
+	TotalBet int `json:"total_bet"`
+
+	// Стрэддл, поставленный игроком до раздачи
+	Straddle int `json:"straddle,omitempty"`
+
+	// Сбросил ли игрок карты
+	Folded bool `json:"folded"`
+
+	// Старшая и младшая рука на вскрытии
+	HighHand *HandValue    `json:"high_hand,omitempty"`
+	LowHand  *LowHandValue `json:"low_hand,omitempty"`
+
+	// Общий выигрыш игрока в раздаче
+	Won int `json:"won"`
+
+	// Эквити игрока в момент олл-ина до ривера и ожидаемый по нему выигрыш
+	// (заполняются, только если раздача закончилась олл-ином до ривера)
+	AllInEquity float64 `json:"all_in_equity,omitempty"`
+	ExpectedWon int     `json:"expected_won,omitempty"`
+}
+
+// HandHistoryRun - один прогон доски в истории раздачи
+type HandHistoryRun struct {
+	// Номер прогона (с единицы)
+	Run int `json:"run"`
+
+	// Доска прогона
+	Board []string `json:"board"`
+
+	// Старшие и младшие руки игроков на этой доске (ключ - ID игрока)
+	Hands map[string]ShowdownHand `json:"hands"`
+
+	// Итог розыгрыша доли каждого банка в этом прогоне
+	Pots []PotSettlement `json:"pots"`
+
+	// Выигрыш каждого игрока в этом прогоне
+	Payouts map[string]int `json:"payouts"`
+}
+
+// HandHistory - история одной раздачи
+type HandHistory struct {
+	GameID      string   `json:"game_id"`
+	ClubID      string   `json:"club_id"`
+	RoomID      string   `json:"room_id"`
+	RoundNumber int      `json:"round_number"`
+	GameType    GameType `json:"game_type"`
+
+	// ID турнира, если раздача сыграна за турнирным столом (фишки турнирные, а не денежные)
+	TournamentID string `json:"tournament_id,omitempty"`
+
+	// Общие карты (при нескольких прогонах - доска первого прогона)
+	Board []string `json:"board"`
+
+	// Прогоны доски (заполняются, только если доска прогонялась несколько раз)
+	Runs []HandHistoryRun `json:"runs,omitempty"`
+
+	// Открытые общие карты в момент олл-ина (заполняется, только если олл-ин был до ривера)
+	AllInBoard []string `json:"all_in_board,omitempty"`
+
+	// Участники раздачи
+	Players []HandHistoryPlayer `json:"players"`
+
+	// Итог розыгрыша каждого банка (старшая и младшая половины)
+	Pots []PotSettlement `json:"pots"`
+
+	// Действия игроков в порядке совершения
+	Actions []HandAction `json:"actions,omitempty"`
+
+	// Общий рейк раздачи
+	Rake int `json:"rake"`
+
+	// Время окончания раздачи (Unix timestamp)
+	Timestamp int64 `json:"timestamp"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustParseCards(t *testing.T, cards ...string) []Card {
+	t.Helper()
+	parsed, err := ParseCards(cards)
+	if err != nil {
+		t.Fatalf("ParseCards(%v) error = %v", cards, err)
+	}
+	return parsed
+}
+
+func TestEvaluateFive(t *testing.T) {
+	tests := []struct {
+		name      string
+		cards     []string
+		shortDeck bool
+		category  HandCategory
+		ranks     []int
+	}{
+		{"старшая карта", []string{"AH", "JD", "9C", "6S", "2H"}, false, HandHighCard, []int{14, 11, 9, 6, 2}},
+		{"пара", []string{"KH", "KD", "9C", "6S", "2H"}, false, HandPair, []int{13, 9, 6, 2}},
+		{"две пары", []string{"KH", "KD", "9C", "9S", "2H"}, false, HandTwoPair, []int{13, 9, 2}},
+		{"сет", []string{"7H", "7D", "7C", "KS", "2H"}, false, HandThreeOfAKind, []int{7, 13, 2}},
+		{"стрит", []string{"9H", "8D", "7C", "6S", "5H"}, false, HandStraight, []int{9}},
+		{"колесо", []string{"AH", "2D", "3C", "4S", "5H"}, false, HandStraight, []int{5}},
+		{"флеш", []string{"AH", "JH", "9H", "6H", "2H"}, false, HandFlush, []int{14, 11, 9, 6, 2}},
+		{"фулл-хаус", []string{"KH", "KD", "KC", "7S", "7H"}, false, HandFullHouse, []int{13, 7}},
+		{"каре", []string{"QH", "QD", "QC", "QS", "2H"}, false, HandFourOfAKind, []int{12, 2}},
+		{"стрит-флеш", []string{"TS", "JS", "QS", "KS", "AS"}, false, HandStraightFlush, []int{14}},
+		{"A-6-7-8-9 без шорт-дека не стрит", []string{"AH", "6D", "7C", "8S", "9H"}, false, HandHighCard, []int{14, 9, 8, 7, 6}},
+		{"шорт-дек: младший стрит A-6-7-8-9", []string{"AH", "6D", "7C", "8S", "9H"}, true, HandStraight, []int{9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards := mustParseCards(t, tt.cards...)
+			got := EvaluateFive(cards)
+			if tt.shortDeck {
+				got = EvaluateShortDeckFive(cards)
+			}
+			if got.Category != tt.category || !reflect.DeepEqual(got.Ranks, tt.ranks) {
+				t.Errorf("оценка %v = %s %v, want %s %v", tt.cards, got.Category, got.Ranks, tt.category, tt.ranks)
+			}
+		})
+	}
+}
+
+func TestHandValueCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      []string
+		shortDeck bool
+		want      int
+	}{
+		{"старшая пара сильнее", []string{"KH", "KD", "9C", "6S", "2H"}, []string{"QH", "QD", "AC", "6D", "2C"}, false, 1},
+		{"кикер решает", []string{"KH", "KD", "9C", "6S", "2H"}, []string{"KC", "KS", "8C", "6D", "2C"}, false, 1},
+		{"колесо младше шестистрита", []string{"AH", "2D", "3C", "4S", "5H"}, []string{"2H", "3D", "4C", "5S", "6H"}, false, -1},
+		{"одинаковые руки разных мастей", []string{"AH", "JD", "9C", "6S", "2H"}, []string{"AD", "JC", "9S", "6H", "2D"}, false, 0},
+		{"фулл-хаус старше флеша", []string{"KH", "KD", "KC", "7S", "7H"}, []string{"AH", "JH", "9H", "6H", "8H"}, false, 1},
+		{"шорт-дек: флеш старше фулл-хауса", []string{"KH", "KD", "KC", "7S", "7H"}, []string{"AH", "JH", "9H", "6H", "8H"}, true, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluate := EvaluateFive
+			if tt.shortDeck {
+				evaluate = EvaluateShortDeckFive
+			}
+			a := evaluate(mustParseCards(t, tt.a...))
+			b := evaluate(mustParseCards(t, tt.b...))
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateLowFive(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []string
+		want  []int // nil - младшая рука не проходит
+	}{
+		{"колесо - лучшая младшая рука", []string{"AH", "2D", "3C", "4S", "5H"}, []int{5, 4, 3, 2, 1}},
+		{"восьмерка проходит", []string{"8H", "5D", "4C", "2S", "AH"}, []int{8, 5, 4, 2, 1}},
+		{"девятка не проходит", []string{"9H", "5D", "4C", "2S", "AH"}, nil},
+		{"пара не проходит", []string{"8H", "8D", "4C", "2S", "AH"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluateLowFive(mustParseCards(t, tt.cards...))
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("EvaluateLowFive(%v) = %v, want nil", tt.cards, got.Ranks)
+				}
+				return
+			}
+			if got == nil || !reflect.DeepEqual(got.Ranks, tt.want) {
+				t.Errorf("EvaluateLowFive(%v) = %v, want %v", tt.cards, got, tt.want)
+			}
+		})
+	}
+
+	better := EvaluateLowFive(mustParseCards(t, "7H", "5D", "4C", "2S", "AH"))
+	worse := EvaluateLowFive(mustParseCards(t, "8H", "3D", "2C", "AS", "4H"))
+	if better.Compare(*worse) != 1 {
+		t.Errorf("7-5-4-2-A должна быть сильнее 8-4-3-2-A")
+	}
+}
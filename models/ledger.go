@@ -0,0 +1,160 @@
+package models
+
+import (
+	"strconv"
+)
+
+// LedgerReason - причина движения фишек в журнале
+type LedgerReason string
+
+// Константы причин движения фишек
+const (
+	LedgerReasonBuyIn           LedgerReason = "buy_in"           // Покупка фишек при посадке за стол
+	LedgerReasonCashOut         LedgerReason = "cash_out"         // Вывод фишек при уходе из-за стола
+	LedgerReasonBlind           LedgerReason = "blind"            // Обязательная ставка (блайнд, анте)
+	LedgerReasonBet             LedgerReason = "bet"              // Ставка игрока в банк
+	LedgerReasonPotWin          LedgerReason = "pot_win"          // Выигрыш банка
+	LedgerReasonRake            LedgerReason = "rake"             // Рейк клуба
+	LedgerReasonRefund          LedgerReason = "refund"           // Возврат неуравненной ставки
+	LedgerReasonAdminAdjustment LedgerReason = "admin_adjustment" // Ручная корректировка администратором
+
+	LedgerReasonTournamentEntry LedgerReason = "tournament_entry" // Взнос за участие в турнире (buy-in и комиссия)
+	LedgerReasonTournamentChips LedgerReason = "tournament_chips" // Выдача и возврат турнирных фишек
+	LedgerReasonTournamentPrize LedgerReason = "tournament_prize" // Выплата турнирного приза
+	LedgerReasonTournamentRebuy LedgerReason = "tournament_rebuy" // Оплата турнирного ребая (buy-in и комиссия)
+	LedgerReasonTournamentAddOn LedgerReason = "tournament_addon" // Оплата турнирного аддона
+	LedgerReasonTournamentKO    LedgerReason = "tournament_ko"    // Выплата баунти за выбитого участника
+)
+
+// Счета журнала, не привязанные к игроку
+const (
+	// LedgerAccountCashier - касса клуба (источник и получатель фишек при buy-in/cash-out)
+	LedgerAccountCashier = "cashier"
+
+	// LedgerAccountRake - счет рейка клуба
+	LedgerAccountRake = "rake"
+
+	// LedgerAccountAdjustments - счет ручных корректировок
+	LedgerAccountAdjustments = "adjustments"
+
+	// LedgerAccountTournamentFees - счет турнирных комиссий клуба
+	LedgerAccountTournamentFees = "tournament_fees"
+)
+
+// LedgerPosting - одна проводка по счету
+// Положительная сумма - зачисление на счет, отрицательная - списание
+type LedgerPosting struct {
+	Account string `json:"account"`
+	Amount  int    `json:"amount"`
+}
+
+// LedgerEntry - запись журнала (набор проводок с нулевой суммой)
+type LedgerEntry struct {
+	// Порядковый номер записи в журнале клуба
+	EntryID int64 `json:"entry_id"`
+
+	// ID клуба
+	ClubID string `json:"club_id"`
+
+	// ID комнаты (может быть пустым для операций вне стола)
+	RoomID string `json:"room_id,omitempty"`
+
+	// Причина движения фишек
+	Reason LedgerReason `json:"reason"`
+
+	// Ссылка на источник операции (ID раздачи, ID операции buy-in и т.д.)
+	Reference string `json:"reference"`
+
+	// Проводки записи
+	Postings []LedgerPosting `json:"postings"`
+
+	// Временная метка (Unix timestamp в секундах)
+	Timestamp int64 `json:"timestamp"`
+}
+
+// NewTransferEntry - создает запись о переводе фишек с одного счета на другой
+func NewTransferEntry(clubID, roomID string, reason LedgerReason, reference, from, to string, amount int) *LedgerEntry {
+	return &LedgerEntry{
+		ClubID:    clubID,
+		RoomID:    roomID,
+		Reason:    reason,
+		Reference: reference,
+		Postings: []LedgerPosting{
+			{Account: from, Amount: -amount},
+			{Account: to, Amount: amount},
+		},
+	}
+}
+
+// IsBalanced - проверяет, что запись сбалансирована (сумма проводок равна нулю)
+func (e *LedgerEntry) IsBalanced() bool {
+	if len(e.Postings) < 2 {
+		return false
+	}
+
+	sum := 0
+	for _, posting := range e.Postings {
+		sum += posting.Amount
+	}
+
+	return sum == 0
+}
+
+// IsValidLedgerReason - проверяет, является ли строка валидной причиной движения фишек
+func IsValidLedgerReason(reason string) bool {
+	switch LedgerReason(reason) {
+	case LedgerReasonBuyIn, LedgerReasonCashOut, LedgerReasonBlind, LedgerReasonBet,
+		LedgerReasonPotWin, LedgerReasonRake, LedgerReasonRefund, LedgerReasonAdminAdjustment,
+		LedgerReasonTournamentEntry, LedgerReasonTournamentChips, LedgerReasonTournamentPrize,
+		LedgerReasonTournamentRebuy, LedgerReasonTournamentAddOn, LedgerReasonTournamentKO:
+		return true
+	default:
+		return false
+	}
+}
+
+// PlayerStackAccount - возвращает счет стека игрока за столом
+// Формат: "room:{roomId}:stack:{userId}"
+// Баланс этого счета всегда должен совпадать с полем chips игрока
+func PlayerStackAccount(roomID, userID string) string {
+	return "room:" + roomID + ":stack:" + userID
+}
+
+// RoomPotAccount - возвращает счет банка стола
+// Формат: "room:{roomId}:pot"
+// В течение раздачи хранит все поставленные фишки, после раздачи должен быть равен нулю
+func RoomPotAccount(roomID string) string {
+	return "room:" + roomID + ":pot"
+}
+
+// TournamentPrizePoolAccount - возвращает счет призового фонда турнира
+// Формат: "tournament:{tournamentId}:prize_pool"
+// Пополняется взносами участников, после выплаты призов должен быть равен нулю
+func TournamentPrizePoolAccount(tournamentID string) string {
+	return "tournament:" + tournamentID + ":prize_pool"
+}
+
+// TournamentChipsAccount - возвращает счет турнирных фишек
+// Формат: "tournament:{tournamentId}:chips"
+// Выдает стартовые стеки участникам; фишки не имеют денежной стоимости
+// и возвращаются на этот счет, когда турнир завершается
+func TournamentChipsAccount(tournamentID string) string {
+	return "tournament:" + tournamentID + ":chips"
+}
+
+// HandReference - формирует ссылку на раздачу для записей журнала
+// Формат: "{gameId}#{roundNumber}"
+func HandReference(gameID string, roundNumber int) string {
+	return gameID + "#" + strconv.Itoa(roundNumber)
+}
+
+// ReconciliationMismatch - расхождение между стеком игрока и балансом его счета в журнале
+type ReconciliationMismatch struct {
+	ClubID         string `json:"club_id"`
+	RoomID         string `json:"room_id"`
+	Account        string `json:"account"`
+	Expected       int    `json:"expected"`       // Значение по состоянию стола
+	LedgerBalance  int    `json:"ledger_balance"` // Значение по журналу
+	Difference     int    `json:"difference"`     // Expected - LedgerBalance
+	DetectedAtUnix int64  `json:"detected_at"`
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LowQualifier - старшая карта, при которой младшая рука еще проходит (8 or better)
+const LowQualifier = 8
+
+// LowHandValue - младшая рука (туз - единица)
+// В омахе хай-лоу - пять разных карт не старше восьмерки, в рэззе - любые пять карт
+type LowHandValue struct {
+	// Комбинация по парам (только в рэззе): рука без пар лучше любой руки с парой
+	Category HandCategory `json:"category,omitempty"`
+
+	// Достоинства по убыванию (туз = 1), например для 8-5-4-2-A: [8, 5, 4, 2, 1]
+	// Для рук с парами сначала идут группы одинаковых карт, например для 3-3-6-2-A: [3, 6, 2, 1]
+	Ranks []int `json:"ranks"`
+
+	// Пять карт, составляющих руку
+	Cards []string `json:"cards"`
+}
+
+// Compare - сравнивает две младшие руки (меньшая - сильнее)
+// Возвращает 1 если рука l сильнее, -1 если слабее, 0 при равенстве
+func (l LowHandValue) Compare(other LowHandValue) int {
+	if l.Category != other.Category {
+		if l.Category < other.Category {
+			return 1
+		}
+		return -1
+	}
+
+	for i := 0; i < len(l.Ranks) && i < len(other.Ranks); i++ {
+		if l.Ranks[i] != other.Ranks[i] {
+			if l.Ranks[i] < other.Ranks[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// EvaluateLowFive - оценивает пять карт как младшую руку
+// Возвращает nil, если карты не составляют младшую руку 8 or better
+// (есть пара или карта старше восьмерки). Стриты и флеши младшую руку не портят
+func EvaluateLowFive(cards []Card) *LowHandValue {
+	ranks := make([]int, 0, len(cards))
+	seen := make(map[int]bool, len(cards))
+
+	for _, c := range cards {
+		rank := c.Rank
+		if rank == RankAce {
+			rank = 1
+		}
+		if rank > LowQualifier || seen[rank] {
+			return nil
+		}
+		seen[rank] = true
+		ranks = append(ranks, rank)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(ranks)))
+	return &LowHandValue{Ranks: ranks, Cards: cardStrings(cards)}
+}
+
+// EvaluateOmahaLow - лучшая младшая рука в омахе: ровно 2 карты игрока и ровно 3 общие карты
+// Возвращает nil, если младшая рука не проходит
+func EvaluateOmahaLow(hole, board []Card) *LowHandValue {
+	var best *LowHandValue
+	for _, holeCombo := range Combinations(len(hole), 2) {
+		for _, boardCombo := range Combinations(len(board), 3) {
+			five := append(pickCards(hole, holeCombo), pickCards(board, boardCombo)...)
+			low := EvaluateLowFive(five)
+			if low != nil && (best == nil || low.Compare(*best) > 0) {
+				best = low
+			}
+		}
+	}
+	return best
+}
+
+// EvaluateBestLow - лучшая младшая рука 8 or better из любых пяти карт (стад хай-лоу)
+// Возвращает nil, если младшая рука не проходит
+func EvaluateBestLow(cards []Card) *LowHandValue {
+	var best *LowHandValue
+	for _, combo := range Combinations(len(cards), 5) {
+		low := EvaluateLowFive(pickCards(cards, combo))
+		if low != nil && (best == nil || low.Compare(*best) > 0) {
+			best = low
+		}
+	}
+	return best
+}
+
+// EvaluateRazz - оценивает карты как младшую руку от туза до пятерки (рэзз)
+// Стриты и флеши не учитываются, туз - единица, пары ухудшают руку.
+// Работает для любого количества карт до пяти: так оцениваются и открытые карты на улицах
+func EvaluateRazz(cards []Card) LowHandValue {
+	counts := make(map[int]int, len(cards))
+	for _, c := range cards {
+		counts[lowRank(c)]++
+	}
+
+	// Группы одинаковых достоинств: сначала по размеру группы, затем по достоинству
+	groups := make([]int, 0, len(counts))
+	for rank := range counts {
+		groups = append(groups, rank)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if counts[groups[i]] != counts[groups[j]] {
+			return counts[groups[i]] > counts[groups[j]]
+		}
+		return groups[i] > groups[j]
+	})
+
+	category := HandHighCard
+	if len(groups) > 0 {
+		switch {
+		case counts[groups[0]] == 4:
+			category = HandFourOfAKind
+		case counts[groups[0]] == 3 && len(groups) >= 2 && counts[groups[1]] == 2:
+			category = HandFullHouse
+		case counts[groups[0]] == 3:
+			category = HandThreeOfAKind
+		case counts[groups[0]] == 2 && len(groups) >= 2 && counts[groups[1]] == 2:
+			category = HandTwoPair
+		case counts[groups[0]] == 2:
+			category = HandPair
+		}
+	}
+
+	return LowHandValue{Category: category, Ranks: groups, Cards: cardStrings(cards)}
+}
+
+// EvaluateRazzBest - лучшая младшая рука рэзза из любых пяти карт игрока
+func EvaluateRazzBest(cards []Card) (LowHandValue, error) {
+	if len(cards) < 5 {
+		return LowHandValue{}, fmt.Errorf("для оценки руки нужно минимум 5 карт, получено %d", len(cards))
+	}
+
+	var best LowHandValue
+	found := false
+	for _, combo := range Combinations(len(cards), 5) {
+		value := EvaluateRazz(pickCards(cards, combo))
+		if !found || value.Compare(best) > 0 {
+			best, found = value, true
+		}
+	}
+
+	return best, nil
+}
+
+// lowRank - достоинство карты для младшей руки (туз = 1)
+func lowRank(c Card) int {
+	if c.Rank == RankAce {
+		return 1
+	}
+	return c.Rank
+}
@@ -0,0 +1,87 @@
+package models
+
+// RotationMode - правило смены разновидности покера в смешанной игре
+type RotationMode string
+
+// Константы правил смены разновидности
+const (
+	// RotationByHands - смена каждые N раздач
+	RotationByHands RotationMode = "hands"
+
+	// RotationByOrbit - смена после каждого круга (по раздаче на каждого игрока за столом)
+	RotationByOrbit RotationMode = "orbit"
+)
+
+// DefaultRotationHands - количество раздач одной разновидности по умолчанию
+const DefaultRotationHands = 8
+
+// ParseRotationMode - парсит строку в RotationMode (по умолчанию - каждые N раздач)
+func ParseRotationMode(mode string) RotationMode {
+	if RotationMode(mode) == RotationByOrbit {
+		return RotationByOrbit
+	}
+	return RotationByHands
+}
+
+// MixedGameVariant - разновидность покера в ротации смешанной игры
+// Вместе с разновидностью меняются структура ставок и ставки стола.
+// Нулевые ставки означают стандартные значения комнаты
+type MixedGameVariant struct {
+	// Разновидность покера
+	GameType GameType `json:"game_type"`
+
+	// Структура ставок ("" - стандартная для разновидности)
+	BettingStructure BettingStructure `json:"betting_structure,omitempty"`
+
+	// Блайнды (0 - не менять блайнды комнаты)
+	SmallBlind int `json:"small_blind,omitempty"`
+	BigBlind   int `json:"big_blind,omitempty"`
+
+	// Малая и большая ставка фикс-лимита
+	SmallBet int `json:"small_bet,omitempty"`
+	BigBet   int `json:"big_bet,omitempty"`
+
+	// Bring-in в стаде
+	BringIn int `json:"bring_in,omitempty"`
+}
+
+// Structure - структура ставок разновидности
+func (v MixedGameVariant) Structure() BettingStructure {
+	return ParseBettingStructure(string(v.BettingStructure), v.GameType)
+}
+
+// MixedGameState - текущее положение смешанной игры в ротации
+type MixedGameState struct {
+	// Текущая разновидность
+	Variant MixedGameVariant `json:"variant"`
+
+	// Индекс текущей разновидности в ротации
+	VariantIndex int `json:"variant_index"`
+
+	// Сколько раздач осталось до смены разновидности (включая текущую)
+	HandsUntilRotation int `json:"hands_until_rotation"`
+
+	// Разновидность сменилась в этой раздаче
+	Rotated bool `json:"rotated"`
+}
+
+// HORSEVariants - ротация HORSE: холдем, омаха хай-лоу, рэзз, стад, стад хай-лоу
+// Все разновидности играются в фикс-лимит с одинаковыми размерами ставок
+func HORSEVariants(smallBet, bigBet int) []MixedGameVariant {
+	gameTypes := []GameType{GameTypeHoldem, GameTypeOmahaHiLo, GameTypeRazz, GameTypeStud, GameTypeStudHiLo}
+
+	variants := make([]MixedGameVariant, len(gameTypes))
+	for i, gameType := range gameTypes {
+		variants[i] = MixedGameVariant{
+			GameType:         gameType,
+			BettingStructure: BettingFixedLimit,
+			SmallBlind:       smallBet / 2,
+			BigBlind:         smallBet,
+			SmallBet:         smallBet,
+			BigBet:           bigBet,
+			BringIn:          smallBet / 4,
+		}
+	}
+
+	return variants
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+// PayoutTier - строка таблицы выплат: доли призового фонда по местам в процентах
+// для турниров с количеством участников не больше MaxEntrants
+type PayoutTier struct {
+	// Максимальное количество участников (0 - без ограничения)
+	MaxEntrants int `json:"max_entrants"`
+
+	// Доли по местам (первый элемент - первое место), в сумме 100
+	Percents []int `json:"percents"`
+}
+
+// PayoutStructure - таблица выплат по размеру поля
+type PayoutStructure []PayoutTier
+
+// DefaultPayoutStructure - стандартная таблица выплат
+// До 3 участников платится 1 место, до 6 - 2 места, до 10 - 3 места, больше - 4 места
+var DefaultPayoutStructure = PayoutStructure{
+	{MaxEntrants: 3, Percents: []int{100}},
+	{MaxEntrants: 6, Percents: []int{65, 35}},
+	{MaxEntrants: 10, Percents: []int{50, 30, 20}},
+	{MaxEntrants: 0, Percents: []int{40, 30, 20, 10}},
+}
+
+// PercentsFor - доли по местам для поля из entrants участников
+// Берется строка с наименьшим MaxEntrants, вмещающим поле; строка без ограничения - последней.
+// Призовых мест не может быть больше, чем участников: доли лишних мест достаются первому месту
+func (s PayoutStructure) PercentsFor(entrants int) []int {
+	tiers := make([]PayoutTier, len(s))
+	copy(tiers, s)
+	sort.SliceStable(tiers, func(i, j int) bool {
+		if tiers[i].MaxEntrants == 0 || tiers[j].MaxEntrants == 0 {
+			return tiers[j].MaxEntrants == 0 && tiers[i].MaxEntrants != 0
+		}
+		return tiers[i].MaxEntrants < tiers[j].MaxEntrants
+	})
+
+	var percents []int
+	for _, tier := range tiers {
+		percents = tier.Percents
+		if tier.MaxEntrants == 0 || entrants <= tier.MaxEntrants {
+			break
+		}
+	}
+
+	if entrants > 0 && len(percents) > entrants {
+		trimmed := make([]int, entrants)
+		copy(trimmed, percents[:entrants])
+		for _, percent := range percents[entrants:] {
+			trimmed[0] += percent
+		}
+		return trimmed
+	}
+	return percents
+}
+
+// IsValid - проверяет таблицу: в каждой строке доли положительные,
+// не растут от места к месту и в сумме дают 100
+func (s PayoutStructure) IsValid() bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for _, tier := range s {
+		if tier.MaxEntrants < 0 || !IsValidPayoutPercents(tier.Percents) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidPayoutPercents - проверяет доли по местам: положительные, не растут, в сумме 100
+func IsValidPayoutPercents(percents []int) bool {
+	if len(percents) == 0 {
+		return false
+	}
+
+	sum := 0
+	for i, percent := range percents {
+		if percent <= 0 || (i > 0 && percent > percents[i-1]) {
+			return false
+		}
+		sum += percent
+	}
+	return sum == 100
+}
+
+// DealMethod - способ расчета сделки за финальным столом
+type DealMethod string
+
+// Константы способов расчета сделки
+const (
+	// DealMethodICM - по модели ICM (Independent Chip Model)
+	DealMethodICM DealMethod = "icm"
+
+	// DealMethodChipChop - каждому гарантирован наименьший оставшийся приз,
+	// остальное делится пропорционально фишкам
+	DealMethodChipChop DealMethod = "chip_chop"
+)
+
+// IsValidDealMethod - проверяет, является ли строка валидным способом расчета сделки
+func IsValidDealMethod(method string) bool {
+	switch DealMethod(method) {
+	case DealMethodICM, DealMethodChipChop:
+		return true
+	default:
+		return false
+	}
+}
+
+// CalculateICM - ожидаемый выигрыш каждого игрока по модели Malmuth-Harville
+// Вероятность занять очередное место пропорциональна доле фишек среди еще не занявших места.
+// stacks - стеки игроков, prizes - оставшиеся призы по местам (первый элемент - первое место).
+// Результат округлен до целых фишек так, что в сумме дает сумму призов
+func CalculateICM(stacks []int, prizes []int) []int {
+	n := len(stacks)
+	equities := make([]float64, n)
+
+	total := 0
+	for _, stack := range stacks {
+		total += stack
+	}
+	if n == 0 || total <= 0 {
+		return make([]int, n)
+	}
+
+	places := len(prizes)
+	if places > n {
+		places = n
+	}
+
+	// probability[mask] - вероятность того, что игроки из mask заняли первые |mask| мест
+	probability := make([]float64, 1<<uint(n))
+	probability[0] = 1
+	for mask := 0; mask < len(probability); mask++ {
+		if probability[mask] == 0 {
+			continue
+		}
+
+		place, taken := 0, 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<uint(i)) != 0 {
+				place++
+				taken += stacks[i]
+			}
+		}
+		if place >= places {
+			continue
+		}
+
+		left := total - taken
+		for i := 0; i < n; i++ {
+			if mask&(1<<uint(i)) != 0 || stacks[i] <= 0 {
+				continue
+			}
+			p := probability[mask] * float64(stacks[i]) / float64(left)
+			probability[mask|1<<uint(i)] += p
+			equities[i] += p * float64(prizes[place])
+		}
+	}
+
+	return roundShares(equities, sumInts(prizes[:places]))
+}
+
+// CalculateChipChop - раздел по фишкам: каждому гарантирован наименьший из призов
+// оставшихся мест, остаток призов делится пропорционально стекам
+func CalculateChipChop(stacks []int, prizes []int) []int {
+	n := len(stacks)
+	if n == 0 {
+		return []int{}
+	}
+
+	remaining := make([]int, n)
+	copy(remaining, prizes)
+	pool := sumInts(remaining)
+	guaranteed := remaining[n-1]
+
+	total := 0
+	for _, stack := range stacks {
+		total += stack
+	}
+
+	shares := make([]float64, n)
+	rest := float64(pool - guaranteed*n)
+	for i, stack := range stacks {
+		shares[i] = float64(guaranteed)
+		if total > 0 {
+			shares[i] += rest * float64(stack) / float64(total)
+		}
+	}
+
+	return roundShares(shares, pool)
+}
+
+// roundShares - округляет доли до целых так, что их сумма равна total
+// Остаток распределяется по наибольшим дробным частям
+func roundShares(shares []float64, total int) []int {
+	result := make([]int, len(shares))
+	fractions := make([]int, len(shares))
+
+	assigned := 0
+	for i, share := range shares {
+		result[i] = int(math.Floor(share))
+		assigned += result[i]
+		fractions[i] = i
+	}
+
+	sort.SliceStable(fractions, func(a, b int) bool {
+		fa := shares[fractions[a]] - math.Floor(shares[fractions[a]])
+		fb := shares[fractions[b]] - math.Floor(shares[fractions[b]])
+		return fa > fb
+	})
+	for k := 0; assigned < total && len(fractions) > 0; k = (k + 1) % len(fractions) {
+		result[fractions[k]]++
+		assigned++
+	}
+
+	return result
+}
+
+// sumInts - сумма элементов
+func sumInts(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// TournamentDeal - предложение сделки за финальным столом
+type TournamentDeal struct {
+	// Способ расчета
+	Method DealMethod `json:"method"`
+
+	// Кто предложил сделку
+	ProposedBy string `json:"proposed_by"`
+
+	// Стеки участников на момент предложения (сделка отменяется, если стеки изменились)
+	Stacks map[string]int `json:"stacks"`
+
+	// Выплата каждому участнику по сделке
+	Payouts map[string]int `json:"payouts"`
+
+	// Участники, согласившиеся на сделку
+	Accepted map[string]bool `json:"accepted,omitempty"`
+
+	// Время предложения (Unix timestamp в миллисекундах)
+	ProposedAt int64 `json:"proposed_at"`
+}
+
+// AllAccepted - проверяет, согласились ли все участники
+func (d *TournamentDeal) AllAccepted() bool {
+	for userID := range d.Stacks {
+		if !d.Accepted[userID] {
+			return false
+		}
+	}
+	return len(d.Stacks) > 0
+}
+
+// PlacesByStack - участники сделки по убыванию стека (при равенстве - по ID)
+// Определяет занятые места при завершении турнира сделкой
+func (d *TournamentDeal) PlacesByStack() []string {
+	userIDs := make([]string, 0, len(d.Stacks))
+	for userID := range d.Stacks {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		if d.Stacks[userIDs[i]] != d.Stacks[userIDs[j]] {
+			return d.Stacks[userIDs[i]] > d.Stacks[userIDs[j]]
+		}
+		return userIDs[i] < userIDs[j]
+	})
+	return userIDs
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCalculateICM(t *testing.T) {
+	tests := []struct {
+		name   string
+		stacks []int
+		prizes []int
+		want   []int
+	}{
+		{
+			name:   "хедз-ап",
+			stacks: []int{3000, 1000},
+			prizes: []int{70, 30},
+			want:   []int{60, 40},
+		},
+		{
+			name:   "равные стеки - равные доли, остаток первому",
+			stacks: []int{1000, 1000, 1000},
+			prizes: []int{50, 30, 20},
+			want:   []int{34, 33, 33},
+		},
+		{
+			name:   "призов меньше, чем игроков",
+			stacks: []int{5000, 3000, 2000},
+			prizes: []int{100},
+			want:   []int{50, 30, 20},
+		},
+		{
+			name:   "без фишек",
+			stacks: []int{0, 0},
+			prizes: []int{70, 30},
+			want:   []int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateICM(tt.stacks, tt.prizes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CalculateICM(%v, %v) = %v, want %v", tt.stacks, tt.prizes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateICMSumsToPrizes(t *testing.T) {
+	stacks := []int{12000, 7300, 4100, 2600, 900}
+	prizes := []int{500, 300, 200, 120, 80}
+
+	got := CalculateICM(stacks, prizes)
+	if sumInts(got) != sumInts(prizes) {
+		t.Fatalf("сумма ICM = %d, want %d", sumInts(got), sumInts(prizes))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i] > got[i-1] {
+			t.Errorf("стек %d получил %d больше стека %d (%d)", stacks[i], got[i], stacks[i-1], got[i-1])
+		}
+	}
+	for i, share := range got {
+		if share < prizes[len(prizes)-1] || share > prizes[0] {
+			t.Errorf("доля игрока %d = %d вне диапазона призов", i, share)
+		}
+	}
+}
+
+func TestCalculateChipChop(t *testing.T) {
+	tests := []struct {
+		name   string
+		stacks []int
+		prizes []int
+		want   []int
+	}{
+		{
+			name:   "хедз-ап",
+			stacks: []int{3000, 1000},
+			prizes: []int{70, 30},
+			want:   []int{60, 40},
+		},
+		{
+			name:   "три игрока",
+			stacks: []int{5000, 3000, 2000},
+			prizes: []int{50, 30, 20},
+			want:   []int{40, 32, 28},
+		},
+		{
+			name:   "округление до суммы призов",
+			stacks: []int{1000, 1000, 1000},
+			prizes: []int{50, 30, 20},
+			want:   []int{34, 33, 33},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateChipChop(tt.stacks, tt.prizes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CalculateChipChop(%v, %v) = %v, want %v", tt.stacks, tt.prizes, got, tt.want)
+			}
+			if sumInts(got) != sumInts(tt.prizes) {
+				t.Errorf("сумма = %d, want %d", sumInts(got), sumInts(tt.prizes))
+			}
+		})
+	}
+}
@@ -57,9 +57,18 @@ type Player struct {
 	// Текущая ставка игрока в раунде
 	Bet int
 
-	// Карты игрока (обычно 2 карты в техасском холдеме)
+	// Общая сумма, внесенная игроком в банк за всю раздачу (для расчета side pots)
+	TotalBet int
+
+	// Часть TotalBet, внесенная как анте за весь стол (анте дилера или большого блайнда)
+	TableAnte int
+
+	// Закрытые карты игрока (обычно 2 карты в техасском холдеме)
 	Cards []string
 
+	// Открытые карты игрока (только в стаде, видны всем за столом)
+	UpCards []string
+
 	// Текущий статус игрока
 	Status PlayerStatus
 
@@ -80,6 +89,33 @@ type Player struct {
 
 	// Общая сумма фишек, с которыми игрок сел за стол (buy-in)
 	InitialBuyIn int
+
+	// Фишки, докупленные во время раздачи (зачисляются в стек после ее окончания)
+	PendingChips int
+
+	// Игрок попросил пропустить раздачи начиная со следующей
+	SitOutNextHand bool
+
+	// Игрок пропустил малый блайнд, пока сидел вне игры
+	MissedSmallBlind bool
+
+	// Игрок пропустил большой блайнд, пока сидел вне игры
+	MissedBigBlind bool
+
+	// Сколько кругов (orbits) игрок пропустил, сидя вне игры
+	SitOutOrbits int
+
+	// Игрок вернулся в игру, но ждет большого блайнда вместо оплаты пропущенных блайндов
+	WaitForBigBlind bool
+
+	// Игрок вернулся в игру и оплатит пропущенные блайнды в следующей раздаче
+	PostMissedBlinds bool
+
+	// Игрок хочет поставить стрэддл в следующей раздаче
+	WantsStraddle bool
+
+	// Сумма стрэддла, поставленного игроком в текущей раздаче
+	Straddle int
 }
 
 // PlayerInfo - структура информации об игроке из Redis
@@ -90,13 +126,27 @@ type PlayerInfo struct {
 	Position      string       `json:"position"`
 	Chips         string       `json:"chips"`
 	Bet           string       `json:"bet"`
-	Cards         string       `json:"cards"` // JSON массив
+	TotalBet      string       `json:"total_bet"`
+	TableAnte     string       `json:"table_ante"`
+	Cards         string       `json:"cards"`    // JSON массив
+	UpCards       string       `json:"up_cards"` // JSON массив
 	Status        PlayerStatus `json:"status"`
 	LastAction    string       `json:"last_action"`
 	IsDealer      string       `json:"is_dealer"`
 	IsSmallBlind  string       `json:"is_small_blind"`
 	IsBigBlind    string       `json:"is_big_blind"`
 	JoinedTableAt string       `json:"joined_table_at"`
+	InitialBuyIn  string       `json:"initial_buy_in"`
+	PendingChips  string       `json:"pending_chips"`
+
+	SitOutNextHand   string `json:"sit_out_next_hand"`
+	MissedSmallBlind string `json:"missed_small_blind"`
+	MissedBigBlind   string `json:"missed_big_blind"`
+	SitOutOrbits     string `json:"sit_out_orbits"`
+	WaitForBigBlind  string `json:"wait_for_big_blind"`
+	PostMissedBlinds string `json:"post_missed_blinds"`
+	WantsStraddle    string `json:"wants_straddle"`
+	Straddle         string `json:"straddle"`
 }
 
 // NewPlayerFromRedis - создает Player из данных Redis hash
@@ -105,12 +155,20 @@ func NewPlayerFromRedis(data map[string]string) (*Player, error) {
 	position, _ := strconv.Atoi(data["position"])
 	chips, _ := strconv.Atoi(data["chips"])
 	bet, _ := strconv.Atoi(data["bet"])
+	totalBet, _ := strconv.Atoi(data["total_bet"])
+	tableAnte, _ := strconv.Atoi(data["table_ante"])
+	initialBuyIn, _ := strconv.Atoi(data["initial_buy_in"])
+	pendingChips, _ := strconv.Atoi(data["pending_chips"])
 
 	// Парсим карты из JSON
 	var cards []string
 	if data["cards"] != "" {
 		json.Unmarshal([]byte(data["cards"]), &cards)
 	}
+	var upCards []string
+	if data["up_cards"] != "" {
+		json.Unmarshal([]byte(data["up_cards"]), &upCards)
+	}
 
 	// Парсим последнее действие
 	var lastAction *PlayerAction
@@ -124,19 +182,45 @@ func NewPlayerFromRedis(data map[string]string) (*Player, error) {
 	isSmallBlind := data["is_small_blind"] == "true" || data["is_small_blind"] == "1"
 	isBigBlind := data["is_big_blind"] == "true" || data["is_big_blind"] == "1"
 
+	// Состояние пропуска раздач
+	sitOutNextHand := data["sit_out_next_hand"] == "true" || data["sit_out_next_hand"] == "1"
+	missedSmallBlind := data["missed_small_blind"] == "true" || data["missed_small_blind"] == "1"
+	missedBigBlind := data["missed_big_blind"] == "true" || data["missed_big_blind"] == "1"
+	waitForBigBlind := data["wait_for_big_blind"] == "true" || data["wait_for_big_blind"] == "1"
+	postMissedBlinds := data["post_missed_blinds"] == "true" || data["post_missed_blinds"] == "1"
+	sitOutOrbits, _ := strconv.Atoi(data["sit_out_orbits"])
+
+	// Стрэддл
+	wantsStraddle := data["wants_straddle"] == "true" || data["wants_straddle"] == "1"
+	straddle, _ := strconv.Atoi(data["straddle"])
+
 	return &Player{
 		UserID:        data["user_id"],
 		Username:      data["username"],
 		Position:      position,
 		Chips:         chips,
 		Bet:           bet,
+		TotalBet:      totalBet,
+		TableAnte:     tableAnte,
 		Cards:         cards,
+		UpCards:       upCards,
 		Status:        PlayerStatus(data["status"]),
 		LastAction:    lastAction,
 		IsDealer:      isDealer,
 		IsSmallBlind:  isSmallBlind,
 		IsBigBlind:    isBigBlind,
 		JoinedTableAt: data["joined_table_at"],
+		InitialBuyIn:  initialBuyIn,
+		PendingChips:  pendingChips,
+
+		SitOutNextHand:   sitOutNextHand,
+		MissedSmallBlind: missedSmallBlind,
+		MissedBigBlind:   missedBigBlind,
+		SitOutOrbits:     sitOutOrbits,
+		WaitForBigBlind:  waitForBigBlind,
+		PostMissedBlinds: postMissedBlinds,
+		WantsStraddle:    wantsStraddle,
+		Straddle:         straddle,
 	}, nil
 }
 
@@ -148,16 +232,31 @@ func (p *Player) ToRedisHash() map[string]interface{} {
 		"position":        p.Position,
 		"chips":           p.Chips,
 		"bet":             p.Bet,
+		"total_bet":       p.TotalBet,
+		"table_ante":      p.TableAnte,
 		"status":          string(p.Status),
 		"is_dealer":       p.IsDealer,
 		"is_small_blind":  p.IsSmallBlind,
 		"is_big_blind":    p.IsBigBlind,
 		"joined_table_at": p.JoinedTableAt,
+		"initial_buy_in":  p.InitialBuyIn,
+		"pending_chips":   p.PendingChips,
+
+		"sit_out_next_hand":  p.SitOutNextHand,
+		"missed_small_blind": p.MissedSmallBlind,
+		"missed_big_blind":   p.MissedBigBlind,
+		"sit_out_orbits":     p.SitOutOrbits,
+		"wait_for_big_blind": p.WaitForBigBlind,
+		"post_missed_blinds": p.PostMissedBlinds,
+		"wants_straddle":     p.WantsStraddle,
+		"straddle":           p.Straddle,
 	}
 
 	// Cards как JSON
 	cardsJSON, _ := json.Marshal(p.Cards)
 	hash["cards"] = string(cardsJSON)
+	upCardsJSON, _ := json.Marshal(p.UpCards)
+	hash["up_cards"] = string(upCardsJSON)
 
 	// LastAction
 	if p.LastAction != nil {
@@ -191,6 +290,11 @@ func (p *Player) HasChips() bool {
 	return p.Chips > 0
 }
 
+// HasPendingChips - проверяет, есть ли у игрока фишки, ожидающие зачисления
+func (p *Player) HasPendingChips() bool {
+	return p.PendingChips > 0
+}
+
 // GetTotalChips - возвращает общее количество фишек (стек + ставка)
 func (p *Player) GetTotalChips() int {
 	return p.Chips + p.Bet
@@ -199,6 +303,7 @@ func (p *Player) GetTotalChips() int {
 // === МЕТОДЫ ДЛЯ РАБОТЫ СО СТАВКАМИ ===
 
 // PlaceBet - делает ставку (перемещает фишки из стека в ставку)
+// Меняет только объект в памяти: стек в Redis списывается через журнал (BettingService.ApplyAction)
 // Возвращает false если недостаточно фишек
 func (p *Player) PlaceBet(amount int) bool {
 	if p.Chips < amount {
@@ -206,6 +311,7 @@ func (p *Player) PlaceBet(amount int) bool {
 	}
 	p.Chips -= amount
 	p.Bet += amount
+	p.TotalBet += amount
 	return true
 }
 
@@ -214,9 +320,16 @@ func (p *Player) ResetBet() {
 	p.Bet = 0
 }
 
+// ResetTotalBet - сбрасывает общую сумму ставок за раздачу (начало новой раздачи)
+func (p *Player) ResetTotalBet() {
+	p.TotalBet = 0
+}
+
 // GoAllIn - игрок ставит все фишки
+// Как и PlaceBet, меняет только объект в памяти
 func (p *Player) GoAllIn() {
 	p.Bet += p.Chips
+	p.TotalBet += p.Chips
 	p.Chips = 0
 	p.Status = PlayerStatusAllIn
 }
@@ -248,6 +361,27 @@ func (p *Player) IsSittingOut() bool {
 	return p.Status == PlayerStatusSitOut
 }
 
+// HasMissedBlinds - проверяет, есть ли у игрока неоплаченные пропущенные блайнды
+func (p *Player) HasMissedBlinds() bool {
+	return p.MissedSmallBlind || p.MissedBigBlind
+}
+
+// CanBeDealtIn - проверяет, может ли игрок получить карты в раздаче
+// Игрок не должен сидеть вне игры или ждать большого блайнда. Игрок без фишек
+// получает карты, только если уже поставил их в этой раздаче (all-in на блайнде)
+func (p *Player) CanBeDealtIn() bool {
+	if p.IsSittingOut() || p.WaitForBigBlind {
+		return false
+	}
+	return p.HasChips() || p.TotalBet > 0
+}
+
+// HasStakeInHand - проверяет, участвует ли игрок в раздаче или уже внес фишки в ее банк
+// Фишки сбросившего игрока остаются в банке и распределяются по его записи при расчете банков
+func (p *Player) HasStakeInHand() bool {
+	return p.IsActive() || p.IsAllIn() || p.TotalBet > 0
+}
+
 // CanAct - проверяет, может ли игрок совершать действия
 func (p *Player) CanAct() bool {
 	return p.Status == PlayerStatusActive && p.HasChips()
@@ -268,16 +402,24 @@ func (p *Player) AddCard(card string) {
 // ClearCards - очищает карты игрока
 func (p *Player) ClearCards() {
 	p.Cards = []string{}
+	p.UpCards = []string{}
 }
 
 // HasCards - проверяет, есть ли у игрока карты
 func (p *Player) HasCards() bool {
-	return len(p.Cards) > 0
+	return len(p.Cards) > 0 || len(p.UpCards) > 0
 }
 
 // GetCardsCount - возвращает количество карт у игрока
 func (p *Player) GetCardsCount() int {
-	return len(p.Cards)
+	return len(p.Cards) + len(p.UpCards)
+}
+
+// AllCards - все карты игрока: закрытые и открытые
+func (p *Player) AllCards() []string {
+	all := make([]string, 0, len(p.Cards)+len(p.UpCards))
+	all = append(all, p.Cards...)
+	return append(all, p.UpCards...)
 }
 
 // === МЕТОДЫ ДЛЯ РАБОТЫ С ДЕЙСТВИЯМИ ===
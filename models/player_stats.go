@@ -0,0 +1,302 @@
+package models
+
+import (
+	"strconv"
+)
+
+// Размер рейтинга игроков клуба
+const (
+	DefaultLeaderboardSize = 20
+	MaxLeaderboardSize     = 100
+)
+
+// HandAction - действие игрока в раздаче для подсчета статистики
+type HandAction struct {
+	// ID раздачи (действия других раздач не учитываются)
+	GameID string `json:"game_id"`
+
+	// ID игрока
+	UserID string `json:"user_id"`
+
+	// Улица, на которой совершено действие
+	Phase GamePhase `json:"phase"`
+
+	// Действие (блайнды и стрэддлы записываются как blind)
+	Action PlayerAction `json:"action"`
+
+	// Ставка игрока на улице после действия
+	Amount int `json:"amount,omitempty"`
+}
+
+// PlayerStats - накопленные счетчики статистики игрока в клубе
+// Счетчики увеличиваются в конце каждой раздачи, проценты рассчитываются из них при запросе
+type PlayerStats struct {
+	UserID string `json:"user_id"`
+
+	// Сыгранные раздачи
+	Hands int `json:"hands"`
+
+	// Раздачи, в которых игрок добровольно вложил фишки до флопа (VPIP) и повышал до флопа (PFR)
+	VPIP int `json:"vpip"`
+	PFR  int `json:"pfr"`
+
+	// Возможности сделать 3-бет (до флопа был ровно один рейз) и сделанные 3-беты
+	ThreeBetChances int `json:"three_bet_chances"`
+	ThreeBets       int `json:"three_bets"`
+
+	// Возможности сбросить карты на 3-бет после своего рейза и сбросы
+	FoldToThreeBetChances int `json:"fold_to_three_bet_chances"`
+	FoldsToThreeBet       int `json:"folds_to_three_bet"`
+
+	// Ставки и повышения после флопа, коллы после флопа (для фактора агрессии)
+	AggressiveActions int `json:"aggressive_actions"`
+	Calls             int `json:"calls"`
+
+	// Раздачи, в которых игрок увидел флоп, дошел до вскрытия и выиграл на вскрытии
+	SawFlop      int `json:"saw_flop"`
+	Showdowns    int `json:"showdowns"`
+	ShowdownsWon int `json:"showdowns_won"`
+
+	// Чистый выигрыш (выигрыш минус ставки)
+	Net int `json:"net"`
+}
+
+// PlayerStatsReport - статистика игрока для HUD и рейтингов: счетчики и рассчитанные из них показатели
+type PlayerStatsReport struct {
+	PlayerStats
+
+	// Показатели в процентах
+	VPIPPercent           float64 `json:"vpip_percent"`
+	PFRPercent            float64 `json:"pfr_percent"`
+	ThreeBetPercent       float64 `json:"three_bet_percent"`
+	FoldToThreeBetPercent float64 `json:"fold_to_three_bet_percent"`
+	WTSDPercent           float64 `json:"wtsd_percent"`
+	WSDPercent            float64 `json:"wsd_percent"`
+
+	// Фактор агрессии: (ставки + повышения) / коллы после флопа
+	AggressionFactor float64 `json:"aggression_factor"`
+}
+
+// NewPlayerStatsFromRedis - создает PlayerStats из данных Redis hash
+func NewPlayerStatsFromRedis(userID string, data map[string]string) *PlayerStats {
+	value := func(field string) int {
+		v, _ := strconv.Atoi(data[field])
+		return v
+	}
+
+	return &PlayerStats{
+		UserID:                userID,
+		Hands:                 value("hands"),
+		VPIP:                  value("vpip"),
+		PFR:                   value("pfr"),
+		ThreeBetChances:       value("three_bet_chances"),
+		ThreeBets:             value("three_bets"),
+		FoldToThreeBetChances: value("fold_to_three_bet_chances"),
+		FoldsToThreeBet:       value("folds_to_three_bet"),
+		AggressiveActions:     value("aggressive_actions"),
+		Calls:                 value("calls"),
+		SawFlop:               value("saw_flop"),
+		Showdowns:             value("showdowns"),
+		ShowdownsWon:          value("showdowns_won"),
+		Net:                   value("net"),
+	}
+}
+
+// ToRedisHash - преобразует PlayerStats в map счетчиков Redis hash
+// Используется для инкрементального обновления (HINCRBY каждого поля)
+func (s *PlayerStats) ToRedisHash() map[string]int {
+	return map[string]int{
+		"hands":                     s.Hands,
+		"vpip":                      s.VPIP,
+		"pfr":                       s.PFR,
+		"three_bet_chances":         s.ThreeBetChances,
+		"three_bets":                s.ThreeBets,
+		"fold_to_three_bet_chances": s.FoldToThreeBetChances,
+		"folds_to_three_bet":        s.FoldsToThreeBet,
+		"aggressive_actions":        s.AggressiveActions,
+		"calls":                     s.Calls,
+		"saw_flop":                  s.SawFlop,
+		"showdowns":                 s.Showdowns,
+		"showdowns_won":             s.ShowdownsWon,
+		"net":                       s.Net,
+	}
+}
+
+// Report - рассчитывает показатели статистики из счетчиков
+func (s *PlayerStats) Report() *PlayerStatsReport {
+	report := &PlayerStatsReport{
+		PlayerStats:           *s,
+		VPIPPercent:           percent(s.VPIP, s.Hands),
+		PFRPercent:            percent(s.PFR, s.Hands),
+		ThreeBetPercent:       percent(s.ThreeBets, s.ThreeBetChances),
+		FoldToThreeBetPercent: percent(s.FoldsToThreeBet, s.FoldToThreeBetChances),
+		WTSDPercent:           percent(s.Showdowns, s.SawFlop),
+		WSDPercent:            percent(s.ShowdownsWon, s.Showdowns),
+	}
+
+	// Без коллов фактор агрессии равен количеству агрессивных действий
+	if s.Calls > 0 {
+		report.AggressionFactor = float64(s.AggressiveActions) / float64(s.Calls)
+	} else {
+		report.AggressionFactor = float64(s.AggressiveActions)
+	}
+
+	return report
+}
+
+// ComputeHandStats - счетчики статистики каждого участника одной раздачи
+// Первый круг торговли (префлоп, в стаде - третья улица) определяет VPIP, PFR, 3-бет и фолд на 3-бет;
+// олл-ин считается повышением, если поднимает ставку улицы. Флоп увидели не сбросившие карты
+// на первом круге, если раздача продолжилась после него; вскрытие - если до конца дошли
+// хотя бы двое игроков. Выигрыш на вскрытии - доля разыгранного на вскрытии банка, а не возврат ставки.
+// Раздача, выигранная сбросом карт соперников, засчитывается в Hands и Net, но не во вскрытия
+func ComputeHandStats(history *HandHistory, actions []HandAction) map[string]*PlayerStats {
+	stats := make(map[string]*PlayerStats, len(history.Players))
+	for _, player := range history.Players {
+		stats[player.UserID] = &PlayerStats{
+			UserID: player.UserID,
+			Hands:  1,
+			Net:    player.Won - player.TotalBet,
+		}
+	}
+
+	vpip := make(map[string]bool)
+	pfr := make(map[string]bool)
+	foldedFirstRound := make(map[string]bool)
+	threeBetChance := make(map[string]bool)
+	foldToThreeBetChance := make(map[string]bool)
+
+	raises := 0
+	opener := ""
+	streetBet := 0
+	phase := GamePhase("")
+	continued := len(history.Board) >= 3
+
+	for _, action := range actions {
+		player := stats[action.UserID]
+		if player == nil {
+			continue
+		}
+
+		if action.Phase != phase {
+			phase = action.Phase
+			streetBet = 0
+		}
+		if action.Action == ActionBlind {
+			if action.Amount > streetBet {
+				streetBet = action.Amount
+			}
+			continue
+		}
+
+		aggressive := action.Action == ActionBet || action.Action == ActionRaise ||
+			(action.Action == ActionAllIn && action.Amount > streetBet)
+		if aggressive {
+			streetBet = action.Amount
+		}
+
+		if !isFirstBettingRound(phase) {
+			continued = true
+			if aggressive {
+				player.AggressiveActions++
+			} else if action.Action == ActionCall || action.Action == ActionAllIn {
+				player.Calls++
+			}
+			continue
+		}
+
+		if action.Action != ActionFold && action.Action != ActionCheck {
+			vpip[action.UserID] = true
+		}
+
+		if raises == 1 && action.UserID != opener && !threeBetChance[action.UserID] {
+			threeBetChance[action.UserID] = true
+			player.ThreeBetChances++
+			if aggressive {
+				player.ThreeBets++
+			}
+		}
+		if raises == 2 && action.UserID == opener && !foldToThreeBetChance[action.UserID] {
+			foldToThreeBetChance[action.UserID] = true
+			player.FoldToThreeBetChances++
+			if action.Action == ActionFold {
+				player.FoldsToThreeBet++
+			}
+		}
+
+		if aggressive {
+			pfr[action.UserID] = true
+			raises++
+			if raises == 1 {
+				opener = action.UserID
+			}
+		}
+		if action.Action == ActionFold {
+			foldedFirstRound[action.UserID] = true
+		}
+	}
+
+	remaining := 0
+	for _, player := range history.Players {
+		if !player.Folded {
+			remaining++
+		}
+	}
+	showdown := remaining >= 2
+	if showdown {
+		continued = true
+	}
+
+	// Выигрышем на вскрытии считается только доля банка, который разыгрывали хотя бы двое:
+	// возврат неуравненной ставки (банк с одним претендентом) выигрышем не является
+	wonShowdown := make(map[string]bool)
+	for _, pot := range history.Pots {
+		if len(pot.EligiblePlayers) < 2 {
+			continue
+		}
+		for userID, share := range pot.HighShares {
+			if share > 0 {
+				wonShowdown[userID] = true
+			}
+		}
+		for userID, share := range pot.LowShares {
+			if share > 0 {
+				wonShowdown[userID] = true
+			}
+		}
+	}
+
+	for _, player := range history.Players {
+		s := stats[player.UserID]
+		if vpip[player.UserID] {
+			s.VPIP = 1
+		}
+		if pfr[player.UserID] {
+			s.PFR = 1
+		}
+		if continued && !foldedFirstRound[player.UserID] {
+			s.SawFlop = 1
+		}
+		if showdown && !player.Folded {
+			s.Showdowns = 1
+			if wonShowdown[player.UserID] {
+				s.ShowdownsWon = 1
+			}
+		}
+	}
+
+	return stats
+}
+
+// isFirstBettingRound - первый круг торговли раздачи (префлоп или третья улица стада)
+func isFirstBettingRound(phase GamePhase) bool {
+	return phase == GamePhasePreFlop || phase == GamePhaseThirdStreet
+}
+
+// percent - доля part от total в процентах (0, если total равен нулю)
+func percent(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeHandStats(t *testing.T) {
+	board := []string{"As", "Kd", "7h", "2c", "9s"}
+	act := func(userID string, phase GamePhase, action PlayerAction, amount int) HandAction {
+		return HandAction{GameID: "g1", UserID: userID, Phase: phase, Action: action, Amount: amount}
+	}
+
+	tests := []struct {
+		name    string
+		history *HandHistory
+		actions []HandAction
+		want    map[string]PlayerStats
+	}{
+		{
+			name: "раздача выиграна сбросом карт до флопа",
+			history: &HandHistory{
+				GameID: "g1",
+				Players: []HandHistoryPlayer{
+					{UserID: "a", TotalBet: 10, Folded: true},
+					{UserID: "b", TotalBet: 20, Folded: true},
+					{UserID: "c", TotalBet: 60, Won: 90},
+				},
+			},
+			actions: []HandAction{
+				act("a", GamePhasePreFlop, ActionBlind, 10),
+				act("b", GamePhasePreFlop, ActionBlind, 20),
+				act("c", GamePhasePreFlop, ActionRaise, 60),
+				act("a", GamePhasePreFlop, ActionFold, 10),
+				act("b", GamePhasePreFlop, ActionFold, 20),
+			},
+			want: map[string]PlayerStats{
+				"a": {UserID: "a", Hands: 1, ThreeBetChances: 1, Net: -10},
+				"b": {UserID: "b", Hands: 1, ThreeBetChances: 1, Net: -20},
+				"c": {UserID: "c", Hands: 1, VPIP: 1, PFR: 1, Net: 30},
+			},
+		},
+		{
+			name: "3-бет и сброс на него",
+			history: &HandHistory{
+				GameID: "g1",
+				Players: []HandHistoryPlayer{
+					{UserID: "a", TotalBet: 10, Folded: true},
+					{UserID: "b", TotalBet: 180, Won: 250},
+					{UserID: "c", TotalBet: 60, Folded: true},
+				},
+			},
+			actions: []HandAction{
+				act("a", GamePhasePreFlop, ActionBlind, 10),
+				act("b", GamePhasePreFlop, ActionBlind, 20),
+				act("c", GamePhasePreFlop, ActionRaise, 60),
+				act("a", GamePhasePreFlop, ActionFold, 10),
+				act("b", GamePhasePreFlop, ActionRaise, 180),
+				act("c", GamePhasePreFlop, ActionFold, 60),
+			},
+			want: map[string]PlayerStats{
+				"a": {UserID: "a", Hands: 1, ThreeBetChances: 1, Net: -10},
+				"b": {UserID: "b", Hands: 1, VPIP: 1, PFR: 1, ThreeBetChances: 1, ThreeBets: 1, Net: 70},
+				"c": {UserID: "c", Hands: 1, VPIP: 1, PFR: 1, FoldToThreeBetChances: 1, FoldsToThreeBet: 1, Net: -60},
+			},
+		},
+		{
+			name: "агрессия после флопа и сброс на терне",
+			history: &HandHistory{
+				GameID: "g1",
+				Board:  board[:4],
+				Players: []HandHistoryPlayer{
+					{UserID: "a", TotalBet: 180, Won: 260},
+					{UserID: "b", TotalBet: 80, Folded: true},
+				},
+			},
+			actions: []HandAction{
+				act("a", GamePhasePreFlop, ActionBlind, 10),
+				act("b", GamePhasePreFlop, ActionBlind, 20),
+				act("a", GamePhasePreFlop, ActionCall, 20),
+				act("b", GamePhasePreFlop, ActionCheck, 20),
+				act("b", GamePhaseFlop, ActionBet, 20),
+				act("a", GamePhaseFlop, ActionRaise, 60),
+				act("b", GamePhaseFlop, ActionCall, 60),
+				act("b", GamePhaseTurn, ActionCheck, 0),
+				act("a", GamePhaseTurn, ActionBet, 100),
+				act("b", GamePhaseTurn, ActionFold, 0),
+			},
+			want: map[string]PlayerStats{
+				"a": {UserID: "a", Hands: 1, VPIP: 1, AggressiveActions: 2, SawFlop: 1, Net: 80},
+				"b": {UserID: "b", Hands: 1, AggressiveActions: 1, Calls: 1, SawFlop: 1, Net: -80},
+			},
+		},
+		{
+			name: "возврат неуравненной ставки на вскрытии не считается выигрышем",
+			history: &HandHistory{
+				GameID: "g1",
+				Board:  board,
+				Players: []HandHistoryPlayer{
+					{UserID: "a", TotalBet: 200, Won: 150},
+					{UserID: "b", TotalBet: 50, Won: 100},
+				},
+				Pots: []PotSettlement{
+					{Amount: 100, EligiblePlayers: []string{"a", "b"}, HighWinners: []string{"b"}, HighShares: map[string]int{"b": 100}},
+					{Amount: 150, EligiblePlayers: []string{"a"}, HighWinners: []string{"a"}, HighShares: map[string]int{"a": 150}},
+				},
+			},
+			actions: []HandAction{
+				act("a", GamePhasePreFlop, ActionBlind, 10),
+				act("b", GamePhasePreFlop, ActionBlind, 20),
+				act("a", GamePhasePreFlop, ActionRaise, 200),
+				act("b", GamePhasePreFlop, ActionAllIn, 50),
+			},
+			want: map[string]PlayerStats{
+				"a": {UserID: "a", Hands: 1, VPIP: 1, PFR: 1, SawFlop: 1, Showdowns: 1, Net: -50},
+				"b": {UserID: "b", Hands: 1, VPIP: 1, ThreeBetChances: 1, SawFlop: 1, Showdowns: 1, ShowdownsWon: 1, Net: 50},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeHandStats(tt.history, tt.actions)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ComputeHandStats() вернул %d игроков, want %d", len(got), len(tt.want))
+			}
+			for userID, want := range tt.want {
+				if got[userID] == nil || !reflect.DeepEqual(*got[userID], want) {
+					t.Errorf("ComputeHandStats()[%s] = %+v, want %+v", userID, got[userID], want)
+				}
+			}
+		})
+	}
+}
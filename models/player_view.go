@@ -0,0 +1,57 @@
+package models
+
+// HiddenCard - обозначение закрытой карты в представлении для других игроков
+const HiddenCard = "XX"
+
+// PlayerView - состояние игрока, которое видит конкретный зритель
+// Свои закрытые карты игрок видит полностью, чужие закрытые карты скрыты
+// (остается только их количество), открытые карты стада видны всем
+type PlayerView struct {
+	UserID   string       `json:"user_id"`
+	Username string       `json:"username"`
+	Position int          `json:"position"`
+	Chips    int          `json:"chips"`
+	Bet      int          `json:"bet"`
+	TotalBet int          `json:"total_bet"`
+	Status   PlayerStatus `json:"status"`
+
+	// Закрытые карты (для чужих игроков каждая карта заменена на HiddenCard)
+	Cards []string `json:"cards"`
+
+	// Открытые карты (стад)
+	UpCards []string `json:"up_cards,omitempty"`
+
+	IsDealer     bool `json:"is_dealer"`
+	IsSmallBlind bool `json:"is_small_blind"`
+	IsBigBlind   bool `json:"is_big_blind"`
+}
+
+// ViewFor - возвращает состояние игрока, видимое зрителю viewerID
+// revealed - карты вскрыты (на вскрытии закрытые карты несбросивших игроков видны всем)
+func (p *Player) ViewFor(viewerID string, revealed bool) PlayerView {
+	view := PlayerView{
+		UserID:       p.UserID,
+		Username:     p.Username,
+		Position:     p.Position,
+		Chips:        p.Chips,
+		Bet:          p.Bet,
+		TotalBet:     p.TotalBet,
+		Status:       p.Status,
+		UpCards:      p.UpCards,
+		IsDealer:     p.IsDealer,
+		IsSmallBlind: p.IsSmallBlind,
+		IsBigBlind:   p.IsBigBlind,
+	}
+
+	if viewerID == p.UserID || (revealed && !p.IsFolded()) {
+		view.Cards = p.Cards
+		return view
+	}
+
+	view.Cards = make([]string, len(p.Cards))
+	for i := range view.Cards {
+		view.Cards[i] = HiddenCard
+	}
+
+	return view
+}
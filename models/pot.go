@@ -0,0 +1,247 @@
+package models
+
+import (
+	"sort"
+)
+
+// PotContribution - вклад игрока в банк за раздачу
+// Используется для построения основного и боковых банков
+type PotContribution struct {
+	// ID игрока
+	UserID string
+
+	// Сколько фишек игрок внес в банк за всю раздачу
+	Amount int
+
+	// Мертвые фишки, внесенные игроком за весь стол (анте дилера или большого блайнда)
+	// Не входят в Amount и целиком идут в основной банк
+	DeadMoney int
+
+	// Сбросил ли игрок карты (его фишки остаются в банке, но он не претендует на него)
+	Folded bool
+}
+
+// BuildPots - строит основной банк и боковые банки по вкладам игроков
+// Первый элемент результата - основной банк, остальные - боковые
+//
+// Алгоритм: банки "нарезаются" по уровням вкладов игроков, которые не сбросили карты.
+// На каждом уровне в банк попадают фишки всех игроков (включая сбросивших) между
+// предыдущим и текущим уровнем, а претендуют на него только не сбросившие игроки,
+// внесшие не меньше текущего уровня.
+// Мертвые фишки (анте за весь стол) добавляются в основной банк: на них претендуют
+// все не сбросившие игроки, даже не внесшие ничего сверх анте
+func BuildPots(contributions []PotContribution) []SidePot {
+	// Собираем уникальные уровни вкладов активных игроков
+	levelSet := make(map[int]bool)
+	for _, c := range contributions {
+		if !c.Folded && c.Amount > 0 {
+			levelSet[c.Amount] = true
+		}
+	}
+
+	levels := make([]int, 0, len(levelSet))
+	for level := range levelSet {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	pots := make([]SidePot, 0, len(levels))
+	previousLevel := 0
+
+	for _, level := range levels {
+		pot := SidePot{EligiblePlayers: []string{}}
+
+		for _, c := range contributions {
+			// Часть вклада игрока между предыдущим и текущим уровнем
+			pot.Amount += minInt(c.Amount, level) - minInt(c.Amount, previousLevel)
+
+			if !c.Folded && c.Amount >= level {
+				pot.EligiblePlayers = append(pot.EligiblePlayers, c.UserID)
+			}
+		}
+
+		// Если претенденты те же, что и в предыдущем банке - объединяем банки
+		if len(pots) > 0 && sameEligiblePlayers(pots[len(pots)-1].EligiblePlayers, pot.EligiblePlayers) {
+			pots[len(pots)-1].Amount += pot.Amount
+		} else if pot.Amount > 0 {
+			pots = append(pots, pot)
+		}
+
+		previousLevel = level
+	}
+
+	// Фишки сбросивших игроков сверх максимального уровня добавляем в последний банк
+	dead := 0
+	for _, c := range contributions {
+		if c.Amount > previousLevel {
+			dead += c.Amount - previousLevel
+		}
+	}
+	if dead > 0 {
+		if len(pots) == 0 {
+			pots = append(pots, SidePot{EligiblePlayers: []string{}})
+		}
+		pots[len(pots)-1].Amount += dead
+	}
+
+	return addDeadMoney(pots, contributions)
+}
+
+// addDeadMoney - добавляет мертвые фишки (анте за весь стол) в основной банк
+// Если кто-то из не сбросивших игроков ничего не внес сверх анте (all-in на анте),
+// на основной банк он не претендует, поэтому мертвые фишки выделяются в отдельный
+// первый банк, на который претендуют все не сбросившие игроки
+func addDeadMoney(pots []SidePot, contributions []PotContribution) []SidePot {
+	deadMoney := 0
+	allLive := true
+	eligible := []string{}
+
+	for _, c := range contributions {
+		deadMoney += c.DeadMoney
+		if c.Folded {
+			continue
+		}
+		eligible = append(eligible, c.UserID)
+		if c.Amount == 0 {
+			allLive = false
+		}
+	}
+
+	if deadMoney == 0 {
+		return pots
+	}
+
+	if allLive && len(pots) > 0 {
+		pots[0].Amount += deadMoney
+		return pots
+	}
+
+	return append([]SidePot{{Amount: deadMoney, EligiblePlayers: eligible}}, pots...)
+}
+
+// SplitPot - делит банк поровну между победителями
+// Нераспределимые фишки (odd chips) отдаются первым победителям в списке,
+// поэтому вызывающий код должен передавать победителей в порядке мест от дилера
+func SplitPot(amount int, winners []string) map[string]int {
+	shares := make(map[string]int)
+	if len(winners) == 0 || amount <= 0 {
+		return shares
+	}
+
+	share := amount / len(winners)
+	remainder := amount % len(winners)
+
+	for i, userID := range winners {
+		shares[userID] += share
+		if i < remainder {
+			shares[userID]++
+		}
+	}
+
+	return shares
+}
+
+// PotWinners - победители банка
+// В хай-лоу банк делится между старшей (High) и младшей (Low) рукой;
+// если младшая рука не прошла, Low пустой и старшая рука забирает весь банк
+type PotWinners struct {
+	High []string `json:"high"`
+	Low  []string `json:"low,omitempty"`
+}
+
+// PotSettlement - итог розыгрыша одного банка
+type PotSettlement struct {
+	// Сумма банка после рейка
+	Amount int `json:"amount"`
+
+	// Рейк, взятый с банка
+	Rake int `json:"rake"`
+
+	// Претенденты на банк
+	EligiblePlayers []string `json:"eligible_players"`
+
+	// Победители старшей и младшей половины
+	HighWinners []string `json:"high_winners"`
+	LowWinners  []string `json:"low_winners,omitempty"`
+
+	// Выигрыш каждого победителя в старшей и младшей половине
+	HighShares map[string]int `json:"high_shares"`
+	LowShares  map[string]int `json:"low_shares,omitempty"`
+
+	// Номер прогона доски (1, 2, 3), 0 - доска прогонялась один раз
+	Run int `json:"run,omitempty"`
+}
+
+// HandSettlement - итог розыгрыша всех банков раздачи
+type HandSettlement struct {
+	// Банки раздачи (первый - основной)
+	Pots []PotSettlement `json:"pots"`
+
+	// Общий выигрыш каждого игрока
+	Payouts map[string]int `json:"payouts"`
+
+	// Общий рейк раздачи
+	Rake int `json:"rake"`
+}
+
+// SplitHiLo - делит банк между старшей и младшей рукой
+// Нечетная фишка достается старшей половине. Если младшая рука не прошла
+// (lowWinners пустой) - старшая рука забирает весь банк (scoop).
+// Каждая половина делится между своими победителями через SplitPot,
+// поэтому ничья в младшей руке дает "четвертование" банка
+func SplitHiLo(amount int, highWinners, lowWinners []string) (map[string]int, map[string]int) {
+	if len(lowWinners) == 0 {
+		return SplitPot(amount, highWinners), map[string]int{}
+	}
+
+	lowHalf := amount / 2
+	highHalf := amount - lowHalf
+
+	return SplitPot(highHalf, highWinners), SplitPot(lowHalf, lowWinners)
+}
+
+// IsContested - проверяет, претендует ли на банк больше одного игрока
+// Банк с одним претендентом - это возврат неуравненной ставки
+func (sp *SidePot) IsContested() bool {
+	return len(sp.EligiblePlayers) > 1
+}
+
+// IsEligible - проверяет, претендует ли игрок на банк
+func (sp *SidePot) IsEligible(userID string) bool {
+	for _, id := range sp.EligiblePlayers {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPotsTotal - возвращает общую сумму всех банков
+func GetPotsTotal(pots []SidePot) int {
+	total := 0
+	for _, pot := range pots {
+		total += pot.Amount
+	}
+	return total
+}
+
+// sameEligiblePlayers - проверяет, совпадают ли списки претендентов
+func sameEligiblePlayers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// minInt - возвращает минимальное из двух чисел
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildPots(t *testing.T) {
+	tests := []struct {
+		name          string
+		contributions []PotContribution
+		want          []SidePot
+	}{
+		{
+			name: "равные вклады - один банк",
+			contributions: []PotContribution{
+				{UserID: "a", Amount: 100},
+				{UserID: "b", Amount: 100},
+				{UserID: "c", Amount: 100},
+			},
+			want: []SidePot{{Amount: 300, EligiblePlayers: []string{"a", "b", "c"}}},
+		},
+		{
+			name: "короткий олл-ин - основной и боковой банк",
+			contributions: []PotContribution{
+				{UserID: "a", Amount: 50},
+				{UserID: "b", Amount: 200},
+				{UserID: "c", Amount: 200},
+			},
+			want: []SidePot{
+				{Amount: 150, EligiblePlayers: []string{"a", "b", "c"}},
+				{Amount: 300, EligiblePlayers: []string{"b", "c"}},
+			},
+		},
+		{
+			name: "фишки сбросившего игрока делятся по уровням",
+			contributions: []PotContribution{
+				{UserID: "a", Amount: 50},
+				{UserID: "b", Amount: 200},
+				{UserID: "c", Amount: 120, Folded: true},
+			},
+			want: []SidePot{
+				{Amount: 150, EligiblePlayers: []string{"a", "b"}},
+				{Amount: 220, EligiblePlayers: []string{"b"}},
+			},
+		},
+		{
+			name: "мертвые фишки идут в основной банк",
+			contributions: []PotContribution{
+				{UserID: "a", Amount: 100, DeadMoney: 30},
+				{UserID: "b", Amount: 100},
+			},
+			want: []SidePot{{Amount: 230, EligiblePlayers: []string{"a", "b"}}},
+		},
+		{
+			name: "олл-ин на анте - мертвые фишки в отдельном первом банке",
+			contributions: []PotContribution{
+				{UserID: "a", Amount: 0, DeadMoney: 20},
+				{UserID: "b", Amount: 100},
+				{UserID: "c", Amount: 100},
+			},
+			want: []SidePot{
+				{Amount: 20, EligiblePlayers: []string{"a", "b", "c"}},
+				{Amount: 200, EligiblePlayers: []string{"b", "c"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildPots(tt.contributions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildPots() = %+v, want %+v", got, tt.want)
+			}
+
+			total := 0
+			for _, c := range tt.contributions {
+				total += c.Amount + c.DeadMoney
+			}
+			if GetPotsTotal(got) != total {
+				t.Errorf("сумма банков = %d, want %d", GetPotsTotal(got), total)
+			}
+		})
+	}
+}
+
+func TestSplitPot(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int
+		winners []string
+		want    map[string]int
+	}{
+		{
+			name:    "один победитель",
+			amount:  100,
+			winners: []string{"a"},
+			want:    map[string]int{"a": 100},
+		},
+		{
+			name:    "нечетная фишка первому от дилера",
+			amount:  100,
+			winners: []string{"b", "a", "c"},
+			want:    map[string]int{"b": 34, "a": 33, "c": 33},
+		},
+		{
+			name:    "две нечетные фишки",
+			amount:  101,
+			winners: []string{"a", "b", "c"},
+			want:    map[string]int{"a": 34, "b": 34, "c": 33},
+		},
+		{
+			name:    "без победителей",
+			amount:  100,
+			winners: nil,
+			want:    map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitPot(tt.amount, tt.winners); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPot(%d, %v) = %v, want %v", tt.amount, tt.winners, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitHiLo(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int
+		high     []string
+		low      []string
+		wantHigh map[string]int
+		wantLow  map[string]int
+	}{
+		{
+			name:     "младшая рука не прошла - старшая забирает весь банк",
+			amount:   75,
+			high:     []string{"a", "b"},
+			wantHigh: map[string]int{"a": 38, "b": 37},
+			wantLow:  map[string]int{},
+		},
+		{
+			name:     "нечетная фишка старшей половине",
+			amount:   101,
+			high:     []string{"a"},
+			low:      []string{"b"},
+			wantHigh: map[string]int{"a": 51},
+			wantLow:  map[string]int{"b": 50},
+		},
+		{
+			name:     "четвертование",
+			amount:   100,
+			high:     []string{"a"},
+			low:      []string{"a", "b"},
+			wantHigh: map[string]int{"a": 50},
+			wantLow:  map[string]int{"a": 25, "b": 25},
+		},
+		{
+			name:     "нечетная фишка младшей половины первому от дилера",
+			amount:   103,
+			high:     []string{"a"},
+			low:      []string{"c", "b"},
+			wantHigh: map[string]int{"a": 52},
+			wantLow:  map[string]int{"c": 26, "b": 25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			high, low := SplitHiLo(tt.amount, tt.high, tt.low)
+			if !reflect.DeepEqual(high, tt.wantHigh) || !reflect.DeepEqual(low, tt.wantLow) {
+				t.Errorf("SplitHiLo(%d, %v, %v) = %v, %v, want %v, %v",
+					tt.amount, tt.high, tt.low, high, low, tt.wantHigh, tt.wantLow)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package models
+
+// RakeRecord - запись о рейке, взятом с одной раздачи
+// Хранится в истории рейка клуба (JSON)
+type RakeRecord struct {
+	// ID клуба
+	ClubID string `json:"club_id"`
+
+	// ID комнаты
+	RoomID string `json:"room_id"`
+
+	// ID игры
+	GameID string `json:"game_id"`
+
+	// Номер раздачи
+	RoundNumber int `json:"round_number"`
+
+	// Количество игроков, получивших карты
+	PlayersDealt int `json:"players_dealt"`
+
+	// Сумма всех разыгранных банков до рейка
+	TotalPot int `json:"total_pot"`
+
+	// Общий рейк с раздачи
+	Amount int `json:"amount"`
+
+	// Рейк с каждого банка (индекс 0 - основной банк)
+	PotRakes []int `json:"pot_rakes"`
+
+	// Временная метка (Unix timestamp в секундах)
+	Timestamp int64 `json:"timestamp"`
+}
+
+// HasRake - проверяет, берется ли рейк в комнате
+func (r *Room) HasRake() bool {
+	return r.RakePercent > 0
+}
+
+// GetRakeCap - возвращает кап рейка для указанного количества игроков в раздаче
+// Берется кап с наибольшим порогом, не превышающим количество игроков.
+// Если подходящего порога нет - используется кап по умолчанию (RakeCap)
+func (r *Room) GetRakeCap(playersDealt int) int {
+	rakeCap := r.RakeCap
+	bestThreshold := -1
+
+	for threshold, value := range r.RakeCapsByPlayers {
+		if threshold <= playersDealt && threshold > bestThreshold {
+			bestThreshold = threshold
+			rakeCap = value
+		}
+	}
+
+	return rakeCap
+}
+
+// CalculateRake - рассчитывает рейк с суммы разыгрываемых банков
+// Параметры:
+//   - rakeablePot: сумма банков, на которые претендует больше одного игрока
+//   - playersDealt: количество игроков, получивших карты
+//   - flopDealt: был ли открыт флоп (в стаде и рэззе - прошла ли раздача дальше третьей улицы)
+//
+// Возвращает 0, если рейк выключен или сработало правило no flop, no drop
+func (r *Room) CalculateRake(rakeablePot, playersDealt int, flopDealt bool) int {
+	if !r.HasRake() || rakeablePot <= 0 {
+		return 0
+	}
+
+	if r.NoFlopNoDrop && !flopDealt {
+		return 0
+	}
+
+	// Рейк округляется вниз до целой фишки
+	rake := int(float64(rakeablePot) * r.RakePercent / 100)
+
+	if rakeCap := r.GetRakeCap(playersDealt); rakeCap > 0 && rake > rakeCap {
+		rake = rakeCap
+	}
+
+	return rake
+}
+
+// DistributeRake - распределяет рейк между банками пропорционально их размеру
+// Банки с одним претендентом (возврат неуравненной ставки) не облагаются рейком.
+// Округление: остаток после пропорционального деления берется с основного банка
+// (первого банка, на который претендует больше одного игрока)
+// Возвращает сумму рейка для каждого банка (в том же порядке)
+func DistributeRake(pots []SidePot, rake int) []int {
+	potRakes := make([]int, len(pots))
+	if rake <= 0 {
+		return potRakes
+	}
+
+	rakeable := GetRakeablePot(pots)
+	if rakeable == 0 {
+		return potRakes
+	}
+
+	distributed := 0
+	firstContested := -1
+	for i, pot := range pots {
+		if !pot.IsContested() {
+			continue
+		}
+		if firstContested == -1 {
+			firstContested = i
+		}
+		potRakes[i] = rake * pot.Amount / rakeable
+		distributed += potRakes[i]
+	}
+
+	potRakes[firstContested] += rake - distributed
+
+	return potRakes
+}
+
+// GetRakeablePot - возвращает сумму банков, облагаемых рейком
+func GetRakeablePot(pots []SidePot) int {
+	total := 0
+	for _, pot := range pots {
+		if pot.IsContested() {
+			total += pot.Amount
+		}
+	}
+	return total
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"strconv"
 )
 
@@ -33,6 +34,12 @@ type Room struct {
 	// ID клуба, которому принадлежит комната
 	ClubID string
 
+	// ID владельца комнаты (может назначать бомб-поты)
+	OwnerID string
+
+	// ID турнира, который играется в комнате (пусто - кэш-игра)
+	TournamentID string
+
 	// Ключ комнаты (уникальный идентификатор)
 	Key string
 
@@ -65,6 +72,75 @@ type Room struct {
 
 	// Количество наблюдателей
 	CurrentSpectators int
+
+	// Процент рейка, который берется с каждого банка (например: 5 = 5%, 0 - без рейка)
+	RakePercent float64
+
+	// Кап рейка по умолчанию (максимальный рейк с раздачи, 0 - без капа)
+	RakeCap int
+
+	// Капы рейка в зависимости от количества игроков, получивших карты
+	// Ключ - минимальное количество игроков, значение - кап (например: {2: 10, 5: 30})
+	RakeCapsByPlayers map[int]int
+
+	// No flop, no drop - не брать рейк, если раздача закончилась до флопа
+	NoFlopNoDrop bool
+
+	// Разновидность покера (holdem, omaha, omaha_hilo)
+	GameType GameType
+
+	// Структура ставок (no_limit, pot_limit, fixed_limit)
+	BettingStructure BettingStructure
+
+	// Малая и большая ставка фикс-лимита (0 - большой блайнд и два больших блайнда)
+	SmallBet int
+	BigBet   int
+
+	// Максимум ставок и повышений в раунде фикс-лимита (0 - DefaultRaiseCap)
+	RaiseCap int
+
+	// Вид обязательных ставок (blinds, button_ante)
+	ForcedBets ForcedBetType
+
+	// Размер анте дилера (0 - большой блайнд)
+	ButtonAnte int
+
+	// Размер bring-in в стаде (0 - малый блайнд)
+	BringIn int
+
+	// Вид анте (none, per_player, button, big_blind) и его размер:
+	// для per_player - анте каждого игрока, для button и big_blind - анте за весь стол
+	AnteType AnteType
+	Ante     int
+
+	// Разрешены стрэддл UTG (игрок после большого блайнда) и стрэддл дилера (Mississippi)
+	UTGStraddle    bool
+	ButtonStraddle bool
+
+	// Максимум стрэддлов в раздаче, включая рестрэддлы (0 - DefaultMaxStraddles)
+	MaxStraddles int
+
+	// Сколько раз можно прогнать доску при олл-ине (0 или 1 - прогон запрещен, максимум MaxBoardRuns)
+	MaxRuns int
+
+	// Бомб-пот каждые N раздач (0 - без расписания)
+	BombPotEvery int
+
+	// Анте бомб-пота с каждого игрока (0 - большой блайнд)
+	BombPotAnte int
+
+	// Бомб-пот раздается на две доски, которые делят банк пополам
+	BombPotDoubleBoard bool
+
+	// Игроки могут назначить бомб-пот голосованием большинства
+	BombPotVoting bool
+
+	// Ротация разновидностей смешанной игры (пусто - комната играет одну разновидность)
+	MixedGame []MixedGameVariant
+
+	// Правило смены разновидности (hands, orbit) и количество раздач при смене по раздачам
+	RotationMode  RotationMode
+	RotationHands int
 }
 
 // RoomInfo - упрощенная структура для информации о комнате из Redis
@@ -72,6 +148,7 @@ type Room struct {
 type RoomInfo struct {
 	RoomID     string     `json:"room_id"`
 	ClubID     string     `json:"club_id"`
+	OwnerID    string     `json:"owner_id"`
 	Key        string     `json:"key"`
 	MaxPlayers string     `json:"max_players"`
 	SmallBlind string     `json:"small_blind"`
@@ -81,6 +158,36 @@ type RoomInfo struct {
 	Currency   string     `json:"currency"`
 	Status     RoomStatus `json:"status"`
 	CreatedAt  string     `json:"created_at"`
+
+	RakePercent  string `json:"rake_percent"`
+	RakeCap      string `json:"rake_cap"`
+	RakeCaps     string `json:"rake_caps"` // JSON объект {"2": 10, "5": 30}
+	NoFlopNoDrop string `json:"no_flop_no_drop"`
+
+	GameType         string `json:"game_type"`
+	BettingStructure string `json:"betting_structure"`
+	SmallBet         string `json:"small_bet"`
+	BigBet           string `json:"big_bet"`
+	RaiseCap         string `json:"raise_cap"`
+	ForcedBets       string `json:"forced_bets"`
+	ButtonAnte       string `json:"button_ante"`
+	BringIn          string `json:"bring_in"`
+	AnteType         string `json:"ante_type"`
+	Ante             string `json:"ante"`
+	UTGStraddle      string `json:"utg_straddle"`
+	ButtonStraddle   string `json:"button_straddle"`
+	MaxStraddles     string `json:"max_straddles"`
+	MaxRuns          string `json:"max_runs"`
+	MixedGame        string `json:"mixed_game"` // JSON массив
+	RotationMode     string `json:"rotation_mode"`
+	RotationHands    string `json:"rotation_hands"`
+
+	BombPotEvery       string `json:"bomb_pot_every"`
+	BombPotAnte        string `json:"bomb_pot_ante"`
+	BombPotDoubleBoard string `json:"bomb_pot_double_board"`
+	BombPotVoting      string `json:"bomb_pot_voting"`
+
+	TournamentID string `json:"tournament_id"`
 }
 
 // NewRoomFromRedis - создает Room из данных Redis hash
@@ -92,9 +199,45 @@ func NewRoomFromRedis(data map[string]string) (*Room, error) {
 	buyInMin, _ := strconv.Atoi(data["buy_in_min"])
 	buyInMax, _ := strconv.Atoi(data["buy_in_max"])
 
+	// Парсим настройки рейка
+	rakePercent, _ := strconv.ParseFloat(data["rake_percent"], 64)
+	rakeCap, _ := strconv.Atoi(data["rake_cap"])
+	noFlopNoDrop := data["no_flop_no_drop"] == "true" || data["no_flop_no_drop"] == "1"
+
+	// Параметры фикс-лимита
+	smallBet, _ := strconv.Atoi(data["small_bet"])
+	bigBet, _ := strconv.Atoi(data["big_bet"])
+	raiseCap, _ := strconv.Atoi(data["raise_cap"])
+	buttonAnte, _ := strconv.Atoi(data["button_ante"])
+	bringIn, _ := strconv.Atoi(data["bring_in"])
+	ante, _ := strconv.Atoi(data["ante"])
+	maxStraddles, _ := strconv.Atoi(data["max_straddles"])
+	maxRuns, _ := strconv.Atoi(data["max_runs"])
+	bombPotEvery, _ := strconv.Atoi(data["bomb_pot_every"])
+	bombPotAnte, _ := strconv.Atoi(data["bomb_pot_ante"])
+	bombPotDoubleBoard := data["bomb_pot_double_board"] == "true" || data["bomb_pot_double_board"] == "1"
+	bombPotVoting := data["bomb_pot_voting"] == "true" || data["bomb_pot_voting"] == "1"
+	utgStraddle := data["utg_straddle"] == "true" || data["utg_straddle"] == "1"
+	buttonStraddle := data["button_straddle"] == "true" || data["button_straddle"] == "1"
+	rotationHands, _ := strconv.Atoi(data["rotation_hands"])
+
+	// Ротация смешанной игры хранится как JSON массив
+	var mixedGame []MixedGameVariant
+	if data["mixed_game"] != "" {
+		json.Unmarshal([]byte(data["mixed_game"]), &mixedGame)
+	}
+	gameType := ParseGameType(data["game_type"])
+
+	// Капы по количеству игроков хранятся как JSON объект
+	var rakeCapsByPlayers map[int]int
+	if data["rake_caps"] != "" {
+		json.Unmarshal([]byte(data["rake_caps"]), &rakeCapsByPlayers)
+	}
+
 	return &Room{
 		RoomID:     data["room_id"],
 		ClubID:     data["club_id"],
+		OwnerID:    data["owner_id"],
 		Key:        data["key"],
 		MaxPlayers: maxPlayers,
 		SmallBlind: smallBlind,
@@ -104,14 +247,45 @@ func NewRoomFromRedis(data map[string]string) (*Room, error) {
 		Currency:   data["currency"],
 		Status:     RoomStatus(data["status"]),
 		CreatedAt:  data["created_at"],
+
+		RakePercent:       rakePercent,
+		RakeCap:           rakeCap,
+		RakeCapsByPlayers: rakeCapsByPlayers,
+		NoFlopNoDrop:      noFlopNoDrop,
+
+		GameType:         gameType,
+		BettingStructure: ParseBettingStructure(data["betting_structure"], gameType),
+		SmallBet:         smallBet,
+		BigBet:           bigBet,
+		RaiseCap:         raiseCap,
+		ForcedBets:       ParseForcedBetType(data["forced_bets"]),
+		ButtonAnte:       buttonAnte,
+		BringIn:          bringIn,
+		AnteType:         ParseAnteType(data["ante_type"]),
+		Ante:             ante,
+		UTGStraddle:      utgStraddle,
+		ButtonStraddle:   buttonStraddle,
+		MaxStraddles:     maxStraddles,
+		MaxRuns:          maxRuns,
+		MixedGame:        mixedGame,
+		RotationMode:     ParseRotationMode(data["rotation_mode"]),
+		RotationHands:    rotationHands,
+
+		BombPotEvery:       bombPotEvery,
+		BombPotAnte:        bombPotAnte,
+		BombPotDoubleBoard: bombPotDoubleBoard,
+		BombPotVoting:      bombPotVoting,
+
+		TournamentID: data["tournament_id"],
 	}, nil
 }
 
 // ToRedisHash - преобразует Room в map для сохранения в Redis hash
 func (r *Room) ToRedisHash() map[string]interface{} {
-	return map[string]interface{}{
+	hash := map[string]interface{}{
 		"room_id":     r.RoomID,
 		"club_id":     r.ClubID,
+		"owner_id":    r.OwnerID,
 		"key":         r.Key,
 		"max_players": r.MaxPlayers,
 		"small_blind": r.SmallBlind,
@@ -121,7 +295,45 @@ func (r *Room) ToRedisHash() map[string]interface{} {
 		"currency":    r.Currency,
 		"status":      string(r.Status),
 		"created_at":  r.CreatedAt,
+
+		"rake_percent":    strconv.FormatFloat(r.RakePercent, 'f', -1, 64),
+		"rake_cap":        r.RakeCap,
+		"no_flop_no_drop": r.NoFlopNoDrop,
+
+		"game_type":         string(r.GameType),
+		"betting_structure": string(r.BettingStructure),
+		"small_bet":         r.SmallBet,
+		"big_bet":           r.BigBet,
+		"raise_cap":         r.RaiseCap,
+		"forced_bets":       string(r.ForcedBets),
+		"button_ante":       r.ButtonAnte,
+		"bring_in":          r.BringIn,
+		"ante_type":         string(r.AnteType),
+		"ante":              r.Ante,
+		"utg_straddle":      r.UTGStraddle,
+		"button_straddle":   r.ButtonStraddle,
+		"max_straddles":     r.MaxStraddles,
+		"max_runs":          r.MaxRuns,
+		"rotation_mode":     string(r.RotationMode),
+		"rotation_hands":    r.RotationHands,
+
+		"bomb_pot_every":        r.BombPotEvery,
+		"bomb_pot_ante":         r.BombPotAnte,
+		"bomb_pot_double_board": r.BombPotDoubleBoard,
+		"bomb_pot_voting":       r.BombPotVoting,
+
+		"tournament_id": r.TournamentID,
 	}
+
+	// Капы рейка как JSON
+	capsJSON, _ := json.Marshal(r.RakeCapsByPlayers)
+	hash["rake_caps"] = string(capsJSON)
+
+	// Ротация смешанной игры как JSON
+	mixedJSON, _ := json.Marshal(r.MixedGame)
+	hash["mixed_game"] = string(mixedJSON)
+
+	return hash
 }
 
 // === МЕТОДЫ ДЛЯ ВАЛИДАЦИИ ===
@@ -153,6 +365,138 @@ func (r *Room) IsFull() bool {
 	return r.CurrentPlayers >= r.MaxPlayers
 }
 
+// HoleCardsCount - возвращает количество закрытых карт игрока в разновидности покера комнаты
+func (r *Room) HoleCardsCount() int {
+	return r.GameType.HoleCardsCount()
+}
+
+// IsPotLimit - проверяет, ограничены ли ставки размером банка
+func (r *Room) IsPotLimit() bool {
+	return r.BettingStructure == BettingPotLimit
+}
+
+// IsFixedLimit - проверяет, фиксированы ли размеры ставок
+func (r *Room) IsFixedLimit() bool {
+	return r.BettingStructure == BettingFixedLimit
+}
+
+// FixedBetSize - размер ставки фикс-лимита в раунде торговли
+// Малая ставка - на префлопе и флопе, большая - на терне и ривере
+// В стаде малая ставка - на третьей и четвертой улицах, большая - с пятой улицы
+func (r *Room) FixedBetSize(phase GamePhase) int {
+	switch phase {
+	case GamePhaseTurn, GamePhaseRiver, GamePhaseFifthStreet, GamePhaseSixthStreet, GamePhaseSeventhStreet:
+		if r.BigBet > 0 {
+			return r.BigBet
+		}
+		return r.BigBlind * 2
+	default:
+		if r.SmallBet > 0 {
+			return r.SmallBet
+		}
+		return r.BigBlind
+	}
+}
+
+// GetRaiseCap - максимум ставок и повышений в раунде фикс-лимита
+func (r *Room) GetRaiseCap() int {
+	if r.RaiseCap > 0 {
+		return r.RaiseCap
+	}
+	return DefaultRaiseCap
+}
+
+// HasAntes - проверяет, ставятся ли в раздачах анте
+func (r *Room) HasAntes() bool {
+	return r.AnteType != AnteNone && r.Ante > 0
+}
+
+// AllowsStraddle - проверяет, разрешены ли в комнате стрэддлы
+func (r *Room) AllowsStraddle() bool {
+	return r.UTGStraddle || r.ButtonStraddle
+}
+
+// GetMaxStraddles - максимум стрэддлов в раздаче, включая рестрэддлы
+func (r *Room) GetMaxStraddles() int {
+	if r.MaxStraddles > 0 {
+		return r.MaxStraddles
+	}
+	return DefaultMaxStraddles
+}
+
+// AllowsRunItMultiple - проверяет, можно ли прогонять доску несколько раз
+func (r *Room) AllowsRunItMultiple() bool {
+	return r.GetMaxRuns() > 1
+}
+
+// GetMaxRuns - максимальное количество прогонов доски
+func (r *Room) GetMaxRuns() int {
+	if r.MaxRuns > MaxBoardRuns {
+		return MaxBoardRuns
+	}
+	if r.MaxRuns < 1 {
+		return 1
+	}
+	return r.MaxRuns
+}
+
+// IsTournament - проверяет, играется ли в комнате турнир
+func (r *Room) IsTournament() bool {
+	return r.TournamentID != ""
+}
+
+// IsOwner - проверяет, является ли игрок владельцем комнаты
+func (r *Room) IsOwner(userID string) bool {
+	return r.OwnerID != "" && r.OwnerID == userID
+}
+
+// GetBombPotAnte - анте бомб-пота с каждого игрока (по умолчанию - большой блайнд)
+func (r *Room) GetBombPotAnte() int {
+	if r.BombPotAnte > 0 {
+		return r.BombPotAnte
+	}
+	return r.BigBlind
+}
+
+// IsBombPotHand - проверяет, выпадает ли раздача roundNumber на бомб-пот по расписанию
+func (r *Room) IsBombPotHand(roundNumber int) bool {
+	return r.BombPotEvery > 0 && roundNumber > 0 && roundNumber%r.BombPotEvery == 0
+}
+
+// IsMixedGame - проверяет, чередует ли комната разновидности покера
+func (r *Room) IsMixedGame() bool {
+	return len(r.MixedGame) > 0
+}
+
+// GetRotationHands - количество раздач одной разновидности при смене по раздачам
+func (r *Room) GetRotationHands() int {
+	if r.RotationHands > 0 {
+		return r.RotationHands
+	}
+	return DefaultRotationHands
+}
+
+// GetBringIn - размер обязательной ставки bring-in в стаде (0 - малый блайнд)
+func (r *Room) GetBringIn() int {
+	if r.BringIn > 0 {
+		return r.BringIn
+	}
+	return r.SmallBlind
+}
+
+// UsesButtonAnte - проверяет, ставит ли дилер анте вместо блайндов
+func (r *Room) UsesButtonAnte() bool {
+	return r.ForcedBets == ForcedBetsButtonAnte
+}
+
+// GetButtonAnte - размер анте дилера
+func (r *Room) GetButtonAnte() int {
+	if r.ButtonAnte > 0 {
+		return r.ButtonAnte
+	}
+	return r.BigBlind
+}
+
 // IsEmpty - проверяет, пустая ли комната (нет игроков)
 func (r *Room) IsEmpty() bool {
 	return r.CurrentPlayers == 0
@@ -0,0 +1,84 @@
+package models
+
+// MaxBoardRuns - максимальное количество прогонов доски (run it twice / three times)
+const MaxBoardRuns = 3
+
+// RunItOffer - предложение игрокам в олл-ине прогнать оставшуюся доску несколько раз
+// Каждый участник выбирает количество прогонов от 1 до MaxRuns;
+// доска прогоняется столько раз, сколько выбрал самый осторожный игрок
+type RunItOffer struct {
+	// Участники раздачи, которым сделано предложение
+	Players []string `json:"players"`
+
+	// Выбор каждого ответившего игрока (ключ - ID игрока)
+	Choices map[string]int `json:"choices,omitempty"`
+
+	// Максимальное количество прогонов (настройка комнаты, ограниченная остатком колоды)
+	MaxRuns int `json:"max_runs"`
+
+	// Срок ответа (Unix timestamp в миллисекундах)
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// IsExpired - проверяет, истек ли срок ответа на момент nowMillis
+func (o *RunItOffer) IsExpired(nowMillis int64) bool {
+	return nowMillis >= o.ExpiresAt
+}
+
+// IsParticipant - проверяет, сделано ли предложение игроку
+func (o *RunItOffer) IsParticipant(userID string) bool {
+	for _, id := range o.Players {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AllResponded - проверяет, ответили ли все участники
+func (o *RunItOffer) AllResponded() bool {
+	for _, userID := range o.Players {
+		if _, ok := o.Choices[userID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// AgreedRuns - согласованное количество прогонов: минимальный выбор среди участников
+// Не ответивший игрок считается выбравшим один прогон
+func (o *RunItOffer) AgreedRuns() int {
+	runs := o.MaxRuns
+	for _, userID := range o.Players {
+		choice, ok := o.Choices[userID]
+		if !ok || choice < 1 {
+			choice = 1
+		}
+		if choice < runs {
+			runs = choice
+		}
+	}
+
+	if runs < 1 {
+		return 1
+	}
+	return runs
+}
+
+// SplitRuns - делит банк на доли прогонов
+// Нечетные фишки достаются первым прогонам
+func SplitRuns(amount, runs int) []int {
+	if runs < 1 {
+		runs = 1
+	}
+
+	shares := make([]int, runs)
+	for i := range shares {
+		shares[i] = amount / runs
+		if i < amount%runs {
+			shares[i]++
+		}
+	}
+
+	return shares
+}
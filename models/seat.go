@@ -0,0 +1,68 @@
+package models
+
+import (
+	"sort"
+)
+
+// SeatAssignment - игрок и занимаемое им место за столом
+type SeatAssignment struct {
+	// ID игрока
+	UserID string `json:"user_id"`
+
+	// Номер места (0 - maxPlayers-1)
+	Seat int `json:"seat"`
+}
+
+// SortBySeat - сортирует игроков по номеру места (по часовой стрелке)
+func SortBySeat(seats []SeatAssignment) {
+	sort.Slice(seats, func(i, j int) bool {
+		return seats[i].Seat < seats[j].Seat
+	})
+}
+
+// OrderFromSeat - возвращает игроков по часовой стрелке, начиная с первого места после afterSeat
+// Например, для порядка ходов от дилера: OrderFromSeat(seats, dealerPosition)
+// Входной список должен быть отсортирован по местам (SortBySeat)
+func OrderFromSeat(seats []SeatAssignment, afterSeat int) []SeatAssignment {
+	if len(seats) == 0 {
+		return seats
+	}
+
+	// Если все места не больше afterSeat - начинаем сначала (переход через 0)
+	start := 0
+	for i, s := range seats {
+		if s.Seat > afterSeat {
+			start = i
+			break
+		}
+	}
+
+	ordered := make([]SeatAssignment, 0, len(seats))
+	ordered = append(ordered, seats[start:]...)
+	ordered = append(ordered, seats[:start]...)
+	return ordered
+}
+
+// NextOccupiedSeat - возвращает следующее занятое место по часовой стрелке после afterSeat
+// Возвращает -1 если за столом никого нет
+func NextOccupiedSeat(seats []SeatAssignment, afterSeat int) int {
+	ordered := OrderFromSeat(seats, afterSeat)
+	if len(ordered) == 0 {
+		return -1
+	}
+	return ordered[0].Seat
+}
+
+// UserIDs - возвращает ID игроков в том же порядке
+func UserIDs(seats []SeatAssignment) []string {
+	ids := make([]string, len(seats))
+	for i, s := range seats {
+		ids[i] = s.UserID
+	}
+	return ids
+}
+
+// IsValidSeat - проверяет, что номер места существует за столом
+func IsValidSeat(seat, maxPlayers int) bool {
+	return seat >= 0 && seat < maxPlayers
+}
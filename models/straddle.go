@@ -0,0 +1,38 @@
+package models
+
+// DefaultMaxStraddles - количество стрэддлов в раздаче по умолчанию (без рестрэддлов)
+const DefaultMaxStraddles = 1
+
+// StraddlePost - добровольный живой блайнд (стрэддл), поставленный до раздачи
+type StraddlePost struct {
+	// ID игрока
+	UserID string `json:"user_id"`
+
+	// Место игрока
+	Seat int `json:"seat"`
+
+	// Сумма стрэддла (каждый следующий стрэддл вдвое больше предыдущего)
+	Amount int `json:"amount"`
+}
+
+// PreflopActionOrder - порядок хода на префлопе
+// seats - места игроков в раздаче по часовой стрелке, lastToAct - место игрока, который
+// ходит последним (большой блайнд или последний стрэддл). Ход начинается со следующего за ним
+func PreflopActionOrder(seats []int, lastToAct int) []int {
+	start := -1
+	for i, seat := range seats {
+		if seat == lastToAct {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return seats
+	}
+
+	order := make([]int, 0, len(seats))
+	for i := 0; i < len(seats); i++ {
+		order = append(order, seats[(start+i)%len(seats)])
+	}
+	return order
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"sort"
+)
+
+// StudStreetCards - сколько закрытых и открытых карт получает игрок на улице стада
+// Третья улица: 2 закрытые + 1 открытая, четвертая-шестая: 1 открытая, седьмая: 1 закрытая
+func StudStreetCards(phase GamePhase) (down int, up int) {
+	switch phase {
+	case GamePhaseThirdStreet:
+		return 2, 1
+	case GamePhaseFourthStreet, GamePhaseFifthStreet, GamePhaseSixthStreet:
+		return 0, 1
+	case GamePhaseSeventhStreet:
+		return 1, 0
+	default:
+		return 0, 0
+	}
+}
+
+// IsStudStreet - проверяет, является ли фаза улицей стада
+func IsStudStreet(phase GamePhase) bool {
+	down, up := StudStreetCards(phase)
+	return down+up > 0
+}
+
+// suitOrder - старшинство мастей для выбора bring-in: трефы < бубны < червы < пики
+var suitOrder = map[string]int{"C": 1, "D": 2, "H": 3, "S": 4}
+
+// SuitRank - старшинство масти (используется только при равенстве достоинств)
+func SuitRank(suit string) int {
+	return suitOrder[suit]
+}
+
+// FindBringIn - определяет игрока, открывающего торговлю обязательной ставкой bring-in
+// doorCards - открытая карта третьей улицы каждого игрока (ключ - ID игрока)
+// В стаде bring-in ставит младшая открытая карта (туз - старший), в рэззе - старшая
+// (туз - младший, король - старший). При равенстве достоинств решает масть:
+// в стаде bring-in ставит младшая масть, в рэззе - старшая
+func FindBringIn(doorCards map[string]Card, razz bool) string {
+	bringIn := ""
+	var worst Card
+
+	for userID, card := range doorCards {
+		if bringIn == "" || bringInWorse(card, worst, razz) {
+			bringIn, worst = userID, card
+		}
+	}
+
+	return bringIn
+}
+
+// bringInWorse - проверяет, слабее ли карта a карты b для определения bring-in
+func bringInWorse(a, b Card, razz bool) bool {
+	if razz {
+		if lowRank(a) != lowRank(b) {
+			return lowRank(a) > lowRank(b)
+		}
+		return SuitRank(a.Suit) > SuitRank(b.Suit)
+	}
+
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return SuitRank(a.Suit) < SuitRank(b.Suit)
+}
+
+// EvaluateVisible - оценивает открытые карты игрока в стаде (от 1 до 4 карт)
+// Учитываются только пары, сеты и каре: неполные стриты и флеши силы не дают
+func EvaluateVisible(cards []Card) HandValue {
+	counts := make(map[int]int, len(cards))
+	for _, c := range cards {
+		counts[c.Rank]++
+	}
+
+	groups := make([]int, 0, len(counts))
+	for rank := range counts {
+		groups = append(groups, rank)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if counts[groups[i]] != counts[groups[j]] {
+			return counts[groups[i]] > counts[groups[j]]
+		}
+		return groups[i] > groups[j]
+	})
+
+	value := HandValue{Category: HandHighCard, Ranks: groups, Cards: cardStrings(cards)}
+	if len(groups) == 0 {
+		return value
+	}
+
+	switch {
+	case counts[groups[0]] == 4:
+		value.Category = HandFourOfAKind
+	case counts[groups[0]] == 3:
+		value.Category = HandThreeOfAKind
+	case counts[groups[0]] == 2 && len(groups) >= 2 && counts[groups[1]] == 2:
+		value.Category = HandTwoPair
+	case counts[groups[0]] == 2:
+		value.Category = HandPair
+	}
+
+	return value
+}
+
+// FindFirstToAct - определяет игрока, открывающего торговлю с четвертой улицы
+// В стаде первым ходит лучшая открытая рука, в рэззе - лучшая младшая открытая рука.
+// При равенстве ходит игрок, ближайший к дилеру по часовой стрелке
+// upCards - открытые карты игроков, order - игроки по часовой стрелке от дилера
+func FindFirstToAct(upCards map[string][]Card, order []string, razz bool) string {
+	first := ""
+	var bestHigh HandValue
+	var bestLow LowHandValue
+
+	for _, userID := range order {
+		cards, ok := upCards[userID]
+		if !ok {
+			continue
+		}
+
+		if razz {
+			low := EvaluateRazz(cards)
+			if first == "" || low.Compare(bestLow) > 0 {
+				first, bestLow = userID, low
+			}
+			continue
+		}
+
+		high := EvaluateVisible(cards)
+		if first == "" || high.Compare(bestHigh) > 0 {
+			first, bestHigh = userID, high
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"sort"
+)
+
+// TableMove - пересадка игроков многостольного турнира с одного стола на другой
+type TableMove struct {
+	FromRoomID string `json:"from_room_id"`
+	ToRoomID   string `json:"to_room_id"`
+	Count      int    `json:"count"`
+}
+
+// TableBalancePlan - план балансировки столов
+type TableBalancePlan struct {
+	// Закрываемые столы (все их игроки пересаживаются)
+	Breaks []string `json:"breaks,omitempty"`
+
+	// Пересадки, включая пересадки с закрываемых столов
+	Moves []TableMove `json:"moves,omitempty"`
+}
+
+// IsEmpty - проверяет, что балансировка не нужна
+func (p *TableBalancePlan) IsEmpty() bool {
+	return len(p.Breaks) == 0 && len(p.Moves) == 0
+}
+
+// tableCount - количество игроков за столом при планировании
+type tableCount struct {
+	roomID  string
+	players int
+}
+
+// PlanTableBalance - рассчитывает закрытие столов и пересадки
+// tables - количество игроков за каждым столом, seatsPerTable - мест за столом.
+// Сначала закрываются лишние столы (пока оставшиеся вмещают всех игроков, закрывается
+// стол с наименьшим количеством игроков), затем игроки пересаживаются с самых полных
+// столов на самые пустые, пока разница между столами не станет не больше одного игрока
+func PlanTableBalance(tables map[string]int, seatsPerTable int) *TableBalancePlan {
+	plan := &TableBalancePlan{}
+	if len(tables) == 0 || seatsPerTable <= 0 {
+		return plan
+	}
+
+	counts := make([]*tableCount, 0, len(tables))
+	total := 0
+	for roomID, players := range tables {
+		counts = append(counts, &tableCount{roomID: roomID, players: players})
+		total += players
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].roomID < counts[j].roomID
+	})
+
+	moves := make(map[[2]string]int)
+	var order [][2]string
+	move := func(from, to *tableCount) {
+		key := [2]string{from.roomID, to.roomID}
+		if _, ok := moves[key]; !ok {
+			order = append(order, key)
+		}
+		moves[key]++
+		from.players--
+		to.players++
+	}
+
+	needed := (total + seatsPerTable - 1) / seatsPerTable
+	if needed < 1 {
+		needed = 1
+	}
+
+	for len(counts) > needed {
+		// Закрываем самый пустой стол (при равенстве - последний по ID)
+		breakIndex := 0
+		for i, table := range counts {
+			if table.players <= counts[breakIndex].players {
+				breakIndex = i
+			}
+		}
+		broken := counts[breakIndex]
+		counts = append(counts[:breakIndex], counts[breakIndex+1:]...)
+		plan.Breaks = append(plan.Breaks, broken.roomID)
+
+		for broken.players > 0 {
+			move(broken, smallestTable(counts))
+		}
+	}
+
+	for {
+		smallest, largest := smallestTable(counts), largestTable(counts)
+		if largest.players-smallest.players <= 1 {
+			break
+		}
+		move(largest, smallest)
+	}
+
+	for _, key := range order {
+		plan.Moves = append(plan.Moves, TableMove{FromRoomID: key[0], ToRoomID: key[1], Count: moves[key]})
+	}
+
+	return plan
+}
+
+// smallestTable - стол с наименьшим количеством игроков (при равенстве - первый по ID)
+func smallestTable(counts []*tableCount) *tableCount {
+	smallest := counts[0]
+	for _, table := range counts[1:] {
+		if table.players < smallest.players {
+			smallest = table
+		}
+	}
+	return smallest
+}
+
+// largestTable - стол с наибольшим количеством игроков (при равенстве - первый по ID)
+func largestTable(counts []*tableCount) *tableCount {
+	largest := counts[0]
+	for _, table := range counts[1:] {
+		if table.players > largest.players {
+			largest = table
+		}
+	}
+	return largest
+}
+
+// NextBigBlindOrder - игроки в порядке, в котором они будут ставить большой блайнд,
+// начиная со следующей раздачи (дилер переходит на следующее занятое место)
+// Для честности по блайндам со стола пересаживаются игроки, которым предстоит большой блайнд
+func NextBigBlindOrder(seats []SeatAssignment, dealerPosition int) []SeatAssignment {
+	if len(seats) == 0 {
+		return seats
+	}
+
+	nextDealer := NextOccupiedSeat(seats, dealerPosition)
+	ordered := OrderFromSeat(seats, nextDealer)
+
+	// Один на один дилер ставит малый блайнд, большой - следующий за ним игрок;
+	// иначе большой блайнд - второй игрок после дилера
+	if len(ordered) <= 2 {
+		return ordered
+	}
+	return append(ordered[1:], ordered[0])
+}
+
+// BlindFairSeat - свободное место для игрока, пересаживаемого за стол
+// Выбирается первое свободное место по часовой стрелке после следующего дилера:
+// пересаженный игрок сразу попадает на блайнды и не пропускает их
+// Возвращает -1, если свободных мест нет
+func BlindFairSeat(seats []SeatAssignment, dealerPosition, maxPlayers int) int {
+	occupied := make(map[int]bool, len(seats))
+	for _, s := range seats {
+		occupied[s.Seat] = true
+	}
+
+	start := NextOccupiedSeat(seats, dealerPosition)
+	if start < 0 {
+		start = maxPlayers - 1
+	}
+
+	for i := 1; i <= maxPlayers; i++ {
+		seat := (start + i) % maxPlayers
+		if !occupied[seat] {
+			return seat
+		}
+	}
+	return -1
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanTableBalance(t *testing.T) {
+	tests := []struct {
+		name          string
+		tables        map[string]int
+		seatsPerTable int
+		want          *TableBalancePlan
+	}{
+		{
+			name:          "столы сбалансированы",
+			tables:        map[string]int{"a": 9, "b": 8},
+			seatsPerTable: 9,
+			want:          &TableBalancePlan{},
+		},
+		{
+			name:          "пересадка с полного стола",
+			tables:        map[string]int{"a": 9, "b": 6},
+			seatsPerTable: 9,
+			want:          &TableBalancePlan{Moves: []TableMove{{FromRoomID: "a", ToRoomID: "b", Count: 1}}},
+		},
+		{
+			name:          "закрытие самого пустого стола",
+			tables:        map[string]int{"a": 5, "b": 4, "c": 3},
+			seatsPerTable: 9,
+			want: &TableBalancePlan{
+				Breaks: []string{"c"},
+				Moves: []TableMove{
+					{FromRoomID: "c", ToRoomID: "b", Count: 2},
+					{FromRoomID: "c", ToRoomID: "a", Count: 1},
+				},
+			},
+		},
+		{
+			name:          "финальный стол",
+			tables:        map[string]int{"a": 3, "b": 2, "c": 4},
+			seatsPerTable: 9,
+			want: &TableBalancePlan{
+				Breaks: []string{"b", "c"},
+				Moves: []TableMove{
+					{FromRoomID: "b", ToRoomID: "a", Count: 2},
+					{FromRoomID: "c", ToRoomID: "a", Count: 4},
+				},
+			},
+		},
+		{
+			name:          "без мест за столом",
+			tables:        map[string]int{"a": 3},
+			seatsPerTable: 0,
+			want:          &TableBalancePlan{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanTableBalance(tt.tables, tt.seatsPerTable)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanTableBalance(%v, %d) = %+v, want %+v", tt.tables, tt.seatsPerTable, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// TournamentType - вид турнира
+type TournamentType string
+
+// Константы видов турниров
+const (
+	// TournamentSitAndGo - Sit & Go: один стол, старт при заполнении всех мест
+	TournamentSitAndGo TournamentType = "sit_and_go"
+
+	// TournamentMultiTable - многостольный турнир: столы балансируются и закрываются по мере выбывания
+	TournamentMultiTable TournamentType = "multi_table"
+)
+
+// TournamentStatus - статус турнира
+type TournamentStatus string
+
+// Константы статусов турнира
+const (
+	// TournamentStatusRegistering - идет регистрация игроков
+	TournamentStatusRegistering TournamentStatus = "registering"
+
+	// TournamentStatusRunning - турнир идет
+	TournamentStatusRunning TournamentStatus = "running"
+
+	// TournamentStatusFinished - турнир завершен, призы выплачены
+	TournamentStatusFinished TournamentStatus = "finished"
+)
+
+// Tournament - турнир клуба
+type Tournament struct {
+	// Уникальный ID турнира
+	TournamentID string
+
+	// ID клуба
+	ClubID string
+
+	// Вид турнира
+	Type TournamentType
+
+	// Статус турнира
+	Status TournamentStatus
+
+	// Комната, в которой играется Sit & Go (у многостольного турнира пусто,
+	// столы хранятся в TournamentTables)
+	RoomID string
+
+	// Взнос в призовой фонд и комиссия клуба
+	BuyIn int
+	Fee   int
+
+	// Стартовый стек каждого участника (турнирные фишки)
+	StartingStack int
+
+	// Количество участников, при котором турнир стартует
+	MaxPlayers int
+
+	// Расписание уровней блайндов (часы уровней ведет BlindClockService)
+	BlindLevels []BlindLevel
+
+	// Доли призового фонда по местам в процентах (первый элемент - первое место)
+	// Пусто - доли из PayoutStructure по количеству участников
+	PayoutPercents []int
+
+	// Таблица выплат по размеру поля (пусто - DefaultPayoutStructure)
+	PayoutStructure PayoutStructure
+
+	// Призовой фонд (сумма взносов)
+	PrizePool int
+
+	// Количество зарегистрированных участников
+	Entrants int
+
+	// Время старта и окончания (Unix timestamp в миллисекундах)
+	StartedAt  int64
+	FinishedAt int64
+
+	// Игра рука в руку: на призовом пузыре каждый стол ждет, пока остальные столы доиграют раздачу
+	HandForHand bool
+
+	// Ребаи разрешены до конца этого уровня блайндов включительно (0 - без ребаев).
+	// Ребай стоит BuyIn+Fee, дает StartingStack и доступен, пока стек не больше стартового
+	RebuyUntilLevel int
+
+	// Максимум ребаев на участника (0 - без ограничения)
+	MaxRebuys int
+
+	// Аддон: одноразовая докупка AddOnChips фишек за AddOnCost во время перерыва
+	// (0 - без аддона); вся стоимость аддона идет в призовой фонд
+	AddOnChips int
+	AddOnCost  int
+
+	// Поздняя регистрация открыта до конца этого уровня блайндов включительно (0 - без поздней регистрации)
+	LateRegUntilLevel int
+
+	// Поздняя регистрация закрыта досрочно: первое выбывание закрепляет места и призы,
+	// поэтому после него количество участников и призовой фонд от регистраций больше не меняются
+	LateRegClosed bool
+
+	// Баунти: часть BuyIn, которая идет не в призовой фонд, а в награду за голову участника (0 - без баунти)
+	Bounty int
+
+	// Прогрессивный нокаут: выбивший получает BountyCashPercent процентов баунти (0 - половину),
+	// остальное добавляется к его собственной голове
+	ProgressiveKO     bool
+	BountyCashPercent int
+}
+
+// TournamentInfo - структура турнира из Redis
+// Соответствует hash "club:{clubId}:tournament:{tournamentId}"
+type TournamentInfo struct {
+	TournamentID   string `json:"tournament_id"`
+	ClubID         string `json:"club_id"`
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	RoomID         string `json:"room_id"`
+	BuyIn          string `json:"buy_in"`
+	Fee            string `json:"fee"`
+	StartingStack  string `json:"starting_stack"`
+	MaxPlayers     string `json:"max_players"`
+	BlindLevels    string `json:"blind_levels"`    // JSON массив
+	PayoutPercents string `json:"payout_percents"` // JSON массив
+	PrizePool      string `json:"prize_pool"`
+	Entrants       string `json:"entrants"`
+	StartedAt      string `json:"started_at"`
+	FinishedAt     string `json:"finished_at"`
+
+	HandForHand string `json:"hand_for_hand"`
+
+	PayoutStructure string `json:"payout_structure"` // JSON массив
+
+	RebuyUntilLevel   string `json:"rebuy_until_level"`
+	MaxRebuys         string `json:"max_rebuys"`
+	AddOnChips        string `json:"add_on_chips"`
+	AddOnCost         string `json:"add_on_cost"`
+	LateRegUntilLevel string `json:"late_reg_until_level"`
+	LateRegClosed     string `json:"late_reg_closed"`
+
+	Bounty            string `json:"bounty"`
+	ProgressiveKO     string `json:"progressive_ko"`
+	BountyCashPercent string `json:"bounty_cash_percent"`
+}
+
+// NewTournamentFromRedis - создает Tournament из данных Redis hash
+func NewTournamentFromRedis(data map[string]string) (*Tournament, error) {
+	buyIn, _ := strconv.Atoi(data["buy_in"])
+	fee, _ := strconv.Atoi(data["fee"])
+	startingStack, _ := strconv.Atoi(data["starting_stack"])
+	maxPlayers, _ := strconv.Atoi(data["max_players"])
+	prizePool, _ := strconv.Atoi(data["prize_pool"])
+	entrants, _ := strconv.Atoi(data["entrants"])
+	startedAt, _ := strconv.ParseInt(data["started_at"], 10, 64)
+	finishedAt, _ := strconv.ParseInt(data["finished_at"], 10, 64)
+	rebuyUntilLevel, _ := strconv.Atoi(data["rebuy_until_level"])
+	maxRebuys, _ := strconv.Atoi(data["max_rebuys"])
+	addOnChips, _ := strconv.Atoi(data["add_on_chips"])
+	addOnCost, _ := strconv.Atoi(data["add_on_cost"])
+	lateRegUntilLevel, _ := strconv.Atoi(data["late_reg_until_level"])
+	bounty, _ := strconv.Atoi(data["bounty"])
+	progressiveKO := data["progressive_ko"] == "true" || data["progressive_ko"] == "1"
+	bountyCashPercent, _ := strconv.Atoi(data["bounty_cash_percent"])
+
+	var blindLevels []BlindLevel
+	if data["blind_levels"] != "" {
+		json.Unmarshal([]byte(data["blind_levels"]), &blindLevels)
+	}
+
+	var payoutPercents []int
+	if data["payout_percents"] != "" {
+		json.Unmarshal([]byte(data["payout_percents"]), &payoutPercents)
+	}
+
+	var payoutStructure PayoutStructure
+	if data["payout_structure"] != "" {
+		json.Unmarshal([]byte(data["payout_structure"]), &payoutStructure)
+	}
+
+	return &Tournament{
+		TournamentID:   data["tournament_id"],
+		ClubID:         data["club_id"],
+		Type:           TournamentType(data["type"]),
+		Status:         TournamentStatus(data["status"]),
+		RoomID:         data["room_id"],
+		BuyIn:          buyIn,
+		Fee:            fee,
+		StartingStack:  startingStack,
+		MaxPlayers:     maxPlayers,
+		BlindLevels:    blindLevels,
+		PayoutPercents: payoutPercents,
+		PrizePool:      prizePool,
+		Entrants:       entrants,
+		StartedAt:      startedAt,
+		FinishedAt:     finishedAt,
+
+		HandForHand: data["hand_for_hand"] == "true" || data["hand_for_hand"] == "1",
+
+		PayoutStructure: payoutStructure,
+
+		RebuyUntilLevel:   rebuyUntilLevel,
+		MaxRebuys:         maxRebuys,
+		AddOnChips:        addOnChips,
+		AddOnCost:         addOnCost,
+		LateRegUntilLevel: lateRegUntilLevel,
+		LateRegClosed:     data["late_reg_closed"] == "true" || data["late_reg_closed"] == "1",
+
+		Bounty:            bounty,
+		ProgressiveKO:     progressiveKO,
+		BountyCashPercent: bountyCashPercent,
+	}, nil
+}
+
+// ToRedisHash - преобразует Tournament в map для сохранения в Redis hash
+func (t *Tournament) ToRedisHash() map[string]interface{} {
+	levelsJSON, _ := json.Marshal(t.BlindLevels)
+	payoutsJSON, _ := json.Marshal(t.PayoutPercents)
+	structureJSON, _ := json.Marshal(t.PayoutStructure)
+
+	return map[string]interface{}{
+		"tournament_id":   t.TournamentID,
+		"club_id":         t.ClubID,
+		"type":            string(t.Type),
+		"status":          string(t.Status),
+		"room_id":         t.RoomID,
+		"buy_in":          t.BuyIn,
+		"fee":             t.Fee,
+		"starting_stack":  t.StartingStack,
+		"max_players":     t.MaxPlayers,
+		"blind_levels":    string(levelsJSON),
+		"payout_percents": string(payoutsJSON),
+		"prize_pool":      t.PrizePool,
+		"entrants":        t.Entrants,
+		"started_at":      t.StartedAt,
+		"finished_at":     t.FinishedAt,
+
+		"hand_for_hand": t.HandForHand,
+
+		"payout_structure": string(structureJSON),
+
+		"rebuy_until_level":    t.RebuyUntilLevel,
+		"max_rebuys":           t.MaxRebuys,
+		"add_on_chips":         t.AddOnChips,
+		"add_on_cost":          t.AddOnCost,
+		"late_reg_until_level": t.LateRegUntilLevel,
+		"late_reg_closed":      t.LateRegClosed,
+
+		"bounty":              t.Bounty,
+		"progressive_ko":      t.ProgressiveKO,
+		"bounty_cash_percent": t.BountyCashPercent,
+	}
+}
+
+// IsRegistering - проверяет, идет ли регистрация
+func (t *Tournament) IsRegistering() bool {
+	return t.Status == TournamentStatusRegistering
+}
+
+// IsRunning - проверяет, идет ли турнир
+func (t *Tournament) IsRunning() bool {
+	return t.Status == TournamentStatusRunning
+}
+
+// IsMultiTable - проверяет, является ли турнир многостольным
+func (t *Tournament) IsMultiTable() bool {
+	return t.Type == TournamentMultiTable
+}
+
+// PaidPlaces - количество призовых мест
+func (t *Tournament) PaidPlaces() int {
+	return len(t.GetPayoutPercents())
+}
+
+// IsRebuyPeriod - проверяет, идет ли период ребаев на уровне блайндов level
+func (t *Tournament) IsRebuyPeriod(level int) bool {
+	return t.RebuyUntilLevel > 0 && level <= t.RebuyUntilLevel
+}
+
+// CanRebuy - проверяет, остались ли у участника ребаи
+func (t *Tournament) CanRebuy(entry *TournamentEntry) bool {
+	return t.MaxRebuys == 0 || entry.Rebuys < t.MaxRebuys
+}
+
+// HasAddOn - проверяет, предусмотрен ли в турнире аддон
+func (t *Tournament) HasAddOn() bool {
+	return t.AddOnChips > 0
+}
+
+// IsLateRegistration - проверяет, открыта ли поздняя регистрация на уровне блайндов level
+func (t *Tournament) IsLateRegistration(level int) bool {
+	return t.LateRegUntilLevel > 0 && level <= t.LateRegUntilLevel && !t.LateRegClosed
+}
+
+// IsBounty - проверяет, является ли турнир нокаут-турниром
+func (t *Tournament) IsBounty() bool {
+	return t.Bounty > 0
+}
+
+// PrizePoolPart - часть BuyIn, которая идет в призовой фонд (без баунти)
+func (t *Tournament) PrizePoolPart() int {
+	return t.BuyIn - t.Bounty
+}
+
+// KnockoutCashPercent - процент баунти, который выбивший получает сразу
+// В обычном нокауте - весь баунти, в прогрессивном - BountyCashPercent (по умолчанию половина)
+func (t *Tournament) KnockoutCashPercent() int {
+	if !t.ProgressiveKO {
+		return 100
+	}
+	if t.BountyCashPercent <= 0 {
+		return 50
+	}
+	return t.BountyCashPercent
+}
+
+// IsFull - проверяет, заполнены ли все места
+func (t *Tournament) IsFull() bool {
+	return t.MaxPlayers > 0 && t.Entrants >= t.MaxPlayers
+}
+
+// GetPayoutPercents - доли призового фонда по местам
+func (t *Tournament) GetPayoutPercents() []int {
+	if len(t.PayoutPercents) > 0 {
+		return t.PayoutPercents
+	}
+	if len(t.PayoutStructure) > 0 {
+		return t.PayoutStructure.PercentsFor(t.Entrants)
+	}
+	return DefaultPayoutStructure.PercentsFor(t.Entrants)
+}
+
+// TournamentEntryStatus - статус участника турнира
+type TournamentEntryStatus string
+
+// Константы статусов участника
+const (
+	// TournamentEntryRegistering - регистрация участника еще не завершена (место занято, взнос списывается)
+	TournamentEntryRegistering TournamentEntryStatus = "registering"
+
+	// TournamentEntryPlaying - участник в игре
+	TournamentEntryPlaying TournamentEntryStatus = "playing"
+
+	// TournamentEntryEliminated - участник выбыл
+	TournamentEntryEliminated TournamentEntryStatus = "eliminated"
+
+	// TournamentEntryWinner - победитель турнира
+	TournamentEntryWinner TournamentEntryStatus = "winner"
+)
+
+// TournamentEntry - участник турнира
+type TournamentEntry struct {
+	UserID   string                `json:"user_id"`
+	Username string                `json:"username"`
+	Status   TournamentEntryStatus `json:"status"`
+
+	// Стол, за которым сидит участник
+	RoomID string `json:"room_id,omitempty"`
+
+	// Занятое место (0 - участник еще в игре)
+	Place int `json:"place,omitempty"`
+
+	// Выигранный приз
+	Prize int `json:"prize,omitempty"`
+
+	// Количество сделанных ребаев и взят ли аддон
+	Rebuys int  `json:"rebuys,omitempty"`
+	AddOn  bool `json:"add_on,omitempty"`
+
+	// Текущий баунти за голову участника
+	Bounty int `json:"bounty,omitempty"`
+
+	// Количество выбитых участников и сумма полученных за них баунти
+	Knockouts   int `json:"knockouts,omitempty"`
+	BountiesWon int `json:"bounties_won,omitempty"`
+
+	// Кто выбил участника в раздаче, после которой он мог сделать ребай (ID игрока -> выигрыш
+	// из последнего банка участника); по этим долям делится баунти, если участник откажется от ребая
+	PendingKnockout map[string]int `json:"pending_knockout,omitempty"`
+
+	// Время регистрации и выбывания (Unix timestamp в миллисекундах)
+	RegisteredAt int64 `json:"registered_at"`
+	EliminatedAt int64 `json:"eliminated_at,omitempty"`
+}
+
+// IsPlaying - проверяет, остается ли участник в турнире
+func (e *TournamentEntry) IsPlaying() bool {
+	return e.Status == TournamentEntryPlaying
+}
+
+// CalculatePrizes - делит призовой фонд по таблице выплат
+// Доли округляются вниз, остаток от округления достается первому месту
+func CalculatePrizes(prizePool int, percents []int) []int {
+	prizes := make([]int, len(percents))
+	if len(percents) == 0 || prizePool <= 0 {
+		return prizes
+	}
+
+	paid := 0
+	for i, percent := range percents {
+		prizes[i] = prizePool * percent / 100
+		paid += prizes[i]
+	}
+	prizes[0] += prizePool - paid
+
+	return prizes
+}
+
+// Операции в журнале аудита турнира
+const (
+	TournamentAuditRegistration     = "registration"      // Регистрация до старта
+	TournamentAuditLateRegistration = "late_registration" // Поздняя регистрация
+	TournamentAuditRebuy            = "rebuy"             // Ребай
+	TournamentAuditAddOn            = "add_on"            // Аддон
+)
+
+// TournamentAuditRecord - запись журнала аудита турнира
+type TournamentAuditRecord struct {
+	// Операция (TournamentAudit*)
+	Operation string `json:"operation"`
+
+	// Участник
+	UserID string `json:"user_id"`
+
+	// Сколько участник заплатил и сколько из этого ушло в призовой фонд
+	Paid      int `json:"paid"`
+	PrizePart int `json:"prize_part"`
+
+	// Выданные фишки
+	Chips int `json:"chips"`
+
+	// Призовой фонд после операции
+	PrizePool int `json:"prize_pool"`
+
+	// Уровень блайндов в момент операции (0 - до старта)
+	Level int `json:"level"`
+
+	// Временная метка (Unix timestamp в миллисекундах)
+	Timestamp int64 `json:"timestamp"`
+}
@@ -0,0 +1,36 @@
+package models
+
+// SeatOffer - место, предложенное игроку из листа ожидания
+// Место резервируется за игроком до ExpiresAt; если игрок не принял
+// предложение вовремя, оно передается следующему в листе ожидания
+type SeatOffer struct {
+	// ID игрока, которому предложено место
+	UserID string `json:"user_id"`
+
+	// ID комнаты
+	RoomID string `json:"room_id"`
+
+	// Зарезервированное место
+	Seat int `json:"seat"`
+
+	// Время предложения и срок действия (Unix timestamp в миллисекундах)
+	OfferedAt int64 `json:"offered_at"`
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// IsExpired - проверяет, истек ли срок предложения на момент nowMillis
+func (o *SeatOffer) IsExpired(nowMillis int64) bool {
+	return nowMillis >= o.ExpiresAt
+}
+
+// WaitingListEntry - запись в листе ожидания комнаты
+type WaitingListEntry struct {
+	// ID игрока
+	UserID string `json:"user_id"`
+
+	// Позиция в очереди (1 - следующий)
+	Position int `json:"position"`
+
+	// Время записи в лист ожидания (Unix timestamp в миллисекундах)
+	JoinedAt int64 `json:"joined_at"`
+}
@@ -117,12 +117,16 @@ func (al *ActionLogger) LogPlayerLeft(clubID, roomID, userID string, role string
 	})
 }
 
-// LogPlayerSatDown - записывает действие, когда игрок садится за стол
-func (al *ActionLogger) LogPlayerSatDown(clubID, roomID, userID string, position int, buyIn int) error {
+// LogPlayerSatDown - записывает действие, когда игрок садится за стол или докупает фишки
+// operation: "sit_down", "rebuy" или "top_up"
+// pending: true если фишки будут зачислены только после окончания текущей раздачи
+func (al *ActionLogger) LogPlayerSatDown(clubID, roomID, userID string, position int, buyIn int, operation string, pending bool) error {
 	return al.LogAction(clubID, roomID, "player_sat_down", map[string]interface{}{
-		"user_id":  userID,
-		"position": position,
-		"buy_in":   buyIn,
+		"user_id":   userID,
+		"position":  position,
+		"buy_in":    buyIn,
+		"operation": operation,
+		"pending":   pending,
 	})
 }
 
@@ -135,6 +139,68 @@ func (al *ActionLogger) LogPlayerStoodUp(clubID, roomID, userID string, position
 	})
 }
 
+// LogSeatChanged - записывает действие пересадки игрока на другое место
+func (al *ActionLogger) LogSeatChanged(clubID, roomID, userID string, oldPosition, newPosition int) error {
+	return al.LogAction(clubID, roomID, "seat_changed", map[string]interface{}{
+		"user_id":      userID,
+		"old_position": oldPosition,
+		"new_position": newPosition,
+	})
+}
+
+// LogPlayerSatOut - записывает действие, когда игрок начинает пропускать раздачи
+// nextHand: true если игрок сядет вне игры только после окончания текущей раздачи
+func (al *ActionLogger) LogPlayerSatOut(clubID, roomID, userID string, nextHand bool) error {
+	return al.LogAction(clubID, roomID, "player_sat_out", map[string]interface{}{
+		"user_id":   userID,
+		"next_hand": nextHand,
+	})
+}
+
+// LogPlayerSatIn - записывает действие возвращения игрока в игру
+// waitForBigBlind: true если игрок ждет большого блайнда вместо оплаты пропущенных блайндов
+func (al *ActionLogger) LogPlayerSatIn(clubID, roomID, userID string, waitForBigBlind bool) error {
+	return al.LogAction(clubID, roomID, "player_sat_in", map[string]interface{}{
+		"user_id":            userID,
+		"wait_for_big_blind": waitForBigBlind,
+	})
+}
+
+// LogBlindMissed - записывает пропуск блайнда игроком, сидящим вне игры
+// blind: "small" или "big"
+func (al *ActionLogger) LogBlindMissed(clubID, roomID, userID, blind string) error {
+	return al.LogAction(clubID, roomID, "blind_missed", map[string]interface{}{
+		"user_id": userID,
+		"blind":   blind,
+	})
+}
+
+// LogWaitingListJoined - записывает запись игрока в лист ожидания комнаты
+func (al *ActionLogger) LogWaitingListJoined(clubID, roomID, userID string, position int) error {
+	return al.LogAction(clubID, roomID, "waiting_list_joined", map[string]interface{}{
+		"user_id":  userID,
+		"position": position,
+	})
+}
+
+// LogWaitingListLeft - записывает удаление игрока из листа ожидания комнаты
+// reason: "left", "declined", "offer_expired" или "seated"
+func (al *ActionLogger) LogWaitingListLeft(clubID, roomID, userID, reason string) error {
+	return al.LogAction(clubID, roomID, "waiting_list_left", map[string]interface{}{
+		"user_id": userID,
+		"reason":  reason,
+	})
+}
+
+// LogSeatOffered - записывает предложение места игроку из листа ожидания
+func (al *ActionLogger) LogSeatOffered(clubID, roomID, userID string, seat int, expiresAt int64) error {
+	return al.LogAction(clubID, roomID, "seat_offered", map[string]interface{}{
+		"user_id":    userID,
+		"seat":       seat,
+		"expires_at": expiresAt,
+	})
+}
+
 // LogPlayerAction - записывает игровое действие игрока (fold, call, raise и т.д.)
 func (al *ActionLogger) LogPlayerAction(clubID, roomID, userID, action string, amount int) error {
 	data := map[string]interface{}{
@@ -168,6 +234,234 @@ func (al *ActionLogger) LogBlindsPosted(clubID, roomID string, smallBlindUser, b
 	})
 }
 
+// LogButtonAntePosted - записывает действие постановки анте дилера
+func (al *ActionLogger) LogButtonAntePosted(clubID, roomID, userID string, amount int) error {
+	return al.LogAction(clubID, roomID, "button_ante_posted", map[string]interface{}{
+		"user_id": userID,
+		"amount":  amount,
+	})
+}
+
+// LogBringInPosted - записывает действие постановки bring-in в стаде
+func (al *ActionLogger) LogBringInPosted(clubID, roomID, userID string, amount int) error {
+	return al.LogAction(clubID, roomID, "bring_in_posted", map[string]interface{}{
+		"user_id": userID,
+		"amount":  amount,
+	})
+}
+
+// LogVariantChanged - записывает смену разновидности покера в смешанной игре
+func (al *ActionLogger) LogVariantChanged(clubID, roomID, gameType, bettingStructure string, hands int) error {
+	return al.LogAction(clubID, roomID, "variant_changed", map[string]interface{}{
+		"game_type":         gameType,
+		"betting_structure": bettingStructure,
+		"hands":             hands,
+	})
+}
+
+// LogAntesPosted - записывает действие постановки анте
+func (al *ActionLogger) LogAntesPosted(clubID, roomID, anteType string, antes map[string]int) error {
+	return al.LogAction(clubID, roomID, "antes_posted", map[string]interface{}{
+		"ante_type": anteType,
+		"antes":     antes,
+	})
+}
+
+// LogStraddlePosted - записывает действие постановки стрэддла
+// number - номер стрэддла в раздаче (1 - стрэддл, 2 и далее - рестрэддлы)
+func (al *ActionLogger) LogStraddlePosted(clubID, roomID, userID string, amount, number int) error {
+	return al.LogAction(clubID, roomID, "straddle_posted", map[string]interface{}{
+		"user_id": userID,
+		"amount":  amount,
+		"number":  number,
+	})
+}
+
+// LogRunItOffered - записывает предложение игрокам в олл-ине прогнать доску несколько раз
+func (al *ActionLogger) LogRunItOffered(clubID, roomID string, players []string, maxRuns int, expiresAt int64) error {
+	return al.LogAction(clubID, roomID, "run_it_offered", map[string]interface{}{
+		"players":    players,
+		"max_runs":   maxRuns,
+		"expires_at": expiresAt,
+	})
+}
+
+// LogRunItChosen - записывает выбор игроком количества прогонов доски
+func (al *ActionLogger) LogRunItChosen(clubID, roomID, userID string, runs int) error {
+	return al.LogAction(clubID, roomID, "run_it_chosen", map[string]interface{}{
+		"user_id": userID,
+		"runs":    runs,
+	})
+}
+
+// LogRunItAgreed - записывает согласованное количество прогонов доски
+func (al *ActionLogger) LogRunItAgreed(clubID, roomID string, runs int) error {
+	return al.LogAction(clubID, roomID, "run_it_agreed", map[string]interface{}{
+		"runs": runs,
+	})
+}
+
+// LogAllInEquity - записывает эквити игроков, зафиксированное в момент олл-ина до ривера
+func (al *ActionLogger) LogAllInEquity(clubID, roomID, gameID string, board []string, equity map[string]float64) error {
+	return al.LogAction(clubID, roomID, "all_in_equity", map[string]interface{}{
+		"game_id": gameID,
+		"board":   board,
+		"equity":  equity,
+	})
+}
+
+// LogBombPotScheduled - записывает назначение бомб-пота на следующую раздачу
+// reason - кто назначил: owner (владелец комнаты) или vote (голосование игроков)
+func (al *ActionLogger) LogBombPotScheduled(clubID, roomID, reason, userID string) error {
+	return al.LogAction(clubID, roomID, "bomb_pot_scheduled", map[string]interface{}{
+		"reason":  reason,
+		"user_id": userID,
+	})
+}
+
+// LogBombPotVote - записывает голос игрока за бомб-пот
+func (al *ActionLogger) LogBombPotVote(clubID, roomID, userID string, votes, needed int) error {
+	return al.LogAction(clubID, roomID, "bomb_pot_vote", map[string]interface{}{
+		"user_id": userID,
+		"votes":   votes,
+		"needed":  needed,
+	})
+}
+
+// LogBombPotPosted - записывает постановку анте бомб-пота
+func (al *ActionLogger) LogBombPotPosted(clubID, roomID string, antes map[string]int, doubleBoard bool) error {
+	return al.LogAction(clubID, roomID, "bomb_pot_posted", map[string]interface{}{
+		"antes":        antes,
+		"double_board": doubleBoard,
+	})
+}
+
+// LogTournamentRegistered - записывает регистрацию участника турнира
+func (al *ActionLogger) LogTournamentRegistered(clubID, roomID, tournamentID, userID string, entrants int) error {
+	return al.LogAction(clubID, roomID, "tournament_registered", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+		"entrants":      entrants,
+	})
+}
+
+// LogTournamentChipsPurchased - записывает позднюю регистрацию, ребай или аддон участника турнира
+// operation - операция журнала аудита турнира (late_registration, rebuy, add_on)
+func (al *ActionLogger) LogTournamentChipsPurchased(clubID, roomID, tournamentID, userID, operation string, chips, prizePool int) error {
+	return al.LogAction(clubID, roomID, "tournament_"+operation, map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+		"chips":         chips,
+		"prize_pool":    prizePool,
+	})
+}
+
+// LogTournamentKnockout - записывает баунти, полученный за выбитого участника
+// cash - выплаченная сумма, headIncrease - сколько добавлено к баунти выбившего (прогрессивный нокаут)
+func (al *ActionLogger) LogTournamentKnockout(clubID, roomID, tournamentID, userID, eliminatedID string, cash, headIncrease int) error {
+	return al.LogAction(clubID, roomID, "tournament_knockout", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+		"eliminated_id": eliminatedID,
+		"cash":          cash,
+		"head_increase": headIncrease,
+	})
+}
+
+// LogTournamentStarted - записывает старт турнира
+func (al *ActionLogger) LogTournamentStarted(clubID, roomID, tournamentID string, entrants, prizePool int) error {
+	return al.LogAction(clubID, roomID, "tournament_started", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"entrants":      entrants,
+		"prize_pool":    prizePool,
+	})
+}
+
+// LogBlindLevelChanged - записывает переход на новый уровень блайндов или перерыв
+// millisToNext - время до следующего уровня (-1 - не ограничено по времени)
+func (al *ActionLogger) LogBlindLevelChanged(clubID, roomID string, level int, smallBlind, bigBlind, ante int, onBreak bool, millisToNext int64) error {
+	return al.LogAction(clubID, roomID, "blind_level_changed", map[string]interface{}{
+		"level":                level,
+		"small_blind":          smallBlind,
+		"big_blind":            bigBlind,
+		"ante":                 ante,
+		"on_break":             onBreak,
+		"millis_to_next_level": millisToNext,
+	})
+}
+
+// LogPlayerEliminated - записывает выбывание участника турнира
+func (al *ActionLogger) LogPlayerEliminated(clubID, roomID, tournamentID, userID string, place, prize int) error {
+	return al.LogAction(clubID, roomID, "player_eliminated", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+		"place":         place,
+		"prize":         prize,
+	})
+}
+
+// LogTournamentPlayerMoved - записывает пересадку участника турнира на другой стол
+func (al *ActionLogger) LogTournamentPlayerMoved(clubID, roomID, tournamentID, userID, fromRoomID, toRoomID string, seat int) error {
+	return al.LogAction(clubID, roomID, "tournament_player_moved", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+		"from_room_id":  fromRoomID,
+		"to_room_id":    toRoomID,
+		"seat":          seat,
+	})
+}
+
+// LogTournamentTableBroken - записывает закрытие стола турнира
+func (al *ActionLogger) LogTournamentTableBroken(clubID, roomID, tournamentID string) error {
+	return al.LogAction(clubID, roomID, "tournament_table_broken", map[string]interface{}{
+		"tournament_id": tournamentID,
+	})
+}
+
+// LogHandForHand - записывает начало и конец игры рука в руку
+func (al *ActionLogger) LogHandForHand(clubID, roomID, tournamentID string, active bool) error {
+	return al.LogAction(clubID, roomID, "hand_for_hand", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"active":        active,
+	})
+}
+
+// LogDealProposed - записывает предложение сделки за финальным столом
+func (al *ActionLogger) LogDealProposed(clubID, roomID, tournamentID, userID string, method string, payouts map[string]int) error {
+	return al.LogAction(clubID, roomID, "deal_proposed", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+		"method":        method,
+		"payouts":       payouts,
+	})
+}
+
+// LogDealAccepted - записывает согласие участника на сделку
+func (al *ActionLogger) LogDealAccepted(clubID, roomID, tournamentID, userID string) error {
+	return al.LogAction(clubID, roomID, "deal_accepted", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+	})
+}
+
+// LogDealCancelled - записывает отмену сделки (отказ участника или изменение стеков)
+func (al *ActionLogger) LogDealCancelled(clubID, roomID, tournamentID, userID, reason string) error {
+	return al.LogAction(clubID, roomID, "deal_cancelled", map[string]interface{}{
+		"tournament_id": tournamentID,
+		"user_id":       userID,
+		"reason":        reason,
+	})
+}
+
+// LogTournamentFinished - записывает завершение турнира и выплаченные призы
+func (al *ActionLogger) LogTournamentFinished(clubID, roomID, tournamentID, winnerUserID string, prizes map[string]int) error {
+	return al.LogAction(clubID, roomID, "tournament_finished", map[string]interface{}{
+		"tournament_id":  tournamentID,
+		"winner_user_id": winnerUserID,
+		"prizes":         prizes,
+	})
+}
+
 // LogCardsDealt - записывает действие раздачи карт
 func (al *ActionLogger) LogCardsDealt(clubID, roomID string, playersCount int) error {
 	return al.LogAction(clubID, roomID, "cards_dealt", map[string]interface{}{
@@ -191,6 +485,23 @@ func (al *ActionLogger) LogPotAwarded(clubID, roomID, winnerUserID string, amoun
 	})
 }
 
+// LogShowdown - записывает результат вскрытия: выигрыш каждого игрока
+func (al *ActionLogger) LogShowdown(clubID, roomID, gameID string, payouts map[string]int) error {
+	return al.LogAction(clubID, roomID, "showdown", map[string]interface{}{
+		"game_id": gameID,
+		"payouts": payouts,
+	})
+}
+
+// LogRakeTaken - записывает действие взятия рейка с раздачи
+func (al *ActionLogger) LogRakeTaken(clubID, roomID, gameID string, amount int, totalPot int) error {
+	return al.LogAction(clubID, roomID, "rake_taken", map[string]interface{}{
+		"game_id":   gameID,
+		"amount":    amount,
+		"total_pot": totalPot,
+	})
+}
+
 // LogRoundFinished - записывает действие завершения раунда
 func (al *ActionLogger) LogRoundFinished(clubID, roomID string, roundNumber int, totalPot int) error {
 	return al.LogAction(clubID, roomID, "round_finished", map[string]interface{}{
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/utils"
+)
+
+// ActionValidator - сервис для проверки действий и размеров ставок игроков
+// Учитывает структуру ставок комнаты: в безлимите можно поставить весь стек,
+// в пот-лимите максимальное повышение равно банку после колла, в фикс-лимите
+// ставки фиксированы, а количество повышений в раунде ограничено (кроме игры один на один)
+type ActionValidator struct {
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewActionValidator - создает новый экземпляр ActionValidator
+func NewActionValidator(gameStateService *GameStateService) *ActionValidator {
+	return &ActionValidator{
+		gameStateService: gameStateService,
+		logger:           utils.NewLogger("ActionValidator"),
+	}
+}
+
+// GetBetLimits - возвращает допустимые суммы колла и повышения для игрока
+func (av *ActionValidator) GetBetLimits(clubID, roomID, userID string) (*models.BetLimits, error) {
+	bc, err := av.bettingContext(clubID, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := models.CalculateBetLimits(*bc)
+	return &limits, nil
+}
+
+// GetLegalActions - возвращает допустимые действия игрока и суммы для них
+// Клиент использует результат, чтобы показать только доступные кнопки и границы ползунка ставки
+func (av *ActionValidator) GetLegalActions(clubID, roomID, userID string) (*models.LegalActions, error) {
+	bc, err := av.bettingContext(clubID, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	legal := models.CalculateLegalActions(*bc)
+	return &legal, nil
+}
+
+// ValidateAction - проверяет, что действие допустимо для игрока
+// Для ставки и повышения amount - итоговая ставка игрока в раунде
+func (av *ActionValidator) ValidateAction(clubID, roomID, userID string, action models.PlayerAction, amount int) error {
+	legal, err := av.GetLegalActions(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !legal.Allows(action) {
+		return ErrActionNotAllowed
+	}
+
+	if action == models.ActionBet || action == models.ActionRaise {
+		return av.ValidateRaise(clubID, roomID, userID, amount)
+	}
+
+	return nil
+}
+
+// ValidateRaise - проверяет сумму повышения (итоговую ставку игрока в раунде)
+// Повышение меньше минимального допустимо только как all-in
+func (av *ActionValidator) ValidateRaise(clubID, roomID, userID string, raiseTo int) error {
+	bc, err := av.bettingContext(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	legal := models.CalculateLegalActions(*bc)
+	if !legal.Allows(models.ActionBet) && !legal.Allows(models.ActionRaise) {
+		return ErrRaiseCapReached
+	}
+
+	allInTo := bc.PlayerBet + bc.Stack
+	if raiseTo > allInTo {
+		return ErrRaiseExceedsStack
+	}
+	if raiseTo > legal.MaxRaiseTo {
+		return ErrRaiseAboveLimit
+	}
+	if raiseTo <= bc.PlayerBet+legal.CallAmount {
+		return ErrRaiseBelowMinimum
+	}
+	if raiseTo < legal.MinRaiseTo && raiseTo != allInTo {
+		return ErrRaiseBelowMinimum
+	}
+
+	return nil
+}
+
+// bettingContext - собирает состояние торговли для игрока
+// Банк - сумма всех фишек, поставленных в раздаче (включая ставки текущего раунда)
+func (av *ActionValidator) bettingContext(clubID, roomID, userID string) (*models.BettingContext, error) {
+	room, err := av.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	game, err := av.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	player, err := av.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if player == nil {
+		return nil, ErrPlayerNotSeated
+	}
+
+	playerIDs, err := av.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	pot := 0
+	inHand := 0
+	for _, id := range playerIDs {
+		p, err := av.gameStateService.GetPlayer(clubID, roomID, id)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			continue
+		}
+		pot += p.TotalBet
+		if p.IsActive() || p.IsAllIn() {
+			inHand++
+		}
+	}
+
+	return &models.BettingContext{
+		Structure:    room.BettingStructure,
+		Pot:          pot,
+		CurrentBet:   game.CurrentBet,
+		LastRaise:    game.LastRaise,
+		BigBlind:     room.BigBlind,
+		FixedBetSize: room.FixedBetSize(game.Phase),
+		RaiseCount:   game.RaiseCount,
+		RaiseCap:     room.GetRaiseCap(),
+		HeadsUp:      inHand == 2,
+		PlayerBet:    player.Bet,
+		Stack:        player.Chips,
+	}, nil
+}
+
+var (
+	ErrRaiseBelowMinimum = &ActionError{message: "raise is below the minimum"}
+	ErrRaiseAboveLimit   = &ActionError{message: "raise exceeds the betting limit"}
+	ErrRaiseExceedsStack = &ActionError{message: "raise exceeds player stack"}
+	ErrRaiseCapReached   = &ActionError{message: "raise cap reached for this betting round"}
+	ErrActionNotAllowed  = &ActionError{message: "action is not allowed"}
+)
+
+type ActionError struct {
+	message string
+}
+
+func (e *ActionError) Error() string {
+	return "action error: " + e.message
+}
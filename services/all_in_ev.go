@@ -0,0 +1,297 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// AllInEVService - сервис учета эквити олл-инов (all-in adjusted winnings)
+// Когда все оставшиеся игроки в олл-ине до ривера, эквити их рук фиксируется.
+// На вскрытии каждый банк делится между его претендентами по эквити на момент олл-ина -
+// так получается ожидаемый выигрыш игрока, который записывается в историю раздачи рядом
+// с фактическим, а разница накапливается в итогах игрока по клубу
+type AllInEVService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// equityCalculator - сервис расчета эквити
+	equityCalculator *EquityCalculator
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewAllInEVService - создает новый экземпляр AllInEVService
+func NewAllInEVService(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	equityCalculator *EquityCalculator,
+	actionLogger *ActionLogger,
+) *AllInEVService {
+	return &AllInEVService{
+		redis:            redis,
+		gameStateService: gameStateService,
+		equityCalculator: equityCalculator,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("AllInEV"),
+	}
+}
+
+// Capture - фиксирует эквити игроков в момент олл-ина
+// Вызывается из RunItService.Offer и CardDealer.DealRemainingBoards: торговля закончена,
+// все оставшиеся игроки (кроме, возможно, одного, уравнявшего ставку) в олл-ине, а ривер
+// еще не открыт. Повторный вызов в той же раздаче возвращает уже зафиксированное эквити
+func (as *AllInEVService) Capture(clubID, roomID string) (*models.AllInSnapshot, error) {
+	game, err := as.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+	if !game.IsActive() {
+		return nil, ErrRunItNotAllIn
+	}
+
+	existing, err := as.GetSnapshot(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.GameID == game.GameID {
+		return existing, nil
+	}
+
+	if len(game.CommunityCards) >= models.MaxCommunityCards {
+		return nil, ErrRunItBoardComplete
+	}
+	if _, err := allInPlayers(as.gameStateService, clubID, roomID, game); err != nil {
+		return nil, err
+	}
+
+	request, err := as.equityCalculator.RoomRequest(clubID, roomID, 0)
+	if err != nil {
+		return nil, err
+	}
+	result, err := as.equityCalculator.Calculate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.AllInSnapshot{
+		GameID:    game.GameID,
+		Request:   *request,
+		Equity:    result.Players,
+		Timestamp: utils.GetCurrentTimestampMillis(),
+	}
+
+	// Эквити каждого банка считается сразу: торговля закончена, и составы претендентов
+	// основного и боковых банков уже известны по ставкам игроков
+	bets := make(map[string]int, len(request.Players))
+	for _, equityPlayer := range request.Players {
+		player, err := as.gameStateService.GetPlayer(clubID, roomID, equityPlayer.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if player != nil {
+			bets[equityPlayer.UserID] = player.TotalBet
+		}
+	}
+	for _, players := range models.AllInEligibleSets(bets) {
+		var equity map[string]float64
+		if len(players) == len(result.Players) {
+			equity = make(map[string]float64, len(result.Players))
+			for _, player := range result.Players {
+				equity[player.UserID] = player.Equity
+			}
+		} else {
+			equity, err = as.potEquity(snapshot, players)
+			if err != nil {
+				return nil, err
+			}
+		}
+		snapshot.Pots = append(snapshot.Pots, models.AllInPotEquity{Players: players, Equity: equity})
+	}
+
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при сериализации эквити олл-ина: %w", err)
+	}
+	if err := as.redis.Set(as.redis.GetKeys().RoomAllInEquity(clubID, roomID), string(snapshotJSON), 0); err != nil {
+		as.logger.Errorf("Ошибка при сохранении эквити олл-ина в комнате %s:%s: %v", clubID, roomID, err)
+		return nil, fmt.Errorf("ошибка сохранения эквити олл-ина: %w", err)
+	}
+
+	equity := make(map[string]float64, len(result.Players))
+	for _, player := range result.Players {
+		equity[player.UserID] = player.Equity
+	}
+	as.actionLogger.LogAllInEquity(clubID, roomID, game.GameID, request.Board, equity)
+	as.logger.Infof("Олл-ин в комнате %s:%s: эквити %d игроков зафиксировано (досок: %d)",
+		clubID, roomID, len(result.Players), result.Boards)
+
+	return snapshot, nil
+}
+
+// Apply - записывает в историю раздачи эквити и ожидаемый выигрыш участников олл-ина
+// и обновляет их накопленные итоги. Вызывается на вскрытии до сохранения истории.
+// Ожидаемый выигрыш - сумма по банкам (после рейка) долей, положенных игроку по эквити
+// среди претендентов этого банка. Если олл-ина в раздаче не было, ничего не делает
+func (as *AllInEVService) Apply(history *models.HandHistory) error {
+	clubID, roomID := history.ClubID, history.RoomID
+
+	snapshot, err := as.GetSnapshot(clubID, roomID)
+	if err != nil || snapshot == nil {
+		return err
+	}
+	if err := as.redis.Del(as.redis.GetKeys().RoomAllInEquity(clubID, roomID)); err != nil {
+		return err
+	}
+	if snapshot.GameID != history.GameID {
+		as.logger.Warningf("Эквити олл-ина в комнате %s:%s относится к другой раздаче (%s), пропускаем",
+			clubID, roomID, snapshot.GameID)
+		return nil
+	}
+
+	hands := make(map[string][]string, len(snapshot.Request.Players))
+	for _, player := range snapshot.Request.Players {
+		hands[player.UserID] = player.Cards
+	}
+
+	// Банки делятся по эквити, зафиксированному в момент олл-ина. Если состав банка тогда
+	// не был предусмотрен, его эквити досчитывается и запоминается для долей других прогонов
+	expected := make(map[string]float64)
+	for _, pot := range history.Pots {
+		if pot.Amount <= 0 {
+			continue
+		}
+
+		var eligible []string
+		for _, userID := range pot.EligiblePlayers {
+			if _, ok := hands[userID]; ok {
+				eligible = append(eligible, userID)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+		sort.Strings(eligible)
+
+		equity, ok := snapshot.PotEquity(eligible)
+		if !ok {
+			as.logger.Warningf("Раздача %s: эквити банка %v не зафиксировано в момент олл-ина, рассчитываем",
+				history.GameID, eligible)
+			equity, err = as.potEquity(snapshot, eligible)
+			if err != nil {
+				return err
+			}
+			snapshot.Pots = append(snapshot.Pots, models.AllInPotEquity{Players: eligible, Equity: equity})
+		}
+
+		for userID, share := range equity {
+			expected[userID] += float64(pot.Amount) * share
+		}
+	}
+
+	equity := make(map[string]float64, len(snapshot.Equity))
+	for _, player := range snapshot.Equity {
+		equity[player.UserID] = player.Equity
+	}
+
+	history.AllInBoard = snapshot.Request.Board
+
+	keys := as.redis.GetKeys()
+	ctx := as.redis.GetContext()
+	pipe := as.redis.TxPipeline()
+	for i := range history.Players {
+		player := &history.Players[i]
+		if _, ok := hands[player.UserID]; !ok {
+			continue
+		}
+
+		player.AllInEquity = equity[player.UserID]
+		player.ExpectedWon = int(math.Round(expected[player.UserID]))
+
+		statsKey := keys.UserAllInEV(clubID, player.UserID)
+		pipe.HIncrBy(ctx, statsKey, "hands", 1)
+		pipe.HIncrBy(ctx, statsKey, "net", int64(player.Won-player.TotalBet))
+		pipe.HIncrBy(ctx, statsKey, "ev_net", int64(player.ExpectedWon-player.TotalBet))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		as.logger.Errorf("Ошибка при обновлении итогов олл-инов в комнате %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("ошибка обновления итогов олл-инов: %w", err)
+	}
+
+	as.logger.Infof("Раздача %s: ожидаемые выигрыши участников олл-ина записаны", history.GameID)
+	return nil
+}
+
+// GetSnapshot - эквити, зафиксированное в текущей раздаче комнаты (nil, если олл-ина не было)
+func (as *AllInEVService) GetSnapshot(clubID, roomID string) (*models.AllInSnapshot, error) {
+	data, err := as.redis.Get(as.redis.GetKeys().RoomAllInEquity(clubID, roomID))
+	if err != nil {
+		return nil, err
+	}
+	if data == "" {
+		return nil, nil
+	}
+
+	var snapshot models.AllInSnapshot
+	if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+		return nil, fmt.Errorf("ошибка при парсинге эквити олл-ина: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// GetStats - накопленные итоги олл-инов игрока в клубе
+func (as *AllInEVService) GetStats(clubID, userID string) (*models.AllInEVStats, error) {
+	data, err := as.redis.HGetAll(as.redis.GetKeys().UserAllInEV(clubID, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewAllInEVStatsFromRedis(userID, data), nil
+}
+
+// potEquity - эквити претендентов банка на момент олл-ина
+// Руки остальных участников олл-ина известны и в доску прийти не могут
+func (as *AllInEVService) potEquity(snapshot *models.AllInSnapshot, eligible []string) (map[string]float64, error) {
+	inPot := make(map[string]bool, len(eligible))
+	for _, userID := range eligible {
+		inPot[userID] = true
+	}
+
+	request := &models.EquityRequest{
+		GameType: snapshot.Request.GameType,
+		Board:    snapshot.Request.Board,
+		Dead:     append([]string{}, snapshot.Request.Dead...),
+	}
+	for _, player := range snapshot.Request.Players {
+		if inPot[player.UserID] {
+			request.Players = append(request.Players, player)
+		} else {
+			request.Dead = append(request.Dead, player.Cards...)
+		}
+	}
+
+	result, err := as.equityCalculator.Calculate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	equity := make(map[string]float64, len(result.Players))
+	for _, player := range result.Players {
+		equity[player.UserID] = player.Equity
+	}
+
+	return equity, nil
+}
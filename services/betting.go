@@ -0,0 +1,198 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// BettingService - сервис применения действий игроков в раздаче
+// Действие проверяется ActionValidator, фишки уходят из стека в банк только через журнал
+// (ChipLedger.AppendBet) в одной транзакции со ставкой игрока и состоянием торговли.
+// Действие записывается в журнал раздачи для статистики; если после сброса карт в раздаче
+// остался один игрок, раздача завершается без вскрытия
+type BettingService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// actionValidator - сервис проверки действий игроков
+	actionValidator *ActionValidator
+
+	// ledger - журнал движения фишек
+	ledger *ChipLedger
+
+	// showdown - сервис завершения раздачи
+	showdown *ShowdownService
+
+	// playerStats - сервис статистики игроков
+	playerStats *PlayerStatsService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewBettingService - создает новый экземпляр BettingService
+func NewBettingService(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	actionValidator *ActionValidator,
+	ledger *ChipLedger,
+	showdown *ShowdownService,
+	playerStats *PlayerStatsService,
+	actionLogger *ActionLogger,
+) *BettingService {
+	return &BettingService{
+		redis:            redis,
+		gameStateService: gameStateService,
+		actionValidator:  actionValidator,
+		ledger:           ledger,
+		showdown:         showdown,
+		playerStats:      playerStats,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("Betting"),
+	}
+}
+
+// ApplyAction - применяет действие игрока
+// Для ставки и повышения amount - итоговая ставка игрока в раунде, для остальных действий не используется.
+// Возвращает игрока после действия
+func (bs *BettingService) ApplyAction(clubID, roomID, userID string, action models.PlayerAction, amount int) (*models.Player, error) {
+	if err := bs.actionValidator.ValidateAction(clubID, roomID, userID, action, amount); err != nil {
+		return nil, err
+	}
+
+	game, err := bs.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	player, err := bs.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if player == nil {
+		return nil, ErrPlayerNotSeated
+	}
+
+	betTo := player.Bet
+	switch action {
+	case models.ActionCall:
+		betTo = game.CurrentBet
+	case models.ActionBet, models.ActionRaise:
+		betTo = amount
+	case models.ActionAllIn:
+		betTo = player.Bet + player.Chips
+	}
+	if betTo > player.Bet+player.Chips {
+		betTo = player.Bet + player.Chips
+	}
+	chips := betTo - player.Bet
+
+	// Ставка улицы и размер последнего повышения меняются только ставкой выше текущей.
+	// Неполное повышение олл-ином не открывает торговлю заново и не считается повышением
+	currentBet, lastRaise, raiseCount := game.CurrentBet, game.LastRaise, game.RaiseCount
+	if betTo > currentBet {
+		if betTo-currentBet >= lastRaise {
+			lastRaise = betTo - currentBet
+			raiseCount++
+		}
+		currentBet = betTo
+	}
+
+	// Фишки игрока в Redis меняет журнал, здесь меняется только его копия в памяти
+	if action == models.ActionFold {
+		player.SetStatus(models.PlayerStatusFolded)
+	} else if chips == player.Chips && chips > 0 {
+		player.GoAllIn()
+	} else if chips > 0 {
+		player.PlaceBet(chips)
+	}
+	player.SetLastAction(action)
+
+	keys := bs.redis.GetKeys()
+	ctx := bs.redis.GetContext()
+	pipe := bs.redis.TxPipeline()
+
+	if chips > 0 {
+		reference := models.HandReference(game.GameID, game.RoundNumber)
+		if err := bs.ledger.AppendBet(pipe, clubID, roomID, userID, chips, reference); err != nil {
+			return nil, err
+		}
+		pipe.HIncrBy(ctx, keys.GameState(clubID, roomID), "pot", int64(chips))
+	}
+	pipe.HSet(ctx, keys.PlayerInfo(clubID, roomID, userID), map[string]interface{}{
+		"status":      string(player.Status),
+		"bet":         player.Bet,
+		"total_bet":   player.TotalBet,
+		"last_action": string(action),
+	})
+	pipe.HSet(ctx, keys.GameState(clubID, roomID), map[string]interface{}{
+		"current_bet": currentBet,
+		"last_raise":  lastRaise,
+		"raise_count": raiseCount,
+	})
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		bs.logger.Errorf("Ошибка при применении действия %s игрока %s в комнате %s:%s: %v", action, userID, clubID, roomID, err)
+		return nil, fmt.Errorf("ошибка применения действия игрока: %w", err)
+	}
+
+	bs.actionLogger.LogPlayerAction(clubID, roomID, userID, string(action), chips)
+	bs.logger.Debugf("Игрок %s в комнате %s:%s: %s %d (ставка %d)", userID, clubID, roomID, action, chips, player.Bet)
+
+	handAction := &models.HandAction{
+		GameID: game.GameID,
+		UserID: userID,
+		Phase:  game.Phase,
+		Action: action,
+		Amount: player.Bet,
+	}
+	if err := bs.playerStats.RecordAction(clubID, roomID, handAction); err != nil {
+		bs.logger.Warningf("Не удалось записать действие игрока %s для статистики: %v", userID, err)
+	}
+
+	if action == models.ActionFold {
+		if err := bs.finishIfFoldedOut(clubID, roomID); err != nil {
+			return nil, err
+		}
+	}
+
+	return player, nil
+}
+
+// finishIfFoldedOut - завершает раздачу, если карты не сбросил только один игрок
+// Банк присуждается ему без вскрытия (ShowdownService.Showdown), статистика раздачи обновляется там же
+func (bs *BettingService) finishIfFoldedOut(clubID, roomID string) error {
+	playerIDs, err := bs.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	remaining := 0
+	for _, id := range playerIDs {
+		p, err := bs.gameStateService.GetPlayer(clubID, roomID, id)
+		if err != nil {
+			return err
+		}
+		if p != nil && (p.IsActive() || p.IsAllIn()) {
+			remaining++
+		}
+	}
+	if remaining > 1 {
+		return nil
+	}
+
+	if _, err := bs.showdown.Showdown(clubID, roomID); err != nil {
+		return fmt.Errorf("не удалось завершить раздачу после сброса карт: %w", err)
+	}
+
+	return nil
+}
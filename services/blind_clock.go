@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// BlindClockService - сервис часов уровней блайндов
+// Ведет расписание блайндов турнирных комнат и кэш-комнат с растущими блайндами.
+// Часы хранятся в Redis с абсолютным временем начала уровня и после перезапуска
+// движка продолжают идти с того же места. Новый уровень применяется к комнате
+// только в начале следующей раздачи (StartHand)
+type BlindClockService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewBlindClockService - создает новый экземпляр BlindClockService
+func NewBlindClockService(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	actionLogger *ActionLogger,
+) *BlindClockService {
+	return &BlindClockService{
+		redis:            redis,
+		gameStateService: gameStateService,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("BlindClock"),
+	}
+}
+
+// Start - запускает часы комнаты с первого уровня расписания
+// Первый уровень не может быть перерывом; его блайнды сразу ставятся в комнату
+func (bc *BlindClockService) Start(clubID, roomID string, levels []models.BlindLevel) error {
+	if len(levels) == 0 || levels[0].IsBreak {
+		return ErrInvalidBlindSchedule
+	}
+	for _, level := range levels {
+		if !level.IsValid() {
+			return ErrInvalidBlindSchedule
+		}
+	}
+
+	clock := &models.BlindClock{
+		Levels:         levels,
+		LevelIndex:     0,
+		LevelStartedAt: utils.GetCurrentTimestampMillis(),
+	}
+
+	if err := bc.saveClock(clubID, roomID, clock); err != nil {
+		return err
+	}
+	if err := bc.applyBlinds(clubID, roomID, clock.CurrentBlinds()); err != nil {
+		return err
+	}
+
+	bc.logLevelChange(clubID, roomID, clock, clock.LevelStartedAt)
+	bc.logger.Infof("Часы блайндов комнаты %s:%s запущены (%d уровней)", clubID, roomID, len(levels))
+
+	return nil
+}
+
+// Stop - останавливает часы комнаты (блайнды комнаты остаются последними примененными)
+func (bc *BlindClockService) Stop(clubID, roomID string) error {
+	return bc.redis.Del(bc.redis.GetKeys().RoomBlindClock(clubID, roomID))
+}
+
+// GetClock - получает часы комнаты (nil, если у комнаты нет расписания блайндов)
+func (bc *BlindClockService) GetClock(clubID, roomID string) (*models.BlindClock, error) {
+	data, err := bc.redis.HGetAll(bc.redis.GetKeys().RoomBlindClock(clubID, roomID))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return models.NewBlindClockFromRedis(data)
+}
+
+// GetStatus - текущий уровень и время до следующего уровня для клиентов
+// (nil, если у комнаты нет расписания блайндов)
+func (bc *BlindClockService) GetStatus(clubID, roomID string) (*models.BlindClockStatus, error) {
+	clock, err := bc.GetClock(clubID, roomID)
+	if err != nil || clock == nil {
+		return nil, err
+	}
+
+	now := utils.GetCurrentTimestampMillis()
+	clock.Advance(now)
+	return clock.Status(now), nil
+}
+
+// StartHand - переводит часы и применяет новый уровень перед началом раздачи
+// Вызывается до постановки блайндов. Возвращает true, если идет перерыв
+// и раздачу начинать нельзя. Комнаты без расписания блайндов не затрагиваются
+func (bc *BlindClockService) StartHand(clubID, roomID string) (bool, error) {
+	clock, err := bc.GetClock(clubID, roomID)
+	if err != nil {
+		return false, err
+	}
+	if clock == nil {
+		return false, nil
+	}
+
+	now := utils.GetCurrentTimestampMillis()
+	if clock.Advance(now) {
+		if err := bc.saveClock(clubID, roomID, clock); err != nil {
+			return false, err
+		}
+		if !clock.IsOnBreak() {
+			if err := bc.applyBlinds(clubID, roomID, clock.CurrentBlinds()); err != nil {
+				return false, err
+			}
+		}
+
+		bc.logLevelChange(clubID, roomID, clock, now)
+	}
+
+	if clock.IsOnBreak() {
+		return true, nil
+	}
+
+	clockKey := bc.redis.GetKeys().RoomBlindClock(clubID, roomID)
+	if _, err := bc.redis.HIncrBy(clockKey, "level_hands", 1); err != nil {
+		return false, fmt.Errorf("ошибка учета раздачи на уровне блайндов: %w", err)
+	}
+
+	return false, nil
+}
+
+// applyBlinds - ставит в комнату блайнды и анте уровня
+func (bc *BlindClockService) applyBlinds(clubID, roomID string, level models.BlindLevel) error {
+	anteType := models.AnteNone
+	if level.Ante > 0 {
+		anteType = models.AntePerPlayer
+	}
+
+	return bc.gameStateService.UpdateRoomInfo(clubID, roomID, map[string]interface{}{
+		"small_blind": level.SmallBlind,
+		"big_blind":   level.BigBlind,
+		"ante":        level.Ante,
+		"ante_type":   string(anteType),
+	})
+}
+
+// saveClock - сохраняет часы комнаты
+func (bc *BlindClockService) saveClock(clubID, roomID string, clock *models.BlindClock) error {
+	if err := bc.redis.HMSet(bc.redis.GetKeys().RoomBlindClock(clubID, roomID), clock.ToRedisHash()); err != nil {
+		bc.logger.Errorf("Ошибка при сохранении часов блайндов %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("ошибка сохранения часов блайндов: %w", err)
+	}
+	return nil
+}
+
+// logLevelChange - записывает смену уровня в историю комнаты
+func (bc *BlindClockService) logLevelChange(clubID, roomID string, clock *models.BlindClock, nowMillis int64) {
+	blinds := clock.CurrentBlinds()
+	onBreak := clock.IsOnBreak()
+
+	bc.actionLogger.LogBlindLevelChanged(clubID, roomID, clock.LevelIndex+1,
+		blinds.SmallBlind, blinds.BigBlind, blinds.Ante, onBreak, clock.MillisToNextLevel(nowMillis))
+
+	if onBreak {
+		bc.logger.Infof("Комната %s:%s: перерыв (уровень %d)", clubID, roomID, clock.LevelIndex+1)
+	} else {
+		bc.logger.Infof("Комната %s:%s: уровень %d, блайнды %d/%d, анте %d",
+			clubID, roomID, clock.LevelIndex+1, blinds.SmallBlind, blinds.BigBlind, blinds.Ante)
+	}
+}
+
+var (
+	ErrInvalidBlindSchedule = &BlindClockError{message: "invalid blind schedule"}
+)
+
+type BlindClockError struct {
+	message string
+}
+
+func (e *BlindClockError) Error() string {
+	return "blind clock error: " + e.message
+}
@@ -0,0 +1,821 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// PostedBlinds - результат постановки блайндов в начале раздачи
+type PostedBlinds struct {
+	// Игрок на малом блайнде ("" - малый блайнд не ставился)
+	SmallBlindUser string
+
+	// Игрок на большом блайнде
+	BigBlindUser string
+
+	// Фактически поставленные суммы (меньше номинала, если игрок ушел в all-in)
+	SmallBlindAmount int
+	BigBlindAmount   int
+
+	// Игрок, поставивший анте дилера, и его сумма (только в комнатах с анте дилера)
+	ButtonAnteUser   string
+	ButtonAnteAmount int
+
+	// Поставленные анте (ключ - ID игрока, значение - фактическая сумма)
+	Antes map[string]int
+
+	// Стрэддлы в порядке постановки (последний ходит последним на префлопе)
+	Straddles []models.StraddlePost
+
+	// Раздача - бомб-пот: вместо блайндов каждый игрок поставил анте (Antes)
+	BombPot bool
+
+	// Сумма всех обязательных ставок (включая оплату пропущенных блайндов)
+	Pot int
+
+	// Игроки, получающие карты в этой раздаче (по часовой стрелке от дилера)
+	DealtIn []string
+}
+
+// BlindPoster - сервис для постановки блайндов в начале раздачи
+// Определяет малый и большой блайнды от позиции дилера, отмечает блайнды,
+// пропущенные игроками вне игры, и списывает оплату пропущенных блайндов
+// с вернувшихся игроков. В комнатах с анте дилера вместо блайндов дилер ставит
+// одно анте за весь стол, в стаде торговлю открывает bring-in, а в бомб-поте
+// каждый игрок ставит одинаковое анте.
+// Все списания проходят через журнал фишек
+type BlindPoster struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// ledger - журнал движения фишек
+	ledger *ChipLedger
+
+	// sitOutManager - сервис пропуска раздач
+	sitOutManager *SitOutManager
+
+	// playerStats - сервис статистики игроков
+	playerStats *PlayerStatsService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewBlindPoster - создает новый экземпляр BlindPoster
+func NewBlindPoster(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	ledger *ChipLedger,
+	sitOutManager *SitOutManager,
+	playerStats *PlayerStatsService,
+	actionLogger *ActionLogger,
+) *BlindPoster {
+	return &BlindPoster{
+		redis:            redis,
+		gameStateService: gameStateService,
+		ledger:           ledger,
+		sitOutManager:    sitOutManager,
+		playerStats:      playerStats,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("BlindPoster"),
+	}
+}
+
+// PostBlinds - ставит блайнды в начале раздачи
+// Вызывается после перемещения дилера (PrepareGameStart) и до раздачи карт
+func (bp *BlindPoster) PostBlinds(clubID, roomID string) (*PostedBlinds, error) {
+	room, err := bp.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	game, err := bp.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	if err := bp.sitOutManager.ApplyPendingSitOuts(clubID, roomID); err != nil {
+		return nil, err
+	}
+
+	// Игроки с фишками по часовой стрелке от дилера (включая сидящих вне игры)
+	ordered, err := bp.loadPlayersFromDealer(clubID, roomID, game.DealerPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	if room.GameType.IsStud() {
+		return bp.prepareStudHand(clubID, roomID, room, game, ordered)
+	}
+
+	if game.IsBombPot {
+		return bp.postBombPot(clubID, roomID, room, game, ordered)
+	}
+
+	if room.UsesButtonAnte() {
+		return bp.postButtonAnte(clubID, roomID, room, game, ordered)
+	}
+
+	sbPlayer, bbPlayer, ordered, err := bp.assignBlinds(clubID, roomID, ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := models.HandReference(game.GameID, game.RoundNumber)
+	result := &PostedBlinds{BigBlindUser: bbPlayer.UserID}
+
+	// Большой блайнд снимает ожидание и покрывает пропущенные блайнды
+	bbPlayer.WaitForBigBlind = false
+	bbPlayer.PostMissedBlinds = false
+	bbPlayer.MissedSmallBlind = false
+	bbPlayer.MissedBigBlind = false
+
+	// Анте каждого игрока и анте дилера ставятся до блайндов: если стека не хватает
+	// на все, в первую очередь ставится анте. Анте большого блайнда - наоборот, после блайнда
+	anteFirst := room.AnteType != models.AnteBigBlind
+	if anteFirst {
+		if result.Antes, err = bp.postAntes(clubID, roomID, room, ordered, bbPlayer, reference); err != nil {
+			return nil, err
+		}
+	}
+
+	if sbPlayer != nil {
+		result.SmallBlindUser = sbPlayer.UserID
+		result.SmallBlindAmount, err = bp.postForcedBet(clubID, roomID, sbPlayer, room.SmallBlind, true, reference)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.BigBlindAmount, err = bp.postForcedBet(clubID, roomID, bbPlayer, room.BigBlind, true, reference)
+	if err != nil {
+		return nil, err
+	}
+	if !anteFirst {
+		if result.Antes, err = bp.postAntes(clubID, roomID, room, ordered, bbPlayer, reference); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Pot = result.SmallBlindAmount + result.BigBlindAmount + sumAntes(result.Antes)
+
+	for _, player := range ordered {
+		if !player.CanBeDealtIn() {
+			continue
+		}
+
+		// Вернувшийся игрок оплачивает пропущенные блайнды:
+		// большой - живой ставкой, малый - мертвым блайндом (только в банк)
+		if player.PostMissedBlinds {
+			posted, err := bp.postMissedBlinds(clubID, roomID, player, room, reference)
+			if err != nil {
+				return nil, err
+			}
+			result.Pot += posted
+		}
+
+		result.DealtIn = append(result.DealtIn, player.UserID)
+	}
+
+	result.Straddles, err = bp.postStraddles(clubID, roomID, room, ordered, sbPlayer, bbPlayer, reference)
+	if err != nil {
+		return nil, err
+	}
+	for _, straddle := range result.Straddles {
+		result.Pot += straddle.Amount
+	}
+
+	if err := bp.saveHandState(clubID, roomID, ordered, game.DealerPosition, sbPlayer, bbPlayer, room.BigBlind, result.Pot, result.Straddles); err != nil {
+		return nil, err
+	}
+	bp.recordForcedBets(clubID, roomID, game.GameID, models.GamePhasePreFlop, ordered)
+
+	bp.actionLogger.LogBlindsPosted(clubID, roomID, result.SmallBlindUser, result.BigBlindUser,
+		result.SmallBlindAmount, result.BigBlindAmount)
+	bp.logger.Infof("Блайнды в комнате %s:%s: SB %s (%d), BB %s (%d), стрэддлов: %d, игроков в раздаче: %d",
+		clubID, roomID, result.SmallBlindUser, result.SmallBlindAmount,
+		result.BigBlindUser, result.BigBlindAmount, len(result.Straddles), len(result.DealtIn))
+
+	return result, nil
+}
+
+// postButtonAnte - ставит анте дилера вместо блайндов
+// Анте - мертвая ставка: идет в банк, но не засчитывается в ставку раунда,
+// поэтому торговля на префлопе начинается с нулевой ставки
+func (bp *BlindPoster) postButtonAnte(clubID, roomID string, room *models.Room, game *models.Game, ordered []*models.Player) (*PostedBlinds, error) {
+	eligible, err := playersWithoutBlinds(ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	// Игроки идут по часовой стрелке от дилера, поэтому последний в списке - дилер
+	// (или ближайший к нему игрок справа, если дилер вне игры)
+	buttonPlayer := eligible[len(eligible)-1]
+	reference := models.HandReference(game.GameID, game.RoundNumber)
+
+	amount, err := bp.postForcedBet(clubID, roomID, buttonPlayer, room.GetButtonAnte(), false, reference)
+	if err != nil {
+		return nil, err
+	}
+	buttonPlayer.TableAnte = amount
+
+	result := &PostedBlinds{
+		ButtonAnteUser:   buttonPlayer.UserID,
+		ButtonAnteAmount: amount,
+		Pot:              amount,
+	}
+	for _, player := range eligible {
+		result.DealtIn = append(result.DealtIn, player.UserID)
+	}
+
+	if err := bp.saveHandState(clubID, roomID, ordered, game.DealerPosition, nil, nil, 0, result.Pot, nil); err != nil {
+		return nil, err
+	}
+
+	bp.actionLogger.LogButtonAntePosted(clubID, roomID, buttonPlayer.UserID, amount)
+	bp.logger.Infof("Анте дилера в комнате %s:%s: %s (%d), игроков в раздаче: %d",
+		clubID, roomID, buttonPlayer.UserID, amount, len(result.DealtIn))
+
+	return result, nil
+}
+
+// postBombPot - ставит анте бомб-пота с каждого игрока вместо блайндов
+// Анте не засчитывается в ставку раунда: торговли на префлопе нет, и на флопе
+// она начинается с нулевой ставки. Все игроки вносят поровну, поэтому анте - обычный
+// вклад в банк; игрок, которому не хватило на полное анте, оказывается all-in
+func (bp *BlindPoster) postBombPot(clubID, roomID string, room *models.Room, game *models.Game, ordered []*models.Player) (*PostedBlinds, error) {
+	eligible, err := playersWithoutBlinds(ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := models.HandReference(game.GameID, game.RoundNumber)
+	result := &PostedBlinds{BombPot: true, Antes: make(map[string]int, len(eligible))}
+
+	for _, player := range eligible {
+		amount, err := bp.postForcedBet(clubID, roomID, player, room.GetBombPotAnte(), false, reference)
+		if err != nil {
+			return nil, err
+		}
+		result.Antes[player.UserID] = amount
+		result.Pot += amount
+		result.DealtIn = append(result.DealtIn, player.UserID)
+	}
+
+	if err := bp.saveHandState(clubID, roomID, ordered, game.DealerPosition, nil, nil, 0, result.Pot, nil); err != nil {
+		return nil, err
+	}
+
+	bp.actionLogger.LogBombPotPosted(clubID, roomID, result.Antes, room.BombPotDoubleBoard)
+	bp.logger.Infof("Бомб-пот в комнате %s:%s: банк %d, игроков в раздаче: %d",
+		clubID, roomID, result.Pot, len(result.DealtIn))
+
+	return result, nil
+}
+
+// prepareStudHand - готовит раздачу стада: блайндов нет, торговлю открывает bring-in
+// после раздачи третьей улицы (см. PostBringIn)
+func (bp *BlindPoster) prepareStudHand(clubID, roomID string, room *models.Room, game *models.Game, ordered []*models.Player) (*PostedBlinds, error) {
+	eligible, err := playersWithoutBlinds(ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PostedBlinds{}
+	for _, player := range eligible {
+		result.DealtIn = append(result.DealtIn, player.UserID)
+	}
+
+	// В стаде анте ставит каждый игрок (или дилер за весь стол)
+	reference := models.HandReference(game.GameID, game.RoundNumber)
+	if result.Antes, err = bp.postAntes(clubID, roomID, room, ordered, nil, reference); err != nil {
+		return nil, err
+	}
+	result.Pot = sumAntes(result.Antes)
+
+	if err := bp.saveHandState(clubID, roomID, ordered, game.DealerPosition, nil, nil, 0, result.Pot, nil); err != nil {
+		return nil, err
+	}
+
+	bp.logger.Infof("Раздача стада в комнате %s:%s: игроков в раздаче: %d", clubID, roomID, len(result.DealtIn))
+
+	return result, nil
+}
+
+// PostBringIn - ставит обязательную ставку bring-in в стаде
+// Вызывается после раздачи третьей улицы; игрока определяет StudActionOrder.GetBringIn.
+// Bring-in - живая ставка, но не полная: следующий игрок может дополнить ее до малой ставки.
+// Возвращает фактически поставленную сумму
+func (bp *BlindPoster) PostBringIn(clubID, roomID, userID string) (int, error) {
+	room, err := bp.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return 0, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	game, err := bp.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return 0, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	player, err := bp.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if player == nil {
+		return 0, ErrPlayerNotSeated
+	}
+
+	reference := models.HandReference(game.GameID, game.RoundNumber)
+	amount, err := bp.postForcedBet(clubID, roomID, player, room.GetBringIn(), true, reference)
+	if err != nil {
+		return 0, err
+	}
+
+	status := models.PlayerStatusActive
+	if !player.HasChips() {
+		status = models.PlayerStatusAllIn
+	}
+
+	keys := bp.redis.GetKeys()
+	ctx := bp.redis.GetContext()
+	pipe := bp.redis.TxPipeline()
+	pipe.HSet(ctx, keys.PlayerInfo(clubID, roomID, userID), map[string]interface{}{
+		"status":    string(status),
+		"bet":       player.Bet,
+		"total_bet": player.TotalBet,
+	})
+	pipe.HSet(ctx, keys.GameState(clubID, roomID), map[string]interface{}{
+		"current_bet": amount,
+		"last_raise":  0,
+		"raise_count": 0, // Bring-in не считается ставкой: дополнение до малой ставки - первая ставка
+	})
+	pipe.HIncrBy(ctx, keys.GameState(clubID, roomID), "pot", int64(amount))
+	if _, err := pipe.Exec(ctx); err != nil {
+		bp.logger.Errorf("Ошибка при сохранении bring-in в комнате %s:%s: %v", clubID, roomID, err)
+		return 0, fmt.Errorf("ошибка сохранения bring-in: %w", err)
+	}
+
+	bp.recordForcedBets(clubID, roomID, game.GameID, models.GamePhaseThirdStreet, []*models.Player{player})
+	bp.actionLogger.LogBringInPosted(clubID, roomID, userID, amount)
+	bp.logger.Infof("Bring-in в комнате %s:%s: %s (%d)", clubID, roomID, userID, amount)
+
+	return amount, nil
+}
+
+// postStraddles - ставит стрэддлы игроков, заявивших о них перед раздачей
+// Первым стрэддл ставит дилер (Mississippi, если разрешен), иначе игрок после большого
+// блайнда (UTG). Рестрэддлы ставят следующие игроки по часовой стрелке (блайнды
+// пропускаются), каждый вдвое больше предыдущего, пока не наберется максимум стрэддлов
+// или следующий игрок не откажется. Стрэддл - живая ставка, поставивший его игрок
+// ходит на префлопе последним. Заявка действует на одну раздачу
+func (bp *BlindPoster) postStraddles(clubID, roomID string, room *models.Room, ordered []*models.Player, sbPlayer, bbPlayer *models.Player, reference string) ([]models.StraddlePost, error) {
+	defer func() {
+		for _, player := range ordered {
+			player.WantsStraddle = false
+		}
+	}()
+
+	if !room.AllowsStraddle() {
+		return nil, nil
+	}
+
+	// Игроки после большого блайнда по часовой стрелке (без блайндов), дилер - последний
+	var afterBB []*models.Player
+	bbIndex := -1
+	for i, player := range ordered {
+		if player == bbPlayer {
+			bbIndex = i
+			break
+		}
+	}
+	for i := 1; bbIndex >= 0 && i < len(ordered); i++ {
+		player := ordered[(bbIndex+i)%len(ordered)]
+		if player != sbPlayer && player.CanBeDealtIn() {
+			afterBB = append(afterBB, player)
+		}
+	}
+	if len(afterBB) == 0 {
+		return nil, nil
+	}
+
+	var chain []*models.Player
+	button := afterBB[len(afterBB)-1]
+	switch {
+	case room.ButtonStraddle && button.WantsStraddle && button == ordered[len(ordered)-1]:
+		chain = append([]*models.Player{button}, afterBB[:len(afterBB)-1]...)
+	case room.UTGStraddle && afterBB[0].WantsStraddle:
+		chain = afterBB
+	default:
+		return nil, nil
+	}
+
+	var straddles []models.StraddlePost
+	amount := room.BigBlind
+	for _, player := range chain {
+		if len(straddles) >= room.GetMaxStraddles() || !player.WantsStraddle {
+			break
+		}
+
+		amount *= 2
+		if player.Chips < amount {
+			break
+		}
+
+		posted, err := bp.postForcedBet(clubID, roomID, player, amount, true, reference)
+		if err != nil {
+			return nil, err
+		}
+		player.Straddle = posted
+		straddles = append(straddles, models.StraddlePost{UserID: player.UserID, Seat: player.Position, Amount: posted})
+
+		bp.actionLogger.LogStraddlePosted(clubID, roomID, player.UserID, posted, len(straddles))
+	}
+
+	return straddles, nil
+}
+
+// RequestStraddle - заявка игрока на стрэддл в следующей раздаче (или ее отмена)
+func (bp *BlindPoster) RequestStraddle(clubID, roomID, userID string, wants bool) error {
+	room, err := bp.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+	if !room.AllowsStraddle() {
+		return ErrStraddleNotAllowed
+	}
+
+	player, err := bp.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if player == nil {
+		return ErrPlayerNotSeated
+	}
+
+	playerKey := bp.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
+	if err := bp.redis.HSet(playerKey, "wants_straddle", wants); err != nil {
+		bp.logger.Errorf("Ошибка при сохранении заявки на стрэддл игрока %s: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
+
+// postAntes - ставит анте раздачи
+// Анте - мертвые фишки: идут в банк, но не засчитываются в текущую ставку игрока.
+// Анте дилера и большого блайнда ставится за весь стол и целиком идет в основной банк.
+// Если стека не хватает на полное анте, игрок ставит сколько может и оказывается all-in;
+// боковые банки строятся по фактическим вкладам (PotManager.CollectPots)
+// Возвращает поставленные суммы по игрокам
+func (bp *BlindPoster) postAntes(clubID, roomID string, room *models.Room, ordered []*models.Player, bbPlayer *models.Player, reference string) (map[string]int, error) {
+	antes := make(map[string]int)
+	if !room.HasAntes() {
+		return antes, nil
+	}
+
+	dealtIn := make([]*models.Player, 0, len(ordered))
+	for _, player := range ordered {
+		if player.CanBeDealtIn() {
+			dealtIn = append(dealtIn, player)
+		}
+	}
+	if len(dealtIn) == 0 {
+		return antes, nil
+	}
+
+	var posters []*models.Player
+	switch room.AnteType {
+	case models.AntePerPlayer:
+		posters = dealtIn
+	case models.AnteButton:
+		// Игроки идут по часовой стрелке от дилера, поэтому последний в списке - дилер
+		posters = []*models.Player{dealtIn[len(dealtIn)-1]}
+	case models.AnteBigBlind:
+		if bbPlayer != nil {
+			posters = []*models.Player{bbPlayer}
+		}
+	}
+
+	for _, player := range posters {
+		amount, err := bp.postForcedBet(clubID, roomID, player, room.Ante, false, reference)
+		if err != nil {
+			return nil, err
+		}
+		if amount == 0 {
+			continue
+		}
+		if room.AnteType.IsTableAnte() {
+			player.TableAnte += amount
+		}
+		antes[player.UserID] = amount
+	}
+
+	if len(antes) > 0 {
+		bp.actionLogger.LogAntesPosted(clubID, roomID, string(room.AnteType), antes)
+	}
+
+	return antes, nil
+}
+
+// sumAntes - сумма поставленных анте
+func sumAntes(antes map[string]int) int {
+	total := 0
+	for _, amount := range antes {
+		total += amount
+	}
+	return total
+}
+
+// playersWithoutBlinds - игроки, получающие карты в раздаче без блайндов
+// Пропущенных блайндов в такой раздаче нет, поэтому вернувшимся игрокам
+// не нужно ни ждать большого блайнда, ни оплачивать пропущенные блайнды
+func playersWithoutBlinds(ordered []*models.Player) ([]*models.Player, error) {
+	eligible := make([]*models.Player, 0, len(ordered))
+	for _, player := range ordered {
+		if player.IsSittingOut() {
+			continue
+		}
+		player.WaitForBigBlind = false
+		player.PostMissedBlinds = false
+		player.MissedSmallBlind = false
+		player.MissedBigBlind = false
+		eligible = append(eligible, player)
+	}
+	if len(eligible) < 2 {
+		return nil, ErrNotEnoughPlayersForBlinds
+	}
+	return eligible, nil
+}
+
+// loadPlayersFromDealer - загружает игроков с фишками по часовой стрелке от дилера
+// Дилер (если у него есть фишки) оказывается последним в списке
+func (bp *BlindPoster) loadPlayersFromDealer(clubID, roomID string, dealerPosition int) ([]*models.Player, error) {
+	playerIDs, err := bp.gameStateService.GetPlayerIDsFromSeat(clubID, roomID, dealerPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]*models.Player, 0, len(playerIDs))
+	for _, userID := range playerIDs {
+		player, err := bp.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil || !player.HasChips() {
+			continue
+		}
+		players = append(players, player)
+	}
+
+	return players, nil
+}
+
+// assignBlinds - определяет игроков на малом и большом блайндах
+// Игрок вне игры на месте малого блайнда пропускает его (малый блайнд не ставится),
+// игроки вне игры, мимо которых проходит большой блайнд, пропускают большой блайнд.
+// Игрок, ждущий большого блайнда, может занять только место большого блайнда.
+// Возвращает также список игроков без тех, кто был поднят из-за стола за пропуск кругов
+func (bp *BlindPoster) assignBlinds(clubID, roomID string, ordered []*models.Player) (*models.Player, *models.Player, []*models.Player, error) {
+	eligible := make([]*models.Player, 0, len(ordered))
+	for _, player := range ordered {
+		if !player.IsSittingOut() {
+			eligible = append(eligible, player)
+		}
+	}
+	if len(eligible) < 2 {
+		return nil, nil, nil, ErrNotEnoughPlayersForBlinds
+	}
+
+	// Хедз-ап: дилер ставит малый блайнд, соперник - большой.
+	// Ждать большого блайнда вдвоем не имеет смысла - оба игрока в раздаче
+	if len(eligible) == 2 {
+		eligible[0].WaitForBigBlind = false
+		eligible[1].WaitForBigBlind = false
+
+		remaining, err := bp.recordMissedBlinds(clubID, roomID, ordered, eligible[0])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return eligible[1], eligible[0], remaining, nil
+	}
+
+	var sbPlayer, bbPlayer *models.Player
+	for i, player := range ordered {
+		if player.IsSittingOut() {
+			continue
+		}
+		if i == 0 && player.CanBeDealtIn() {
+			sbPlayer = player
+		} else if i > 0 {
+			bbPlayer = player
+			break
+		}
+	}
+
+	if bbPlayer == nil {
+		return nil, nil, nil, ErrNotEnoughPlayersForBlinds
+	}
+
+	remaining, err := bp.recordMissedBlinds(clubID, roomID, ordered, bbPlayer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return sbPlayer, bbPlayer, remaining, nil
+}
+
+// recordMissedBlinds - отмечает блайнды, пропущенные игроками вне игры до большого блайнда
+// Игрок вне игры на первом месте пропускает малый блайнд, дальше до большого блайнда - большой.
+// Отметка ведется и в хедз-апе, иначе вернувшийся игрок не оплатил бы пропущенные блайнды.
+// Возвращает игроков без тех, кто был поднят из-за стола за пропуск кругов
+func (bp *BlindPoster) recordMissedBlinds(clubID, roomID string, ordered []*models.Player, bbPlayer *models.Player) ([]*models.Player, error) {
+	remaining := make([]*models.Player, 0, len(ordered))
+	passed := false
+
+	for i, player := range ordered {
+		if player == bbPlayer {
+			passed = true
+		}
+		if passed || !player.IsSittingOut() {
+			remaining = append(remaining, player)
+			continue
+		}
+
+		stoodUp, err := bp.sitOutManager.RecordMissedBlind(clubID, roomID, player, i > 0)
+		if err != nil {
+			return nil, err
+		}
+		if !stoodUp {
+			remaining = append(remaining, player)
+		}
+	}
+
+	return remaining, nil
+}
+
+// postMissedBlinds - списывает с вернувшегося игрока оплату пропущенных блайндов
+// Возвращает поставленную сумму
+func (bp *BlindPoster) postMissedBlinds(clubID, roomID string, player *models.Player, room *models.Room, reference string) (int, error) {
+	posted := 0
+
+	if player.MissedBigBlind {
+		amount, err := bp.postForcedBet(clubID, roomID, player, room.BigBlind, true, reference)
+		if err != nil {
+			return 0, err
+		}
+		posted += amount
+	}
+
+	if player.MissedSmallBlind {
+		amount, err := bp.postForcedBet(clubID, roomID, player, room.SmallBlind, false, reference)
+		if err != nil {
+			return 0, err
+		}
+		posted += amount
+	}
+
+	player.PostMissedBlinds = false
+	player.MissedSmallBlind = false
+	player.MissedBigBlind = false
+
+	return posted, nil
+}
+
+// postForcedBet - списывает обязательную ставку со стека игрока через журнал
+// live: ставка засчитывается в текущую ставку игрока; мертвый блайнд идет только в банк
+// Возвращает фактически поставленную сумму (не больше стека игрока)
+func (bp *BlindPoster) postForcedBet(clubID, roomID string, player *models.Player, amount int, live bool, reference string) (int, error) {
+	if amount > player.Chips {
+		amount = player.Chips
+	}
+	if amount <= 0 {
+		return 0, nil
+	}
+
+	if err := bp.ledger.RecordBlind(clubID, roomID, player.UserID, amount, reference); err != nil {
+		return 0, fmt.Errorf("не удалось списать блайнд игрока %s: %w", player.UserID, err)
+	}
+
+	player.Chips -= amount
+	player.TotalBet += amount
+	if live {
+		player.Bet += amount
+	}
+
+	return amount, nil
+}
+
+// recordForcedBets - записывает живые обязательные ставки (блайнды, стрэддлы, bring-in)
+// в журнал действий раздачи для статистики игроков. Мертвые ставки (анте, мертвый малый блайнд)
+// не меняют ставку улицы и не записываются
+func (bp *BlindPoster) recordForcedBets(clubID, roomID, gameID string, phase models.GamePhase, players []*models.Player) {
+	for _, player := range players {
+		if player.Bet <= 0 {
+			continue
+		}
+
+		action := &models.HandAction{
+			GameID: gameID,
+			UserID: player.UserID,
+			Phase:  phase,
+			Action: models.ActionBlind,
+			Amount: player.Bet,
+		}
+		if err := bp.playerStats.RecordAction(clubID, roomID, action); err != nil {
+			bp.logger.Warningf("Не удалось записать блайнд игрока %s для статистики: %v", player.UserID, err)
+		}
+	}
+}
+
+// saveHandState - сохраняет ставки, статусы и позиционные флаги игроков и состояние игры
+// Стек игрока уже изменен журналом, поэтому поле chips здесь не перезаписывается
+// bbPlayer == nil - раздача без блайндов (анте дилера, стад, бомб-пот).
+// Последний стрэддл становится текущей ставкой и минимальным шагом повышения
+func (bp *BlindPoster) saveHandState(clubID, roomID string, players []*models.Player, dealerPosition int, sbPlayer, bbPlayer *models.Player, currentBet, pot int, straddles []models.StraddlePost) error {
+	keys := bp.redis.GetKeys()
+	ctx := bp.redis.GetContext()
+	pipe := bp.redis.TxPipeline()
+
+	for _, player := range players {
+		status := player.Status
+		if player.CanBeDealtIn() {
+			status = models.PlayerStatusActive
+			if !player.HasChips() {
+				status = models.PlayerStatusAllIn
+			}
+		}
+
+		pipe.HSet(ctx, keys.PlayerInfo(clubID, roomID, player.UserID), map[string]interface{}{
+			"status":             string(status),
+			"bet":                player.Bet,
+			"total_bet":          player.TotalBet,
+			"table_ante":         player.TableAnte,
+			"is_dealer":          player.Position == dealerPosition,
+			"is_small_blind":     player == sbPlayer,
+			"is_big_blind":       player == bbPlayer,
+			"missed_small_blind": player.MissedSmallBlind,
+			"missed_big_blind":   player.MissedBigBlind,
+			"wait_for_big_blind": player.WaitForBigBlind,
+			"post_missed_blinds": player.PostMissedBlinds,
+			"wants_straddle":     player.WantsStraddle,
+			"straddle":           player.Straddle,
+		})
+	}
+
+	gameUpdates := map[string]interface{}{
+		"current_bet": currentBet,
+		"last_raise":  currentBet,
+		"raise_count": 0,
+		"pot":         pot,
+	}
+	if bbPlayer != nil {
+		gameUpdates["big_blind_position"] = bbPlayer.Position
+		gameUpdates["raise_count"] = 1 // Большой блайнд считается первой ставкой раунда
+	} else {
+		pipe.HDel(ctx, keys.GameState(clubID, roomID), "big_blind_position")
+	}
+	if len(straddles) > 0 {
+		last := straddles[len(straddles)-1]
+		gameUpdates["current_bet"] = last.Amount
+		gameUpdates["last_raise"] = last.Amount
+		gameUpdates["raise_count"] = 1 + len(straddles)
+		gameUpdates["straddle_position"] = last.Seat
+	} else {
+		pipe.HDel(ctx, keys.GameState(clubID, roomID), "straddle_position")
+	}
+	if sbPlayer != nil {
+		gameUpdates["small_blind_position"] = sbPlayer.Position
+	} else {
+		pipe.HDel(ctx, keys.GameState(clubID, roomID), "small_blind_position")
+	}
+	pipe.HSet(ctx, keys.GameState(clubID, roomID), gameUpdates)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		bp.logger.Errorf("Ошибка при сохранении блайндов в комнате %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("ошибка сохранения блайндов: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	ErrNotEnoughPlayersForBlinds = &BlindError{message: "not enough players to post blinds"}
+	ErrStraddleNotAllowed        = &BlindError{message: "straddles are not allowed in this room"}
+)
+
+type BlindError struct {
+	message string
+}
+
+func (e *BlindError) Error() string {
+	return "blind error: " + e.message
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// Кто назначил бомб-пот
+const (
+	BombPotReasonSchedule = "schedule" // Раздача выпала на бомб-пот по расписанию комнаты
+	BombPotReasonOwner    = "owner"    // Бомб-пот назначил владелец комнаты
+	BombPotReasonVote     = "vote"     // Бомб-пот назначен голосованием большинства игроков
+)
+
+// BombPotService - сервис бомб-потов
+// В бомб-поте каждый игрок ставит одинаковое анте, торговля на префлопе пропускается
+// и раздача начинается с флопа (по желанию комнаты - на двух досках, которые делят банк).
+// Бомб-пот выпадает по расписанию (каждые N раздач), назначается владельцем комнаты
+// или голосованием большинства сидящих за столом игроков
+type BombPotService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewBombPotService - создает новый экземпляр BombPotService
+func NewBombPotService(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	actionLogger *ActionLogger,
+) *BombPotService {
+	return &BombPotService{
+		redis:            redis,
+		gameStateService: gameStateService,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("BombPot"),
+	}
+}
+
+// Trigger - владелец комнаты назначает бомб-пот на следующую раздачу
+func (bs *BombPotService) Trigger(clubID, roomID, userID string) error {
+	room, err := bs.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil {
+		return err
+	}
+	if room == nil {
+		return ErrRoomNotFound
+	}
+	if room.GameType.IsStud() {
+		return ErrBombPotNotAllowed
+	}
+	if !room.IsOwner(userID) {
+		return ErrNotRoomOwner
+	}
+
+	return bs.schedule(clubID, roomID, BombPotReasonOwner, userID)
+}
+
+// Vote - игрок голосует за бомб-пот в следующей раздаче
+// Когда голосов набирается больше половины сидящих за столом, бомб-пот назначается.
+// Возвращает количество голосов и сколько голосов нужно
+func (bs *BombPotService) Vote(clubID, roomID, userID string) (int, int, error) {
+	room, err := bs.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if room == nil {
+		return 0, 0, ErrRoomNotFound
+	}
+	if !room.BombPotVoting || room.GameType.IsStud() {
+		return 0, 0, ErrBombPotNotAllowed
+	}
+
+	player, err := bs.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if player == nil {
+		return 0, 0, ErrNotSeatedForVote
+	}
+
+	seated, err := bs.gameStateService.GetPlayersCount(clubID, roomID)
+	if err != nil {
+		return 0, 0, err
+	}
+	needed := int(seated)/2 + 1
+
+	votesKey := bs.redis.GetKeys().RoomBombPotVotes(clubID, roomID)
+	if err := bs.redis.SAdd(votesKey, userID); err != nil {
+		return 0, 0, fmt.Errorf("ошибка сохранения голоса за бомб-пот: %w", err)
+	}
+
+	votes, err := bs.redis.SCard(votesKey)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bs.actionLogger.LogBombPotVote(clubID, roomID, userID, int(votes), needed)
+	bs.logger.Infof("Игрок %s голосует за бомб-пот в комнате %s:%s (%d из %d)", userID, clubID, roomID, votes, needed)
+
+	if int(votes) >= needed {
+		if err := bs.schedule(clubID, roomID, BombPotReasonVote, ""); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return int(votes), needed, nil
+}
+
+// StartHand - определяет, является ли новая раздача бомб-потом
+// Вызывается в начале раздачи до постановки блайндов (BlindPoster.PostBlinds ставит
+// анте бомб-пота вместо блайндов). Для бомб-пота на двух досках доска прогоняется дважды
+func (bs *BombPotService) StartHand(clubID, roomID string) (bool, error) {
+	room, err := bs.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return false, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	game, err := bs.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return false, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	// В стаде нет флопа - бомб-пот невозможен
+	isBombPot := !room.GameType.IsStud() && (game.BombPotPending || room.IsBombPotHand(game.RoundNumber))
+
+	updates := map[string]interface{}{
+		"bomb_pot":         isBombPot,
+		"bomb_pot_pending": false,
+	}
+	if isBombPot && room.BombPotDoubleBoard {
+		updates["run_count"] = 2
+	}
+
+	if err := bs.redis.HMSet(bs.redis.GetKeys().GameState(clubID, roomID), updates); err != nil {
+		bs.logger.Errorf("Ошибка при сохранении бомб-пота в комнате %s:%s: %v", clubID, roomID, err)
+		return false, fmt.Errorf("ошибка сохранения бомб-пота: %w", err)
+	}
+
+	if isBombPot {
+		if !game.BombPotPending {
+			bs.actionLogger.LogBombPotScheduled(clubID, roomID, BombPotReasonSchedule, "")
+		}
+		bs.logger.Infof("Раздача #%d в комнате %s:%s - бомб-пот (две доски: %v)",
+			game.RoundNumber, clubID, roomID, room.BombPotDoubleBoard)
+	}
+
+	return isBombPot, nil
+}
+
+// schedule - назначает бомб-пот на следующую раздачу и сбрасывает голоса
+func (bs *BombPotService) schedule(clubID, roomID, reason, userID string) error {
+	keys := bs.redis.GetKeys()
+	ctx := bs.redis.GetContext()
+
+	pipe := bs.redis.TxPipeline()
+	pipe.HSet(ctx, keys.GameState(clubID, roomID), "bomb_pot_pending", true)
+	pipe.Del(ctx, keys.RoomBombPotVotes(clubID, roomID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		bs.logger.Errorf("Ошибка при назначении бомб-пота в комнате %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("ошибка назначения бомб-пота: %w", err)
+	}
+
+	bs.actionLogger.LogBombPotScheduled(clubID, roomID, reason, userID)
+	bs.logger.Infof("В комнате %s:%s назначен бомб-пот на следующую раздачу (%s)", clubID, roomID, reason)
+
+	return nil
+}
+
+var (
+	ErrBombPotNotAllowed = &BombPotError{message: "bomb pots are not allowed in this room"}
+	ErrNotRoomOwner      = &BombPotError{message: "only the room owner can trigger a bomb pot"}
+	ErrNotSeatedForVote  = &BombPotError{message: "only seated players can vote for a bomb pot"}
+)
+
+type BombPotError struct {
+	message string
+}
+
+func (e *BombPotError) Error() string {
+	return "bomb pot error: " + e.message
+}
@@ -0,0 +1,680 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// topUpAttempts - сколько раз повторяется докупка, если запись игрока изменилась во время проверки лимита
+const topUpAttempts = 3
+
+// Операции покупки фишек
+const (
+	BuyInOperationSitDown = "sit_down" // Посадка за стол с buy-in
+	BuyInOperationRebuy   = "rebuy"    // Повторная покупка после проигрыша всех фишек
+	BuyInOperationTopUp   = "top_up"   // Докупка фишек до лимита между раздачами
+	BuyInOperationStandUp = "stand_up" // Вывод стека при подъеме из-за стола
+
+	BuyInOperationTournament = "tournament" // Выдача и возврат турнирных фишек
+	BuyInOperationAddOn      = "add_on"     // Турнирный аддон
+)
+
+// BuyInManager - сервис для посадки за стол, ребаев и докупок фишек
+// Проверяет лимиты комнаты (BuyInMin/BuyInMax) и текущий стек игрока.
+// Фишки, купленные во время раздачи, откладываются и зачисляются после ее окончания
+type BuyInManager struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// ledger - журнал движения фишек
+	ledger *ChipLedger
+
+	// seatManager - сервис управления местами
+	seatManager *SeatManager
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewBuyInManager - создает новый экземпляр BuyInManager
+func NewBuyInManager(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	ledger *ChipLedger,
+	seatManager *SeatManager,
+	actionLogger *ActionLogger,
+) *BuyInManager {
+	return &BuyInManager{
+		redis:            redis,
+		gameStateService: gameStateService,
+		ledger:           ledger,
+		seatManager:      seatManager,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("BuyInManager"),
+	}
+}
+
+// SitDown - сажает игрока за стол с указанным buy-in
+// Параметры:
+//   - position: номер места за столом (-1 - выбрать свободное место автоматически)
+//   - amount: сумма buy-in (должна быть в пределах BuyInMin..BuyInMax комнаты)
+func (bm *BuyInManager) SitDown(clubID, roomID, userID, username string, position int, amount int) error {
+	room, err := bm.validateSitDown(clubID, roomID, userID, amount)
+	if err != nil {
+		return err
+	}
+
+	if !room.CanAcceptPlayers() {
+		return ErrRoomNotAccepting
+	}
+
+	// Резервируем место (атомарно, защищает от одновременной посадки на одно место)
+	if position < 0 {
+		position, err = bm.seatManager.AutoSelectSeat(clubID, roomID, room.MaxPlayers)
+	} else {
+		err = bm.seatManager.ReserveSeat(clubID, roomID, position, room.MaxPlayers)
+	}
+	if err != nil {
+		return err
+	}
+
+	return bm.seatPlayer(clubID, roomID, userID, username, position, amount, true)
+}
+
+// SitDownReserved - сажает игрока на место, заранее зарезервированное для него
+// (например, место, предложенное из листа ожидания)
+// Если посадка не удалась, резерв места сохраняется
+func (bm *BuyInManager) SitDownReserved(clubID, roomID, userID, username string, seat int, amount int) error {
+	if _, err := bm.validateSitDown(clubID, roomID, userID, amount); err != nil {
+		return err
+	}
+
+	return bm.seatPlayer(clubID, roomID, userID, username, seat, amount, false)
+}
+
+// SitDownTournament - сажает участника турнира за стол с указанным стеком
+// (стартовым при регистрации или текущим при пересадке с другого стола)
+// Фишки выдаются со счета турнирных фишек, а не из кассы клуба
+// Параметры:
+//   - position: номер места за столом (-1 - выбрать свободное место автоматически)
+func (bm *BuyInManager) SitDownTournament(clubID, roomID, userID, username, tournamentID string, position int, stack int) (int, error) {
+	room, err := bm.getRoom(clubID, roomID)
+	if err != nil {
+		return 0, err
+	}
+	if room.TournamentID != tournamentID {
+		return 0, ErrNotTournamentRoom
+	}
+
+	isSeated, err := bm.gameStateService.IsPlayerInRoom(clubID, roomID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if isSeated {
+		return 0, ErrAlreadySeated
+	}
+
+	if position < 0 {
+		position, err = bm.seatManager.AutoSelectSeat(clubID, roomID, room.MaxPlayers)
+	} else {
+		err = bm.seatManager.ReserveSeat(clubID, roomID, position, room.MaxPlayers)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := bm.placePlayer(clubID, roomID, userID, username, position, stack, true); err != nil {
+		return 0, err
+	}
+
+	reference := buyInReference(BuyInOperationTournament, userID)
+	if err := bm.ledger.RecordTournamentChips(clubID, roomID, tournamentID, userID, stack, reference); err != nil {
+		bm.unplacePlayer(clubID, roomID, userID, position, true)
+		return 0, err
+	}
+
+	bm.actionLogger.LogPlayerSatDown(clubID, roomID, userID, position, stack, BuyInOperationTournament, false)
+	bm.logger.Infof("Участник турнира %s сел за стол %s:%s (место %d, стек %d)", userID, clubID, roomID, position, stack)
+
+	return position, nil
+}
+
+// validateSitDown - проверяет комнату, сумму buy-in и что игрок еще не сидит за столом
+func (bm *BuyInManager) validateSitDown(clubID, roomID, userID string, amount int) (*models.Room, error) {
+	room, err := bm.getRoom(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsTournament() {
+		return nil, ErrTournamentRoom
+	}
+
+	if err := validateBuyInAmount(room, amount); err != nil {
+		return nil, err
+	}
+
+	isSeated, err := bm.gameStateService.IsPlayerInRoom(clubID, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isSeated {
+		return nil, ErrAlreadySeated
+	}
+
+	return room, nil
+}
+
+// seatPlayer - создает игрока на зарезервированном месте и зачисляет buy-in через журнал
+// cancelReservation: снять резерв места, если игрока не удалось создать
+func (bm *BuyInManager) seatPlayer(clubID, roomID, userID, username string, position int, amount int, cancelReservation bool) error {
+	if err := bm.placePlayer(clubID, roomID, userID, username, position, amount, cancelReservation); err != nil {
+		return err
+	}
+
+	if err := bm.ledger.RecordBuyIn(clubID, roomID, userID, amount, buyInReference(BuyInOperationSitDown, userID)); err != nil {
+		bm.unplacePlayer(clubID, roomID, userID, position, cancelReservation)
+		return err
+	}
+
+	bm.actionLogger.LogPlayerSatDown(clubID, roomID, userID, position, amount, BuyInOperationSitDown, false)
+	bm.logger.Infof("Игрок %s сел за стол %s:%s (место %d, buy-in %d)", userID, clubID, roomID, position, amount)
+
+	return nil
+}
+
+// placePlayer - создает игрока с пустым стеком на зарезервированном месте
+// Фишки зачисляются вызывающим через журнал
+func (bm *BuyInManager) placePlayer(clubID, roomID, userID, username string, position int, amount int, cancelReservation bool) error {
+	// Создаем игрока с пустым стеком - фишки зачисляются через журнал
+	player := &models.Player{
+		UserID:        userID,
+		Username:      username,
+		Position:      position,
+		Status:        models.PlayerStatusWaiting,
+		Cards:         []string{},
+		JoinedTableAt: utils.GetISO8601Time(),
+		InitialBuyIn:  amount,
+	}
+
+	keys := bm.redis.GetKeys()
+	ctx := bm.redis.GetContext()
+
+	pipe := bm.redis.TxPipeline()
+	pipe.HSet(ctx, keys.PlayerInfo(clubID, roomID, userID), player.ToRedisHash())
+	pipe.SAdd(ctx, keys.RoomPlayers(clubID, roomID), userID)
+	pipe.Set(ctx, keys.UserCurrentRoom(clubID, userID), roomID, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		bm.logger.Errorf("Ошибка при посадке игрока %s за стол %s:%s: %v", userID, clubID, roomID, err)
+		if cancelReservation {
+			bm.seatManager.CancelReservation(clubID, roomID, position)
+		}
+		return fmt.Errorf("ошибка посадки за стол: %w", err)
+	}
+
+	if err := bm.seatManager.AssignSeat(clubID, roomID, userID, position); err != nil {
+		bm.unplacePlayer(clubID, roomID, userID, position, cancelReservation)
+		return err
+	}
+
+	return nil
+}
+
+// unplacePlayer - отменяет placePlayer, если посадку не удалось завершить
+// Игрок удаляется из-за стола, место освобождается (или остается зарезервированным за ним,
+// если резерв сделан заранее, например по предложению из листа ожидания)
+func (bm *BuyInManager) unplacePlayer(clubID, roomID, userID string, position int, cancelReservation bool) {
+	keys := bm.redis.GetKeys()
+	ctx := bm.redis.GetContext()
+
+	pipe := bm.redis.TxPipeline()
+	if cancelReservation {
+		pipe.SRem(ctx, keys.RoomOccupiedSeats(clubID, roomID), strconv.Itoa(position))
+	}
+	pipe.ZRem(ctx, keys.RoomTurnOrder(clubID, roomID), userID)
+	pipe.Del(ctx, keys.PlayerInfo(clubID, roomID, userID))
+	pipe.SRem(ctx, keys.RoomPlayers(clubID, roomID), userID)
+	pipe.Del(ctx, keys.UserCurrentRoom(clubID, userID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		bm.logger.Errorf("Ошибка при отмене посадки игрока %s за стол %s:%s: %v", userID, clubID, roomID, err)
+	}
+}
+
+// Rebuy - повторная покупка фишек игроком, проигравшим весь стек
+// Сумма должна быть в пределах BuyInMin..BuyInMax комнаты
+// Игрок в олл-ине еще участвует в раздаче и может выиграть банк, поэтому ребай ему недоступен
+func (bm *BuyInManager) Rebuy(clubID, roomID, userID string, amount int) error {
+	room, player, err := bm.getRoomAndPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	if player.HasChips() || player.HasPendingChips() {
+		return ErrNotBusted
+	}
+
+	isActive, err := bm.gameStateService.IsGameActive(clubID, roomID)
+	if err != nil {
+		return err
+	}
+	if isActive && (player.IsActive() || player.IsAllIn()) {
+		return ErrPlayerInHand
+	}
+
+	if err := validateBuyInAmount(room, amount); err != nil {
+		return err
+	}
+
+	return bm.addChips(clubID, roomID, "", player, amount, BuyInOperationRebuy)
+}
+
+// TopUp - докупка фишек игроком, у которого еще есть стек
+// Стек после докупки (включая отложенные фишки) не может превышать BuyInMax.
+// Проверка лимита и зачисление выполняются в одной транзакции под WATCH на запись игрока
+// и состояние игры: одновременные докупки не могут вместе превысить лимит
+func (bm *BuyInManager) TopUp(clubID, roomID, userID string, amount int) error {
+	room, _, err := bm.getRoomAndPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	if amount <= 0 {
+		return ErrBuyInOutOfRange
+	}
+
+	keys := bm.redis.GetKeys()
+	ctx := bm.redis.GetContext()
+	playerKey := keys.PlayerInfo(clubID, roomID, userID)
+	gameKey := keys.GameState(clubID, roomID)
+
+	var player *models.Player
+	var deferred bool
+
+	topUp := func(tx *redis.Tx) error {
+		data, err := tx.HGetAll(ctx, playerKey).Result()
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return ErrPlayerNotSeated
+		}
+		player, err = models.NewPlayerFromRedis(data)
+		if err != nil {
+			return err
+		}
+
+		if !player.HasChips() && !player.HasPendingChips() {
+			// Игрок без фишек должен делать rebuy, а не top-up
+			return ErrUseRebuy
+		}
+
+		// Учитываем фишки, уже поставленные в текущей раздаче
+		stackAfterTopUp := player.GetTotalChips() + player.PendingChips + amount
+		if room.BuyInMax > 0 && stackAfterTopUp > room.BuyInMax {
+			return ErrTopUpExceedsMax
+		}
+
+		gameData, err := tx.HGetAll(ctx, gameKey).Result()
+		if err != nil {
+			return err
+		}
+		deferred = false
+		if len(gameData) > 0 {
+			game, err := models.NewGameFromRedis(gameData)
+			if err != nil {
+				return err
+			}
+			deferred = game.IsActive()
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return bm.appendChips(pipe, clubID, roomID, "", userID, amount, BuyInOperationTopUp, deferred)
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < topUpAttempts; attempt++ {
+		err = bm.redis.GetClient().Watch(ctx, topUp, playerKey, gameKey)
+		if err != redis.TxFailedErr {
+			break
+		}
+	}
+	if err == redis.TxFailedErr {
+		bm.logger.Warningf("Докупка игрока %s в комнате %s:%s не выполнена: запись игрока менялась во время докупки", userID, clubID, roomID)
+		return ErrConcurrentTopUp
+	}
+	if err != nil {
+		if _, ok := err.(*BuyInError); !ok {
+			bm.logger.Errorf("Ошибка при докупке фишек игроком %s в комнате %s:%s: %v", userID, clubID, roomID, err)
+		}
+		return err
+	}
+
+	bm.logChipsAdded(clubID, roomID, player, amount, BuyInOperationTopUp, deferred)
+	return nil
+}
+
+// StandUp - поднимает игрока из-за стола: выводит его стек в кассу и освобождает место
+// Игрок, участвующий в текущей раздаче или уже внесший фишки в ее банк (в том числе сбросивший карты),
+// встать не может: его запись нужна для расчета банков до конца раздачи
+// Отложенные докупки не зачислялись в стек и в журнал, поэтому просто отменяются
+func (bm *BuyInManager) StandUp(clubID, roomID, userID string) error {
+	player, err := bm.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if player == nil {
+		return ErrPlayerNotSeated
+	}
+
+	isActive, err := bm.gameStateService.IsGameActive(clubID, roomID)
+	if err != nil {
+		return err
+	}
+	if isActive && player.HasStakeInHand() {
+		return ErrPlayerInHand
+	}
+
+	if player.HasChips() {
+		// Турнирные фишки нельзя вывести в кассу
+		room, err := bm.getRoom(clubID, roomID)
+		if err != nil {
+			return err
+		}
+		if room.IsTournament() {
+			return ErrTournamentRoom
+		}
+
+		reference := buyInReference(BuyInOperationStandUp, userID)
+		if err := bm.ledger.RecordCashOut(clubID, roomID, userID, player.Chips, reference); err != nil {
+			return err
+		}
+	}
+
+	return bm.removePlayer(clubID, roomID, player)
+}
+
+// AddTournamentChips - выдает участнику турнира фишки ребая или аддона со счета турнирных фишек
+// Во время раздачи фишки откладываются и зачисляются после ее окончания
+func (bm *BuyInManager) AddTournamentChips(clubID, roomID, userID, tournamentID string, amount int, operation string) error {
+	room, err := bm.getRoom(clubID, roomID)
+	if err != nil {
+		return err
+	}
+	if room.TournamentID != tournamentID {
+		return ErrNotTournamentRoom
+	}
+
+	player, err := bm.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if player == nil {
+		return ErrPlayerNotSeated
+	}
+
+	return bm.addChips(clubID, roomID, tournamentID, player, amount, operation)
+}
+
+// StandUpTournament - поднимает участника турнира из-за стола
+// Оставшиеся фишки возвращаются на счет турнирных фишек
+func (bm *BuyInManager) StandUpTournament(clubID, roomID, userID, tournamentID string) error {
+	player, err := bm.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if player == nil {
+		return ErrPlayerNotSeated
+	}
+
+	if player.HasChips() {
+		reference := buyInReference(BuyInOperationTournament, userID)
+		if err := bm.ledger.RecordTournamentChips(clubID, roomID, tournamentID, userID, -player.Chips, reference); err != nil {
+			return err
+		}
+	}
+
+	return bm.removePlayer(clubID, roomID, player)
+}
+
+// removePlayer - освобождает место игрока и удаляет его из комнаты
+func (bm *BuyInManager) removePlayer(clubID, roomID string, player *models.Player) error {
+	userID := player.UserID
+
+	if err := bm.seatManager.ReleaseSeat(clubID, roomID, userID, player.Position); err != nil {
+		return err
+	}
+
+	keys := bm.redis.GetKeys()
+	ctx := bm.redis.GetContext()
+
+	pipe := bm.redis.TxPipeline()
+	pipe.Del(ctx, keys.PlayerInfo(clubID, roomID, userID))
+	pipe.Del(ctx, keys.PlayerPendingChips(clubID, roomID, userID))
+	pipe.SRem(ctx, keys.RoomPlayers(clubID, roomID), userID)
+	pipe.Del(ctx, keys.UserCurrentRoom(clubID, userID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		bm.logger.Errorf("Ошибка при удалении игрока %s из-за стола %s:%s: %v", userID, clubID, roomID, err)
+		return fmt.Errorf("ошибка подъема из-за стола: %w", err)
+	}
+
+	bm.actionLogger.LogPlayerStoodUp(clubID, roomID, userID, player.Position, player.Chips)
+	bm.logger.Infof("Игрок %s встал из-за стола %s:%s (место %d, фишек: %d)",
+		userID, clubID, roomID, player.Position, player.Chips)
+
+	return nil
+}
+
+// ApplyPendingChips - зачисляет отложенные фишки всем игрокам комнаты
+// Вызывается после окончания раздачи. Каждая операция покупки зачисляется отдельной записью
+// журнала, а отложенные фишки уменьшаются на зачисленную сумму в той же транзакции: докупка,
+// пришедшая во время зачисления, остается отложенной, а сбой не приводит к повторному зачислению
+func (bm *BuyInManager) ApplyPendingChips(clubID, roomID string) error {
+	room, err := bm.getRoom(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	playerIDs, err := bm.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range playerIDs {
+		player, err := bm.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return err
+		}
+		if player == nil || !player.HasPendingChips() {
+			continue
+		}
+
+		operations, err := bm.redis.HGetAll(bm.redis.GetKeys().PlayerPendingChips(clubID, roomID, userID))
+		if err != nil {
+			return err
+		}
+
+		// Фишки, отложенные без указания операции, зачисляются как докупка
+		untracked := player.PendingChips
+		for operation, value := range operations {
+			amount, _ := strconv.Atoi(value)
+			if amount <= 0 {
+				continue
+			}
+			untracked -= amount
+			if err := bm.creditPendingChips(clubID, roomID, room.TournamentID, userID, amount, operation); err != nil {
+				return err
+			}
+		}
+		if untracked > 0 {
+			if err := bm.creditPendingChips(clubID, roomID, room.TournamentID, userID, untracked, BuyInOperationTopUp); err != nil {
+				return err
+			}
+		}
+
+		bm.logger.Infof("Игроку %s зачислено %d отложенных фишек", userID, player.PendingChips)
+	}
+
+	return nil
+}
+
+// creditPendingChips - зачисляет отложенные фишки одной операции покупки и списывает их из отложенных
+func (bm *BuyInManager) creditPendingChips(clubID, roomID, tournamentID, userID string, amount int, operation string) error {
+	keys := bm.redis.GetKeys()
+	ctx := bm.redis.GetContext()
+
+	counterAccount, reason := chipsSource(tournamentID)
+
+	pipe := bm.redis.TxPipeline()
+	if err := bm.ledger.AppendPlayerTransfer(pipe, clubID, roomID, userID, amount, counterAccount, reason, buyInReference(operation, userID)); err != nil {
+		return err
+	}
+	pipe.HIncrBy(ctx, keys.PlayerInfo(clubID, roomID, userID), "pending_chips", int64(-amount))
+	pipe.HIncrBy(ctx, keys.PlayerPendingChips(clubID, roomID, userID), operation, int64(-amount))
+	if _, err := pipe.Exec(ctx); err != nil {
+		bm.logger.Errorf("Ошибка при зачислении отложенных фишек игроку %s (%s): %v", userID, operation, err)
+		return fmt.Errorf("ошибка зачисления отложенных фишек: %w", err)
+	}
+
+	return nil
+}
+
+// addChips - добавляет фишки игроку: сразу, если раздача не идет, иначе - откладывает
+// Отложенные фишки запоминаются вместе с операцией покупки, чтобы зачислить их в журнал под ней
+func (bm *BuyInManager) addChips(clubID, roomID, tournamentID string, player *models.Player, amount int, operation string) error {
+	isActive, err := bm.gameStateService.IsGameActive(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	pipe := bm.redis.TxPipeline()
+	if err := bm.appendChips(pipe, clubID, roomID, tournamentID, player.UserID, amount, operation, isActive); err != nil {
+		return err
+	}
+	if _, err := pipe.Exec(bm.redis.GetContext()); err != nil {
+		bm.logger.Errorf("Ошибка при добавлении фишек игроку %s (%s): %v", player.UserID, operation, err)
+		return fmt.Errorf("ошибка добавления фишек игроку: %w", err)
+	}
+
+	bm.logChipsAdded(clubID, roomID, player, amount, operation, isActive)
+	return nil
+}
+
+// appendChips - добавляет в pipeline зачисление фишек игроку через журнал или, если deferred, откладывает их
+func (bm *BuyInManager) appendChips(pipe redis.Pipeliner, clubID, roomID, tournamentID, userID string, amount int, operation string, deferred bool) error {
+	if !deferred {
+		counterAccount, reason := chipsSource(tournamentID)
+		return bm.ledger.AppendPlayerTransfer(pipe, clubID, roomID, userID, amount, counterAccount, reason, buyInReference(operation, userID))
+	}
+
+	keys := bm.redis.GetKeys()
+	ctx := bm.redis.GetContext()
+	pipe.HIncrBy(ctx, keys.PlayerInfo(clubID, roomID, userID), "pending_chips", int64(amount))
+	pipe.HIncrBy(ctx, keys.PlayerPendingChips(clubID, roomID, userID), operation, int64(amount))
+	return nil
+}
+
+// logChipsAdded - записывает добавление фишек игроку в журнал действий и лог
+func (bm *BuyInManager) logChipsAdded(clubID, roomID string, player *models.Player, amount int, operation string, deferred bool) {
+	bm.actionLogger.LogPlayerSatDown(clubID, roomID, player.UserID, player.Position, amount, operation, deferred)
+	if deferred {
+		bm.logger.Infof("Игрок %s: %s на %d отложен до конца раздачи", player.UserID, operation, amount)
+		return
+	}
+	bm.logger.Infof("Игрок %s: %s на %d", player.UserID, operation, amount)
+}
+
+// chipsSource - счет, с которого выдаются фишки, и основание записи журнала
+// Турнирные фишки (tournamentID не пустой) выдаются со счета турнирных фишек, остальные - из кассы
+func chipsSource(tournamentID string) (string, models.LedgerReason) {
+	if tournamentID != "" {
+		return models.TournamentChipsAccount(tournamentID), models.LedgerReasonTournamentChips
+	}
+	return models.LedgerAccountCashier, models.LedgerReasonBuyIn
+}
+
+// getRoom - получает комнату или возвращает ошибку, если ее нет
+func (bm *BuyInManager) getRoom(clubID, roomID string) (*models.Room, error) {
+	room, err := bm.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+	return room, nil
+}
+
+// getRoomAndPlayer - получает комнату и сидящего за столом игрока
+func (bm *BuyInManager) getRoomAndPlayer(clubID, roomID, userID string) (*models.Room, *models.Player, error) {
+	room, err := bm.getRoom(clubID, roomID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if room.IsTournament() {
+		return nil, nil, ErrTournamentRoom
+	}
+
+	player, err := bm.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if player == nil {
+		return nil, nil, ErrPlayerNotSeated
+	}
+
+	return room, player, nil
+}
+
+// validateBuyInAmount - проверяет, что сумма buy-in в пределах лимитов комнаты
+func validateBuyInAmount(room *models.Room, amount int) error {
+	if amount <= 0 {
+		return ErrBuyInOutOfRange
+	}
+	if room.BuyInMin > 0 && amount < room.BuyInMin {
+		return ErrBuyInOutOfRange
+	}
+	if room.BuyInMax > 0 && amount > room.BuyInMax {
+		return ErrBuyInOutOfRange
+	}
+	return nil
+}
+
+// buyInReference - формирует ссылку на операцию покупки фишек для журнала
+func buyInReference(operation, userID string) string {
+	return fmt.Sprintf("%s:%s:%d", operation, userID, utils.GetCurrentTimestampMillis())
+}
+
+var (
+	ErrRoomNotAccepting = &BuyInError{message: "room is not accepting players"}
+	ErrAlreadySeated    = &BuyInError{message: "player is already seated"}
+	ErrPlayerNotSeated  = &BuyInError{message: "player is not seated"}
+	ErrBuyInOutOfRange  = &BuyInError{message: "buy-in amount is outside room limits"}
+	ErrNotBusted        = &BuyInError{message: "rebuy is allowed only when busted"}
+	ErrUseRebuy         = &BuyInError{message: "player has no chips, use rebuy"}
+	ErrTopUpExceedsMax  = &BuyInError{message: "top-up would exceed maximum buy-in"}
+	ErrPlayerInHand     = &BuyInError{message: "player is in the current hand"}
+	ErrConcurrentTopUp  = &BuyInError{message: "player stack changed during top-up, try again"}
+
+	ErrTournamentRoom    = &BuyInError{message: "cash buy-ins are not allowed in a tournament room"}
+	ErrNotTournamentRoom = &BuyInError{message: "room does not belong to this tournament"}
+)
+
+type BuyInError struct {
+	message string
+}
+
+func (e *BuyInError) Error() string {
+	return "buy-in error: " + e.message
+}
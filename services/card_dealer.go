@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"poker-engine/models"
 	"poker-engine/storage"
 	"poker-engine/utils"
 )
@@ -19,6 +20,9 @@ type CardDealer struct {
 	// gameStateService - сервис состояния игры
 	gameStateService *GameStateService
 
+	// allInEV - сервис учета эквити олл-инов (эквити фиксируется перед докладыванием досок)
+	allInEV *AllInEVService
+
 	// logger - логгер для вывода сообщений
 	logger *utils.Logger
 }
@@ -28,27 +32,56 @@ func NewCardDealer(
 	redis *storage.RedisClient,
 	deckManager *DeckManager,
 	gameStateService *GameStateService,
+	allInEV *AllInEVService,
 ) *CardDealer {
 	return &CardDealer{
 		redis:            redis,
 		deckManager:      deckManager,
 		gameStateService: gameStateService,
+		allInEV:          allInEV,
 		logger:           utils.NewLogger("CardDealer"),
 	}
 }
 
 // DealCardsToPlayers - раздает карты всем игрокам в комнате
-// В техасском холдеме каждый игрок получает 2 карты
+// Количество карт зависит от разновидности покера: 2 в холдеме, 4 в омахе,
+// в стаде - две закрытые и одна открытая карта (третья улица)
 func (cd *CardDealer) DealCardsToPlayers(clubID, roomID string) error {
 	cd.logger.Infof("Начинаем раздачу карт в комнате %s:%s", clubID, roomID)
 
-	// Шаг 1: Получаем список всех игроков в комнате
-	playerIDs, err := cd.gameStateService.GetPlayerIDs(clubID, roomID)
+	room, err := cd.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	// Шаг 1: Получаем игроков в порядке раздачи (по часовой стрелке от дилера)
+	game, err := cd.gameStateService.GetGameState(clubID, roomID)
+	if err != nil {
+		return fmt.Errorf("не удалось получить состояние игры: %w", err)
+	}
+	dealerPosition := -1
+	if game != nil {
+		dealerPosition = game.DealerPosition
+	}
+
+	seatedIDs, err := cd.gameStateService.GetPlayerIDsFromSeat(clubID, roomID, dealerPosition)
 	if err != nil {
 		cd.logger.Errorf("Ошибка при получении списка игроков: %v", err)
 		return fmt.Errorf("не удалось получить список игроков: %w", err)
 	}
 
+	// Игроки вне игры и ждущие большого блайнда карт не получают
+	playerIDs := make([]string, 0, len(seatedIDs))
+	for _, userID := range seatedIDs {
+		player, err := cd.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return fmt.Errorf("не удалось получить данные игрока %s: %w", userID, err)
+		}
+		if player != nil && player.CanBeDealtIn() {
+			playerIDs = append(playerIDs, userID)
+		}
+	}
+
 	if len(playerIDs) == 0 {
 		cd.logger.Warning("Нет игроков для раздачи карт")
 		return fmt.Errorf("нет игроков в комнате")
@@ -57,7 +90,11 @@ func (cd *CardDealer) DealCardsToPlayers(clubID, roomID string) error {
 	cd.logger.Infof("Найдено %d игроков для раздачи карт", len(playerIDs))
 
 	// Шаг 2: Создаем и тасуем новую колоду
-	deck := cd.deckManager.CreateAndShuffleDeck()
+	deck := cd.deckManager.CreateAndShuffleDeck(room.GameType)
+	if err := ValidateDeck(deck, room.GameType); err != nil {
+		cd.logger.Errorf("Некорректная колода для комнаты %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("некорректная колода: %w", err)
+	}
 	cd.logger.Debugf("Колода создана и перетасована (%d карт)", len(deck))
 
 	// Шаг 3: Сохраняем колоду в Redis
@@ -66,29 +103,39 @@ func (cd *CardDealer) DealCardsToPlayers(clubID, roomID string) error {
 		return fmt.Errorf("не удалось сохранить колоду: %w", err)
 	}
 
-	// Шаг 4: Раздаем по 2 карты каждому игроку
-	cardsPerPlayer := 2 // В техасском холдеме всегда 2 карты
+	// Шаг 4: Раздаем каждому игроку закрытые карты (и открытую карту в стаде)
+	cardsPerPlayer := room.HoleCardsCount()
+	upCardsPerPlayer := 0
+	if room.GameType.IsStud() {
+		cardsPerPlayer, upCardsPerPlayer = models.StudStreetCards(models.GamePhaseThirdStreet)
+	}
 
 	for _, userID := range playerIDs {
-		// Берем 2 карты из колоды
-		cards, err := cd.deckManager.DrawCards(clubID, roomID, cardsPerPlayer)
+		// Берем карты игрока из колоды
+		cards, err := cd.deckManager.DrawCards(clubID, roomID, cardsPerPlayer+upCardsPerPlayer)
 		if err != nil {
 			cd.logger.Errorf("Ошибка при взятии карт для игрока %s: %v", userID, err)
 			return fmt.Errorf("ошибка при взятии карт: %w", err)
 		}
 
-		if len(cards) != cardsPerPlayer {
+		if len(cards) != cardsPerPlayer+upCardsPerPlayer {
 			cd.logger.Errorf("Недостаточно карт в колоде для игрока %s", userID)
 			return fmt.Errorf("недостаточно карт в колоде")
 		}
 
 		// Сохраняем карты игроку в Redis
-		err = cd.savePlayerCards(clubID, roomID, userID, cards)
+		err = cd.savePlayerCards(clubID, roomID, userID, cards[:cardsPerPlayer])
 		if err != nil {
 			cd.logger.Errorf("Ошибка при сохранении карт игрока %s: %v", userID, err)
 			return fmt.Errorf("не удалось сохранить карты игроку: %w", err)
 		}
 
+		err = cd.savePlayerUpCards(clubID, roomID, userID, cards[cardsPerPlayer:])
+		if err != nil {
+			cd.logger.Errorf("Ошибка при сохранении открытых карт игрока %s: %v", userID, err)
+			return fmt.Errorf("не удалось сохранить карты игроку: %w", err)
+		}
+
 		// Форматируем карты для красивого вывода в лог
 		formattedCards := FormatCards(cards)
 		cd.logger.Debugf("Игрок %s получил карты: %v", userID, formattedCards)
@@ -102,8 +149,18 @@ func (cd *CardDealer) DealCardsToPlayers(clubID, roomID string) error {
 	return nil
 }
 
-// savePlayerCards - сохраняет карты игроку в Redis
+// savePlayerCards - сохраняет закрытые карты игроку в Redis
 func (cd *CardDealer) savePlayerCards(clubID, roomID, userID string, cards []string) error {
+	return cd.savePlayerCardsField(clubID, roomID, userID, "cards", cards)
+}
+
+// savePlayerUpCards - сохраняет открытые карты игроку в Redis (стад)
+func (cd *CardDealer) savePlayerUpCards(clubID, roomID, userID string, cards []string) error {
+	return cd.savePlayerCardsField(clubID, roomID, userID, "up_cards", cards)
+}
+
+// savePlayerCardsField - сохраняет карты в поле hash игрока
+func (cd *CardDealer) savePlayerCardsField(clubID, roomID, userID, field string, cards []string) error {
 	// Получаем ключ для данных игрока
 	playerKey := cd.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
 
@@ -114,7 +171,7 @@ func (cd *CardDealer) savePlayerCards(clubID, roomID, userID string, cards []str
 	}
 
 	// Сохраняем карты в Redis
-	err = cd.redis.HSet(playerKey, "cards", string(cardsJSON))
+	err = cd.redis.HSet(playerKey, field, string(cardsJSON))
 	if err != nil {
 		return fmt.Errorf("ошибка при сохранении карт в Redis: %w", err)
 	}
@@ -124,19 +181,23 @@ func (cd *CardDealer) savePlayerCards(clubID, roomID, userID string, cards []str
 
 // DealCommunityCards - раздает общие карты на стол (флоп, терн, ривер)
 // count: 3 для флопа, 1 для терна, 1 для ривера
+// Если игроки договорились прогнать доску несколько раз, карты раздаются на каждую доску;
+// возвращаются карты первой доски
 func (cd *CardDealer) DealCommunityCards(clubID, roomID string, count int) ([]string, error) {
 	cd.logger.Infof("Раздаем %d общих карт в комнате %s:%s", count, clubID, roomID)
 
-	// Берем карты из колоды
-	cards, err := cd.deckManager.DrawCards(clubID, roomID, count)
+	game, err := cd.gameStateService.GetGameState(clubID, roomID)
 	if err != nil {
-		cd.logger.Errorf("Ошибка при взятии общих карт: %v", err)
-		return nil, fmt.Errorf("не удалось взять карты из колоды: %w", err)
+		return nil, fmt.Errorf("не удалось получить состояние игры: %w", err)
+	}
+	if game != nil && game.GetRunCount() > 1 {
+		return cd.dealToBoards(clubID, roomID, game, count)
 	}
 
-	if len(cards) != count {
-		cd.logger.Errorf("Недостаточно карт в колоде (запрошено %d, получено %d)", count, len(cards))
-		return nil, fmt.Errorf("недостаточно карт в колоде")
+	// Берем карты из колоды
+	cards, err := cd.drawCommunityCards(clubID, roomID, count)
+	if err != nil {
+		return nil, err
 	}
 
 	// Получаем текущие общие карты
@@ -163,6 +224,152 @@ func (cd *CardDealer) DealCommunityCards(clubID, roomID string, count int) ([]st
 	return cards, nil
 }
 
+// DealRemainingBoards - докладывает все доски прогонов до пяти карт
+// Вызывается, когда торговля окончена и все оставшиеся игроки в олл-ине.
+// Возвращает доски всех прогонов (одну доску, если прогон один)
+func (cd *CardDealer) DealRemainingBoards(clubID, roomID string) ([][]string, error) {
+	game, err := cd.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	missing := models.MaxCommunityCards - len(game.CommunityCards)
+	if missing > 0 {
+		// Эквити фиксируется по доске на момент олл-ина, до докладывания карт
+		if _, err := cd.allInEV.Capture(clubID, roomID); err != nil {
+			cd.logger.Warningf("Не удалось зафиксировать эквити олл-ина в комнате %s:%s: %v", clubID, roomID, err)
+		}
+
+		if _, err := cd.DealCommunityCards(clubID, roomID, missing); err != nil {
+			return nil, err
+		}
+
+		game, err = cd.gameStateService.GetGameState(clubID, roomID)
+		if err != nil || game == nil {
+			return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+		}
+	}
+
+	return game.GetBoards(), nil
+}
+
+// dealToBoards - раздает count карт на каждую доску прогонов
+// Общие карты, открытые до договоренности, входят во все доски
+func (cd *CardDealer) dealToBoards(clubID, roomID string, game *models.Game, count int) ([]string, error) {
+	runs := game.GetRunCount()
+
+	boards := game.Boards
+	if len(boards) != runs {
+		boards = make([][]string, runs)
+		for i := range boards {
+			boards[i] = append([]string{}, game.CommunityCards...)
+		}
+	}
+
+	var firstCards []string
+	for i := range boards {
+		cards, err := cd.drawCommunityCards(clubID, roomID, count)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			firstCards = cards
+		}
+		boards[i] = append(boards[i], cards...)
+
+		cd.logger.Infof("Прогон %d: общие карты добавлены: %v (всего на доске: %d)", i+1, FormatCards(cards), len(boards[i]))
+	}
+
+	if err := cd.saveBoards(clubID, roomID, boards); err != nil {
+		cd.logger.Errorf("Ошибка при сохранении досок прогонов: %v", err)
+		return nil, err
+	}
+
+	return firstCards, nil
+}
+
+// drawCommunityCards - берет из колоды count общих карт
+func (cd *CardDealer) drawCommunityCards(clubID, roomID string, count int) ([]string, error) {
+	cards, err := cd.deckManager.DrawCards(clubID, roomID, count)
+	if err != nil {
+		cd.logger.Errorf("Ошибка при взятии общих карт: %v", err)
+		return nil, fmt.Errorf("не удалось взять карты из колоды: %w", err)
+	}
+
+	if len(cards) != count {
+		cd.logger.Errorf("Недостаточно карт в колоде (запрошено %d, получено %d)", count, len(cards))
+		return nil, fmt.Errorf("недостаточно карт в колоде")
+	}
+
+	return cards, nil
+}
+
+// DealStudStreet - раздает карты очередной улицы стада (с четвертой по седьмую)
+// Фаза игры уже должна быть переведена на улицу. Карты получают игроки, оставшиеся в раздаче.
+// Если на седьмой улице карт в колоде не хватает всем игрокам, открывается одна общая
+// карта, которая считается седьмой картой каждого игрока
+func (cd *CardDealer) DealStudStreet(clubID, roomID string) error {
+	game, err := cd.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	down, up := models.StudStreetCards(game.Phase)
+	if down+up == 0 || game.Phase == models.GamePhaseThirdStreet {
+		return fmt.Errorf("фаза %s не является улицей для раздачи карт стада", game.Phase)
+	}
+
+	seatedIDs, err := cd.gameStateService.GetPlayerIDsFromSeat(clubID, roomID, game.DealerPosition)
+	if err != nil {
+		return fmt.Errorf("не удалось получить список игроков: %w", err)
+	}
+
+	players := make([]*models.Player, 0, len(seatedIDs))
+	for _, userID := range seatedIDs {
+		player, err := cd.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return fmt.Errorf("не удалось получить данные игрока %s: %w", userID, err)
+		}
+		if player != nil && player.HasCards() && !player.IsFolded() {
+			players = append(players, player)
+		}
+	}
+
+	remaining, err := cd.deckManager.GetDeckSize(clubID, roomID)
+	if err != nil {
+		return err
+	}
+	if int(remaining) < len(players)*(down+up) {
+		cd.logger.Infof("В комнате %s:%s не хватает карт на улицу %s - открываем общую карту", clubID, roomID, game.Phase)
+		_, err := cd.DealCommunityCards(clubID, roomID, 1)
+		return err
+	}
+
+	for _, player := range players {
+		cards, err := cd.deckManager.DrawCards(clubID, roomID, down+up)
+		if err != nil {
+			return fmt.Errorf("ошибка при взятии карт: %w", err)
+		}
+		if len(cards) != down+up {
+			return fmt.Errorf("недостаточно карт в колоде")
+		}
+
+		if down > 0 {
+			err = cd.savePlayerCards(clubID, roomID, player.UserID, append(player.Cards, cards[:down]...))
+		} else {
+			err = cd.savePlayerUpCards(clubID, roomID, player.UserID, append(player.UpCards, cards[down:]...))
+		}
+		if err != nil {
+			cd.logger.Errorf("Ошибка при сохранении карт игрока %s: %v", player.UserID, err)
+			return fmt.Errorf("не удалось сохранить карты игроку: %w", err)
+		}
+	}
+
+	cd.logger.Infof("Улица %s роздана в комнате %s:%s: %d игрокам", game.Phase, clubID, roomID, len(players))
+
+	return nil
+}
+
 // getCurrentCommunityCards - получает текущие общие карты из Redis
 func (cd *CardDealer) getCurrentCommunityCards(clubID, roomID string) ([]string, error) {
 	gameKey := cd.redis.GetKeys().GameState(clubID, roomID)
@@ -207,6 +414,30 @@ func (cd *CardDealer) saveCommunityCards(clubID, roomID string, cards []string)
 	return nil
 }
 
+// saveBoards - сохраняет доски прогонов в Redis
+// Первая доска сохраняется и как общие карты стола
+func (cd *CardDealer) saveBoards(clubID, roomID string, boards [][]string) error {
+	boardsJSON, err := json.Marshal(boards)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации досок прогонов: %w", err)
+	}
+
+	cardsJSON, err := json.Marshal(boards[0])
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации общих карт: %w", err)
+	}
+
+	err = cd.redis.HMSet(cd.redis.GetKeys().GameState(clubID, roomID), map[string]interface{}{
+		"boards":          string(boardsJSON),
+		"community_cards": string(cardsJSON),
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении досок прогонов: %w", err)
+	}
+
+	return nil
+}
+
 // GetPlayerCards - получает карты игрока из Redis
 func (cd *CardDealer) GetPlayerCards(clubID, roomID, userID string) ([]string, error) {
 	playerKey := cd.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
@@ -248,6 +479,10 @@ func (cd *CardDealer) ClearAllCards(clubID, roomID string) error {
 		if err != nil {
 			cd.logger.Warningf("Не удалось очистить карты игрока %s: %v", userID, err)
 		}
+		err = cd.savePlayerUpCards(clubID, roomID, userID, []string{})
+		if err != nil {
+			cd.logger.Warningf("Не удалось очистить открытые карты игрока %s: %v", userID, err)
+		}
 	}
 
 	// Очищаем общие карты
@@ -256,6 +491,12 @@ func (cd *CardDealer) ClearAllCards(clubID, roomID string) error {
 		return err
 	}
 
+	// Очищаем доски прогонов
+	gameKey := cd.redis.GetKeys().GameState(clubID, roomID)
+	if err := cd.redis.HMSet(gameKey, map[string]interface{}{"boards": "[]", "run_count": 0}); err != nil {
+		cd.logger.Warningf("Не удалось очистить доски прогонов: %v", err)
+	}
+
 	// Удаляем колоду из Redis
 	deckKey := cd.redis.GetKeys().RoomDeck(clubID, roomID)
 	err = cd.redis.Del(deckKey)
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ChipLedger - журнал движения фишек по принципу двойной записи
+// Каждое движение фишек - это сбалансированная запись (сумма проводок = 0).
+// Журнал только дополняется, записи никогда не изменяются и не удаляются
+type ChipLedger struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры (для сверки стеков)
+	gameStateService *GameStateService
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewChipLedger - создает новый экземпляр ChipLedger
+func NewChipLedger(redis *storage.RedisClient, gameStateService *GameStateService) *ChipLedger {
+	return &ChipLedger{
+		redis:            redis,
+		gameStateService: gameStateService,
+		logger:           utils.NewLogger("ChipLedger"),
+	}
+}
+
+// === ЗАПИСЬ В ЖУРНАЛ ===
+
+// Append - добавляет запись журнала в транзакционный pipeline вызывающего кода
+// Позволяет атомарно изменить стек игрока и записать движение фишек в журнал.
+// Pipeline должен быть создан через TxPipeline и выполнен вызывающим кодом
+func (cl *ChipLedger) Append(pipe redis.Pipeliner, entry *models.LedgerEntry) error {
+	if !entry.IsBalanced() {
+		return fmt.Errorf("запись журнала не сбалансирована (%s, %s)", entry.Reason, entry.Reference)
+	}
+
+	// Номер записи выдается заранее: пропуски в нумерации допустимы, повторы - нет
+	entryID, err := cl.redis.Incr(cl.redis.GetKeys().ClubLedgerSequence(entry.ClubID))
+	if err != nil {
+		return fmt.Errorf("не удалось получить номер записи журнала: %w", err)
+	}
+	entry.EntryID = entryID
+	entry.Timestamp = utils.GetCurrentTimestamp()
+
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации записи журнала: %w", err)
+	}
+
+	keys := cl.redis.GetKeys()
+	ctx := cl.redis.GetContext()
+
+	pipe.RPush(ctx, keys.ClubLedger(entry.ClubID), string(entryJSON))
+	for _, posting := range entry.Postings {
+		pipe.HIncrBy(ctx, keys.ClubLedgerBalances(entry.ClubID), posting.Account, int64(posting.Amount))
+	}
+
+	return nil
+}
+
+// Record - записывает движение фишек в журнал отдельной транзакцией
+func (cl *ChipLedger) Record(entry *models.LedgerEntry) error {
+	pipe := cl.redis.TxPipeline()
+
+	if err := cl.Append(pipe, entry); err != nil {
+		return err
+	}
+
+	if _, err := pipe.Exec(cl.redis.GetContext()); err != nil {
+		cl.logger.Errorf("Ошибка при записи в журнал клуба %s: %v", entry.ClubID, err)
+		return fmt.Errorf("ошибка записи в журнал: %w", err)
+	}
+
+	return nil
+}
+
+// TransferPlayerChips - атомарно изменяет стек игрока и записывает движение фишек в журнал
+// Параметры:
+//   - delta: изменение стека игрока (положительное - фишки пришли игроку, отрицательное - ушли)
+//   - counterAccount: счет, с которого пришли или на который ушли фишки
+func (cl *ChipLedger) TransferPlayerChips(clubID, roomID, userID string, delta int, counterAccount string, reason models.LedgerReason, reference string) error {
+	if delta == 0 {
+		return nil
+	}
+
+	pipe := cl.redis.TxPipeline()
+	if err := cl.AppendPlayerTransfer(pipe, clubID, roomID, userID, delta, counterAccount, reason, reference); err != nil {
+		return err
+	}
+
+	if _, err := pipe.Exec(cl.redis.GetContext()); err != nil {
+		cl.logger.Errorf("Ошибка при переводе фишек игрока %s: %v", userID, err)
+		return fmt.Errorf("ошибка перевода фишек: %w", err)
+	}
+
+	return nil
+}
+
+// AppendPlayerTransfer - добавляет изменение стека игрока и запись журнала в транзакционный
+// pipeline вызывающего кода (см. TransferPlayerChips)
+// Позволяет выполнить в той же транзакции связанные изменения, например списание отложенных фишек
+func (cl *ChipLedger) AppendPlayerTransfer(pipe redis.Pipeliner, clubID, roomID, userID string, delta int, counterAccount string, reason models.LedgerReason, reference string) error {
+	stackAccount := models.PlayerStackAccount(roomID, userID)
+	entry := models.NewTransferEntry(clubID, roomID, reason, reference, counterAccount, stackAccount, delta)
+
+	playerKey := cl.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
+	pipe.HIncrBy(cl.redis.GetContext(), playerKey, "chips", int64(delta))
+
+	return cl.Append(pipe, entry)
+}
+
+// RecordBuyIn - покупка фишек: касса -> стек игрока
+func (cl *ChipLedger) RecordBuyIn(clubID, roomID, userID string, amount int, reference string) error {
+	return cl.TransferPlayerChips(clubID, roomID, userID, amount, models.LedgerAccountCashier, models.LedgerReasonBuyIn, reference)
+}
+
+// RecordCashOut - вывод фишек: стек игрока -> касса
+func (cl *ChipLedger) RecordCashOut(clubID, roomID, userID string, amount int, reference string) error {
+	return cl.TransferPlayerChips(clubID, roomID, userID, -amount, models.LedgerAccountCashier, models.LedgerReasonCashOut, reference)
+}
+
+// RecordBlind - обязательная ставка: стек игрока -> банк стола
+func (cl *ChipLedger) RecordBlind(clubID, roomID, userID string, amount int, reference string) error {
+	return cl.TransferPlayerChips(clubID, roomID, userID, -amount, models.RoomPotAccount(roomID), models.LedgerReasonBlind, reference)
+}
+
+// AppendBet - ставка игрока: стек игрока -> банк стола
+// Добавляется в pipeline вызывающего кода, чтобы ставка и состояние торговли менялись в одной транзакции
+func (cl *ChipLedger) AppendBet(pipe redis.Pipeliner, clubID, roomID, userID string, amount int, reference string) error {
+	return cl.AppendPlayerTransfer(pipe, clubID, roomID, userID, -amount, models.RoomPotAccount(roomID), models.LedgerReasonBet, reference)
+}
+
+// AppendPotWin - выигрыш банка: банк стола -> стек игрока
+// Добавляется в pipeline вызывающего кода, чтобы рейк и все выигрыши раздачи записывались одной транзакцией
+func (cl *ChipLedger) AppendPotWin(pipe redis.Pipeliner, clubID, roomID, userID string, amount int, reference string) error {
+	return cl.AppendPlayerTransfer(pipe, clubID, roomID, userID, amount, models.RoomPotAccount(roomID), models.LedgerReasonPotWin, reference)
+}
+
+// AppendRefund - возврат неуравненной ставки: банк стола -> стек игрока
+// Добавляется в pipeline вызывающего кода вместе с выигрышами раздачи
+func (cl *ChipLedger) AppendRefund(pipe redis.Pipeliner, clubID, roomID, userID string, amount int, reference string) error {
+	return cl.AppendPlayerTransfer(pipe, clubID, roomID, userID, amount, models.RoomPotAccount(roomID), models.LedgerReasonRefund, reference)
+}
+
+// RecordAdminAdjustment - ручная корректировка стека игрока администратором
+// delta может быть как положительной, так и отрицательной
+func (cl *ChipLedger) RecordAdminAdjustment(clubID, roomID, userID string, delta int, reference string) error {
+	return cl.TransferPlayerChips(clubID, roomID, userID, delta, models.LedgerAccountAdjustments, models.LedgerReasonAdminAdjustment, reference)
+}
+
+// RecordTournamentEntry - взнос за участие в турнире: касса -> призовой фонд и счет комиссий
+func (cl *ChipLedger) RecordTournamentEntry(clubID, tournamentID string, buyIn, fee int, reference string) error {
+	return cl.recordTournamentPayment(clubID, tournamentID, models.LedgerReasonTournamentEntry, buyIn, fee, reference)
+}
+
+// RecordTournamentRebuy - оплата ребая: касса -> призовой фонд и счет комиссий
+func (cl *ChipLedger) RecordTournamentRebuy(clubID, tournamentID string, buyIn, fee int, reference string) error {
+	return cl.recordTournamentPayment(clubID, tournamentID, models.LedgerReasonTournamentRebuy, buyIn, fee, reference)
+}
+
+// RecordTournamentAddOn - оплата аддона: касса -> призовой фонд
+func (cl *ChipLedger) RecordTournamentAddOn(clubID, tournamentID string, cost int, reference string) error {
+	return cl.recordTournamentPayment(clubID, tournamentID, models.LedgerReasonTournamentAddOn, cost, 0, reference)
+}
+
+// RefundTournamentEntry - возврат взноса за участие, если регистрацию не удалось завершить:
+// призовой фонд и счет комиссий -> касса (сторнирующая запись взноса)
+func (cl *ChipLedger) RefundTournamentEntry(clubID, tournamentID string, buyIn, fee int, reference string) error {
+	return cl.recordTournamentPayment(clubID, tournamentID, models.LedgerReasonTournamentEntry, -buyIn, -fee, reference)
+}
+
+// recordTournamentPayment - платеж участника турнира: касса -> призовой фонд (prizePart) и счет комиссий (fee)
+func (cl *ChipLedger) recordTournamentPayment(clubID, tournamentID string, reason models.LedgerReason, prizePart, fee int, reference string) error {
+	entry := &models.LedgerEntry{
+		ClubID:    clubID,
+		Reason:    reason,
+		Reference: reference,
+		Postings: []models.LedgerPosting{
+			{Account: models.LedgerAccountCashier, Amount: -(prizePart + fee)},
+			{Account: models.TournamentPrizePoolAccount(tournamentID), Amount: prizePart},
+		},
+	}
+	if fee != 0 {
+		entry.Postings = append(entry.Postings, models.LedgerPosting{Account: models.LedgerAccountTournamentFees, Amount: fee})
+	}
+
+	return cl.Record(entry)
+}
+
+// RecordTournamentChips - выдача (delta > 0) или возврат (delta < 0) турнирных фишек:
+// счет турнирных фишек <-> стек игрока
+func (cl *ChipLedger) RecordTournamentChips(clubID, roomID, tournamentID, userID string, delta int, reference string) error {
+	return cl.TransferPlayerChips(clubID, roomID, userID, delta, models.TournamentChipsAccount(tournamentID), models.LedgerReasonTournamentChips, reference)
+}
+
+// RecordTournamentPrize - выплата турнирного приза: призовой фонд -> касса
+func (cl *ChipLedger) RecordTournamentPrize(clubID, tournamentID string, amount int, reference string) error {
+	return cl.Record(models.NewTransferEntry(clubID, "", models.LedgerReasonTournamentPrize, reference,
+		models.TournamentPrizePoolAccount(tournamentID), models.LedgerAccountCashier, amount))
+}
+
+// RecordTournamentBounty - выплата баунти: призовой фонд (куда поступила баунти-часть взносов) -> касса
+func (cl *ChipLedger) RecordTournamentBounty(clubID, tournamentID string, amount int, reference string) error {
+	return cl.Record(models.NewTransferEntry(clubID, "", models.LedgerReasonTournamentKO, reference,
+		models.TournamentPrizePoolAccount(tournamentID), models.LedgerAccountCashier, amount))
+}
+
+// NewRakeEntry - создает запись о рейке: банк стола -> счет рейка клуба
+// Запись добавляется в транзакцию рейка через Append
+func (cl *ChipLedger) NewRakeEntry(clubID, roomID string, amount int, reference string) *models.LedgerEntry {
+	return models.NewTransferEntry(clubID, roomID, models.LedgerReasonRake, reference,
+		models.RoomPotAccount(roomID), models.LedgerAccountRake, amount)
+}
+
+// === ЧТЕНИЕ ЖУРНАЛА ===
+
+// GetBalance - возвращает баланс счета по журналу
+func (cl *ChipLedger) GetBalance(clubID, account string) (int, error) {
+	value, err := cl.redis.HGet(cl.redis.GetKeys().ClubLedgerBalances(clubID), account)
+	if err != nil {
+		return 0, err
+	}
+	balance, _ := strconv.Atoi(value)
+	return balance, nil
+}
+
+// GetPlayerBalance - возвращает баланс стека игрока по журналу
+func (cl *ChipLedger) GetPlayerBalance(clubID, roomID, userID string) (int, error) {
+	return cl.GetBalance(clubID, models.PlayerStackAccount(roomID, userID))
+}
+
+// GetRecentEntries - возвращает последние N записей журнала клуба
+func (cl *ChipLedger) GetRecentEntries(clubID string, count int64) ([]models.LedgerEntry, error) {
+	if count <= 0 {
+		count = 100
+	}
+
+	jsonStrings, err := cl.redis.LRange(cl.redis.GetKeys().ClubLedger(clubID), -count, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.LedgerEntry, 0, len(jsonStrings))
+	for _, jsonStr := range jsonStrings {
+		var entry models.LedgerEntry
+		if err := json.Unmarshal([]byte(jsonStr), &entry); err != nil {
+			cl.logger.Warningf("Ошибка при парсинге записи журнала: %v", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// === СВЕРКА ===
+
+// ReconcileRoom - сверяет стеки игроков и банк стола с балансами журнала
+// Возвращает найденные расхождения (пустой список - все сходится)
+func (cl *ChipLedger) ReconcileRoom(clubID, roomID string) ([]models.ReconciliationMismatch, error) {
+	playerIDs, err := cl.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := cl.redis.HGetAll(cl.redis.GetKeys().ClubLedgerBalances(clubID))
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]models.ReconciliationMismatch, 0)
+	check := func(account string, expected int) {
+		balance, _ := strconv.Atoi(balances[account])
+		if balance != expected {
+			mismatches = append(mismatches, models.ReconciliationMismatch{
+				ClubID:         clubID,
+				RoomID:         roomID,
+				Account:        account,
+				Expected:       expected,
+				LedgerBalance:  balance,
+				Difference:     expected - balance,
+				DetectedAtUnix: utils.GetCurrentTimestamp(),
+			})
+		}
+	}
+
+	// Стек каждого игрока должен совпадать с балансом его счета,
+	// а банк стола - с суммой ставок игроков за раздачу
+	potTotal := 0
+	for _, userID := range playerIDs {
+		player, err := cl.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil {
+			continue
+		}
+
+		check(models.PlayerStackAccount(roomID, userID), player.Chips)
+		potTotal += player.TotalBet
+	}
+	check(models.RoomPotAccount(roomID), potTotal)
+
+	return mismatches, nil
+}
+
+// RecordMismatches - сохраняет найденные расхождения для разбора администратором
+func (cl *ChipLedger) RecordMismatches(clubID string, mismatches []models.ReconciliationMismatch) error {
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		mismatchJSON, err := json.Marshal(mismatch)
+		if err != nil {
+			return err
+		}
+		values = append(values, string(mismatchJSON))
+	}
+
+	return cl.redis.RPush(cl.redis.GetKeys().ClubLedgerMismatches(clubID), values...)
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
+	"fmt"
 	"math/rand"
 	"time"
 
+	"poker-engine/models"
 	"poker-engine/storage"
 	"poker-engine/utils"
 )
@@ -59,21 +61,19 @@ const (
 	King  = "K" // Король
 )
 
-// CreateDeck - создает новую колоду из 52 карт
+// CreateDeck - создает новую колоду для разновидности покера
+// Обычная колода - 52 карты, в шорт-деке двойки-пятерки убраны (36 карт)
 // Возвращает массив строк вида: ["AH", "2H", "3H", ..., "KS"]
-func (dm *DeckManager) CreateDeck() []string {
+func (dm *DeckManager) CreateDeck(gameType models.GameType) []string {
 	// Массив мастей (4 масти)
 	suits := []string{Hearts, Diamonds, Clubs, Spades}
 
-	// Массив достоинств (13 достоинств)
-	ranks := []string{
-		Ace, Two, Three, Four, Five, Six, Seven,
-		Eight, Nine, Ten, Jack, Queen, King,
-	}
+	// Массив достоинств (13 достоинств, в шорт-деке - 9)
+	ranks := deckRanks(gameType)
 
 	// Создаем пустой массив для колоды
 	// 4 масти × 13 достоинств = 52 карты
-	deck := make([]string, 0, 52)
+	deck := make([]string, 0, gameType.DeckSize())
 
 	// Проходим по всем мастям
 	for _, suit := range suits {
@@ -115,11 +115,32 @@ func (dm *DeckManager) ShuffleDeck(deck []string) []string {
 }
 
 // CreateAndShuffleDeck - создает и сразу тасует колоду (удобный метод)
-func (dm *DeckManager) CreateAndShuffleDeck() []string {
-	deck := dm.CreateDeck()
+func (dm *DeckManager) CreateAndShuffleDeck(gameType models.GameType) []string {
+	deck := dm.CreateDeck(gameType)
 	return dm.ShuffleDeck(deck)
 }
 
+// ValidateDeck - проверяет колоду разновидности покера:
+// нужное количество карт, все карты допустимы и не повторяются
+func ValidateDeck(deck []string, gameType models.GameType) error {
+	if len(deck) != gameType.DeckSize() {
+		return fmt.Errorf("в колоде %d карт, ожидалось %d", len(deck), gameType.DeckSize())
+	}
+
+	seen := make(map[string]bool, len(deck))
+	for _, card := range deck {
+		if !IsValidCard(card, gameType) {
+			return fmt.Errorf("недопустимая карта в колоде: %s", card)
+		}
+		if seen[card] {
+			return fmt.Errorf("карта %s встречается в колоде дважды", card)
+		}
+		seen[card] = true
+	}
+
+	return nil
+}
+
 // SaveDeckToRedis - сохраняет колоду в Redis для комнаты
 // Сохраняется как список (LIST), карты берутся с конца (RPOP)
 func (dm *DeckManager) SaveDeckToRedis(clubID, roomID string, deck []string) error {
@@ -249,8 +270,9 @@ func FormatCards(cards []string) []string {
 	return formatted
 }
 
-// IsValidCard - проверяет, является ли строка валидной картой
-func IsValidCard(card string) bool {
+// IsValidCard - проверяет, является ли строка валидной картой колоды разновидности покера
+// Например, "2H" - валидная карта в холдеме, но не в шорт-деке
+func IsValidCard(card string, gameType models.GameType) bool {
 	if len(card) != 2 {
 		return false
 	}
@@ -259,7 +281,7 @@ func IsValidCard(card string) bool {
 	suit := string(card[1])
 
 	// Проверяем достоинство
-	validRanks := []string{Ace, Two, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King}
+	validRanks := deckRanks(gameType)
 	validRank := false
 	for _, r := range validRanks {
 		if rank == r {
@@ -283,3 +305,24 @@ func IsValidCard(card string) bool {
 
 	return validSuit
 }
+
+// deckRanks - достоинства карт в колоде разновидности покера
+func deckRanks(gameType models.GameType) []string {
+	ranks := []string{
+		Ace, Two, Three, Four, Five, Six, Seven,
+		Eight, Nine, Ten, Jack, Queen, King,
+	}
+	if !gameType.IsShortDeck() {
+		return ranks
+	}
+
+	// В шорт-деке остаются только карты от шестерки и выше
+	short := make([]string, 0, len(ranks))
+	for _, rank := range ranks {
+		card, err := models.ParseCard(rank + Hearts)
+		if err == nil && card.Rank >= gameType.MinCardRank() {
+			short = append(short, rank)
+		}
+	}
+	return short
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"poker-engine/models"
+	"poker-engine/utils"
+)
+
+// EquityCalculator - сервис расчета эквити известных рук
+// Карты в формате колоды DeckManager ("AH", "TD"). Если возможных досок немного
+// (не больше models.ExactEquityBoards), перебираются все доски, иначе доска
+// достраивается случайно заданное количество раз (Монте-Карло)
+type EquityCalculator struct {
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// deckManager - сервис колоды (состав колоды разновидности покера)
+	deckManager *DeckManager
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewEquityCalculator - создает новый экземпляр EquityCalculator
+func NewEquityCalculator(gameStateService *GameStateService, deckManager *DeckManager) *EquityCalculator {
+	return &EquityCalculator{
+		gameStateService: gameStateService,
+		deckManager:      deckManager,
+		logger:           utils.NewLogger("EquityCalculator"),
+	}
+}
+
+// equityTally - накопленные результаты руки по просчитанным доскам
+type equityTally struct {
+	wins   int
+	ties   int
+	equity float64
+}
+
+// Calculate - рассчитывает вероятность выигрыша, дележа и ожидаемую долю банка каждой руки
+func (ec *EquityCalculator) Calculate(request *models.EquityRequest) (*models.EquityResult, error) {
+	gameType := request.GameType
+	if gameType == "" {
+		gameType = models.GameTypeHoldem
+	}
+	if !gameType.IsValid() || gameType.IsStud() {
+		return nil, ErrEquityUnsupportedGame
+	}
+
+	hands, board, stub, err := ec.parseRequest(gameType, request)
+	if err != nil {
+		return nil, err
+	}
+
+	iterations := request.Iterations
+	if iterations <= 0 {
+		iterations = models.DefaultEquityIterations
+	}
+	if iterations > models.MaxEquityIterations {
+		return nil, ErrInvalidEquityRequest
+	}
+
+	missing := 5 - len(board)
+	tallies := make([]equityTally, len(hands))
+	result := &models.EquityResult{}
+
+	if models.CountCombinations(len(stub), missing, models.ExactEquityBoards) <= models.ExactEquityBoards {
+		result.Exact = true
+		if missing == 0 {
+			if err := scoreBoard(gameType, hands, board, tallies); err != nil {
+				return nil, err
+			}
+			result.Boards = 1
+		} else {
+			for _, indexes := range models.Combinations(len(stub), missing) {
+				full := append(board[:len(board):len(board)], pickStub(stub, indexes)...)
+				if err := scoreBoard(gameType, hands, full, tallies); err != nil {
+					return nil, err
+				}
+				result.Boards++
+			}
+		}
+	} else {
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		deck := make([]models.Card, len(stub))
+		copy(deck, stub)
+
+		for i := 0; i < iterations; i++ {
+			// Частичная тасовка Фишера-Йетса: первые missing карт - случайная достройка доски
+			for j := 0; j < missing; j++ {
+				k := j + rng.Intn(len(deck)-j)
+				deck[j], deck[k] = deck[k], deck[j]
+			}
+			full := append(board[:len(board):len(board)], deck[:missing]...)
+			if err := scoreBoard(gameType, hands, full, tallies); err != nil {
+				return nil, err
+			}
+		}
+		result.Boards = iterations
+	}
+
+	result.Players = make([]models.PlayerEquity, len(hands))
+	for i, player := range request.Players {
+		result.Players[i] = models.PlayerEquity{
+			UserID: player.UserID,
+			Cards:  player.Cards,
+			Win:    float64(tallies[i].wins) / float64(result.Boards),
+			Tie:    float64(tallies[i].ties) / float64(result.Boards),
+			Equity: tallies[i].equity / float64(result.Boards),
+		}
+	}
+
+	ec.logger.Debugf("Эквити %d рук рассчитано по %d доскам (точно: %v)", len(hands), result.Boards, result.Exact)
+
+	return result, nil
+}
+
+// RoomRequest - запрос эквити для текущей раздачи комнаты: руки игроков, не сбросивших карты,
+// и открытая доска; карты сбросивших игроков считаются вышедшими из игры
+func (ec *EquityCalculator) RoomRequest(clubID, roomID string, iterations int) (*models.EquityRequest, error) {
+	room, err := ec.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	game, err := ec.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	playerIDs, err := ec.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &models.EquityRequest{
+		GameType:   room.GameType,
+		Board:      game.CommunityCards,
+		Iterations: iterations,
+	}
+	for _, userID := range playerIDs {
+		player, err := ec.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil || !player.HasCards() {
+			continue
+		}
+		if player.IsFolded() {
+			request.Dead = append(request.Dead, player.AllCards()...)
+			continue
+		}
+		request.Players = append(request.Players, models.EquityPlayer{UserID: userID, Cards: player.Cards})
+	}
+
+	return request, nil
+}
+
+// CalculateRoom - эквити игроков текущей раздачи комнаты (для зрителей и кэш-аута по EV)
+func (ec *EquityCalculator) CalculateRoom(clubID, roomID string, iterations int) (*models.EquityResult, error) {
+	request, err := ec.RoomRequest(clubID, roomID, iterations)
+	if err != nil {
+		return nil, err
+	}
+	return ec.Calculate(request)
+}
+
+// parseRequest - проверяет запрос и разбирает карты
+// Возвращает руки игроков, открытую доску и оставшиеся в колоде карты
+func (ec *EquityCalculator) parseRequest(gameType models.GameType, request *models.EquityRequest) ([][]models.Card, []models.Card, []models.Card, error) {
+	if len(request.Players) < models.MinEquityPlayers || len(request.Players) > models.MaxEquityPlayers {
+		return nil, nil, nil, ErrInvalidEquityRequest
+	}
+	switch len(request.Board) {
+	case 0, 3, 4, 5:
+	default:
+		return nil, nil, nil, ErrInvalidEquityRequest
+	}
+
+	known := make(map[string]bool)
+	use := func(cards []string) ([]models.Card, error) {
+		for _, card := range cards {
+			if !IsValidCard(card, gameType) || known[card] {
+				return nil, ErrInvalidEquityRequest
+			}
+			known[card] = true
+		}
+		return models.ParseCards(cards)
+	}
+
+	hands := make([][]models.Card, len(request.Players))
+	for i, player := range request.Players {
+		if len(player.Cards) != gameType.HoleCardsCount() {
+			return nil, nil, nil, ErrInvalidEquityRequest
+		}
+		hand, err := use(player.Cards)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		hands[i] = hand
+	}
+
+	board, err := use(request.Board)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err := use(request.Dead); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var stub []models.Card
+	for _, card := range ec.deckManager.CreateDeck(gameType) {
+		if known[card] {
+			continue
+		}
+		parsed, err := models.ParseCard(card)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		stub = append(stub, parsed)
+	}
+	if len(stub) < 5-len(board) {
+		return nil, nil, nil, ErrInvalidEquityRequest
+	}
+
+	return hands, board, stub, nil
+}
+
+// scoreBoard - оценивает руки на полной доске и добавляет результат к накопленным
+// Банк делится между лучшими старшими руками; в хай-лоу половина банка достается
+// лучшим младшим рукам, если младшая рука прошла
+func scoreBoard(gameType models.GameType, hands [][]models.Card, board []models.Card, tallies []equityTally) error {
+	highs := make([]models.HandValue, len(hands))
+	lows := make([]*models.LowHandValue, len(hands))
+
+	var highWinners, lowWinners []int
+	for i, hand := range hands {
+		high, err := evaluateHigh(gameType, hand, board)
+		if err != nil {
+			return err
+		}
+		highs[i] = high
+		if len(highWinners) == 0 {
+			highWinners = []int{i}
+		} else if cmp := high.Compare(highs[highWinners[0]]); cmp > 0 {
+			highWinners = []int{i}
+		} else if cmp == 0 {
+			highWinners = append(highWinners, i)
+		}
+
+		if !gameType.IsHiLo() {
+			continue
+		}
+		low, err := evaluateLow(gameType, hand, board)
+		if err != nil {
+			return err
+		}
+		lows[i] = low
+		if low == nil {
+			continue
+		}
+		if len(lowWinners) == 0 {
+			lowWinners = []int{i}
+		} else if cmp := low.Compare(*lows[lowWinners[0]]); cmp > 0 {
+			lowWinners = []int{i}
+		} else if cmp == 0 {
+			lowWinners = append(lowWinners, i)
+		}
+	}
+
+	shares := make([]float64, len(hands))
+	highPart := 1.0
+	if len(lowWinners) > 0 {
+		highPart = 0.5
+		for _, i := range lowWinners {
+			shares[i] += 0.5 / float64(len(lowWinners))
+		}
+	}
+	for _, i := range highWinners {
+		shares[i] += highPart / float64(len(highWinners))
+	}
+
+	for i, share := range shares {
+		if share <= 0 {
+			continue
+		}
+		tallies[i].equity += share
+		if share >= 1 {
+			tallies[i].wins++
+		} else {
+			tallies[i].ties++
+		}
+	}
+
+	return nil
+}
+
+// pickStub - выбирает карты колоды по индексам
+func pickStub(stub []models.Card, indexes []int) []models.Card {
+	cards := make([]models.Card, len(indexes))
+	for i, index := range indexes {
+		cards[i] = stub[index]
+	}
+	return cards
+}
+
+var (
+	ErrInvalidEquityRequest  = &EquityError{message: "invalid equity request"}
+	ErrEquityUnsupportedGame = &EquityError{message: "equity is available only for community card games"}
+)
+
+type EquityError struct {
+	message string
+}
+
+func (e *EquityError) Error() string {
+	return "equity error: " + e.message
+}
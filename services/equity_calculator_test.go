@@ -0,0 +1,127 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"poker-engine/models"
+)
+
+func TestEquityCalculatorCalculate(t *testing.T) {
+	tests := []struct {
+		name      string
+		gameType  models.GameType
+		hands     [][]string
+		board     []string
+		want      []float64
+		tolerance float64
+		exact     bool
+	}{
+		{
+			// Монте-Карло: 20 000 прогонов дают отклонение около 0.003
+			name:      "AA против KK до флопа",
+			hands:     [][]string{{"AS", "AH"}, {"KD", "KC"}},
+			want:      []float64{0.813, 0.187},
+			tolerance: 0.015,
+		},
+		{
+			name:      "сет королей против тузов на терне - два аута из 44",
+			hands:     [][]string{{"AH", "AD"}, {"KH", "KD"}},
+			board:     []string{"KS", "7C", "2H", "3D"},
+			want:      []float64{2.0 / 44, 42.0 / 44},
+			tolerance: 1e-9,
+			exact:     true,
+		},
+		{
+			name:      "роял-флеш на доске - банк делится",
+			hands:     [][]string{{"2C", "3D"}, {"4H", "5C"}},
+			board:     []string{"AS", "KS", "QS", "JS", "TS"},
+			want:      []float64{0.5, 0.5},
+			tolerance: 1e-9,
+			exact:     true,
+		},
+		{
+			name:      "шорт-дек: флеш старше фулл-хауса",
+			gameType:  models.GameTypeShortDeck,
+			hands:     [][]string{{"AH", "KH"}, {"9D", "TC"}},
+			board:     []string{"6H", "7H", "9H", "9C", "6C"},
+			want:      []float64{1, 0},
+			tolerance: 1e-9,
+			exact:     true,
+		},
+		{
+			name:      "омаха хай-лоу: старшая рука и половина младшей",
+			gameType:  models.GameTypeOmahaHiLo,
+			hands:     [][]string{{"AH", "2H", "KC", "KD"}, {"AS", "2S", "QC", "QD"}},
+			board:     []string{"3D", "5C", "8H", "KH", "JS"},
+			want:      []float64{0.75, 0.25},
+			tolerance: 1e-9,
+			exact:     true,
+		},
+	}
+
+	calculator := NewEquityCalculator(nil, NewDeckManager(nil))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &models.EquityRequest{GameType: tt.gameType, Board: tt.board}
+			for _, hand := range tt.hands {
+				request.Players = append(request.Players, models.EquityPlayer{Cards: hand})
+			}
+
+			result, err := calculator.Calculate(request)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if result.Exact != tt.exact {
+				t.Errorf("Calculate().Exact = %v, want %v", result.Exact, tt.exact)
+			}
+
+			total := 0.0
+			for i, player := range result.Players {
+				total += player.Equity
+				if math.Abs(player.Equity-tt.want[i]) > tt.tolerance {
+					t.Errorf("эквити руки %v = %.4f, want %.4f", player.Cards, player.Equity, tt.want[i])
+				}
+			}
+			if math.Abs(total-1) > 1e-9 {
+				t.Errorf("сумма эквити = %.6f, want 1", total)
+			}
+		})
+	}
+}
+
+func TestEquityCalculatorInvalidRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *models.EquityRequest
+	}{
+		{
+			name:    "одна рука",
+			request: &models.EquityRequest{Players: []models.EquityPlayer{{Cards: []string{"AS", "AH"}}}},
+		},
+		{
+			name: "карта повторяется",
+			request: &models.EquityRequest{Players: []models.EquityPlayer{
+				{Cards: []string{"AS", "AH"}}, {Cards: []string{"AS", "KD"}},
+			}},
+		},
+		{
+			name: "две открытые карты",
+			request: &models.EquityRequest{
+				Players: []models.EquityPlayer{{Cards: []string{"AS", "AH"}}, {Cards: []string{"KS", "KD"}}},
+				Board:   []string{"2C", "3C"},
+			},
+		},
+	}
+
+	calculator := NewEquityCalculator(nil, NewDeckManager(nil))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := calculator.Calculate(tt.request); err != ErrInvalidEquityRequest {
+				t.Errorf("Calculate() error = %v, want %v", err, ErrInvalidEquityRequest)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package services
 
 import (
+	"strconv"
+
 	"poker-engine/models"
 	"poker-engine/storage"
 	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // GameStateService - сервис для работы с состоянием игры
@@ -117,6 +121,97 @@ func (gs *GameStateService) GetPlayerIDs(clubID, roomID string) ([]string, error
 	return playerIDs, nil
 }
 
+// GetSeatedPlayers - получает игроков, отсортированных по номеру места
+// Порядок берется из sorted set turn_order (score = номер места).
+// Если turn_order не совпадает со списком игроков, он перестраивается по позициям игроков
+func (gs *GameStateService) GetSeatedPlayers(clubID, roomID string) ([]models.SeatAssignment, error) {
+	turnOrderKey := gs.redis.GetKeys().RoomTurnOrder(clubID, roomID)
+
+	entries, err := gs.redis.ZRangeWithScores(turnOrderKey, 0, -1)
+	if err != nil {
+		gs.logger.Errorf("Ошибка при получении порядка мест в комнате %s:%s: %v", clubID, roomID, err)
+		return nil, err
+	}
+
+	playersCount, err := gs.GetPlayersCount(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(entries)) != playersCount {
+		return gs.RebuildTurnOrder(clubID, roomID)
+	}
+
+	seats := make([]models.SeatAssignment, 0, len(entries))
+	for _, entry := range entries {
+		userID, _ := entry.Member.(string)
+		seats = append(seats, models.SeatAssignment{UserID: userID, Seat: int(entry.Score)})
+	}
+
+	return seats, nil
+}
+
+// GetPlayerIDsBySeat - получает ID игроков в порядке мест за столом (по часовой стрелке от места 0)
+// В отличие от GetPlayerIDs, порядок детерминирован и подходит для определения очередности ходов
+func (gs *GameStateService) GetPlayerIDsBySeat(clubID, roomID string) ([]string, error) {
+	seats, err := gs.GetSeatedPlayers(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	return models.UserIDs(seats), nil
+}
+
+// GetPlayerIDsFromSeat - получает ID игроков по часовой стрелке, начиная с места после afterSeat
+// Например, GetPlayerIDsFromSeat(clubID, roomID, game.DealerPosition) - порядок раздачи карт
+func (gs *GameStateService) GetPlayerIDsFromSeat(clubID, roomID string, afterSeat int) ([]string, error) {
+	seats, err := gs.GetSeatedPlayers(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	return models.UserIDs(models.OrderFromSeat(seats, afterSeat)), nil
+}
+
+// RebuildTurnOrder - перестраивает turn_order по позициям игроков
+// Места игроков также добавляются в occupied_seats (без удаления чужих резерваций)
+func (gs *GameStateService) RebuildTurnOrder(clubID, roomID string) ([]models.SeatAssignment, error) {
+	playerIDs, err := gs.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make([]models.SeatAssignment, 0, len(playerIDs))
+	for _, userID := range playerIDs {
+		player, err := gs.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil {
+			continue
+		}
+		seats = append(seats, models.SeatAssignment{UserID: userID, Seat: player.Position})
+	}
+	models.SortBySeat(seats)
+
+	keys := gs.redis.GetKeys()
+	ctx := gs.redis.GetContext()
+	turnOrderKey := keys.RoomTurnOrder(clubID, roomID)
+	seatsKey := keys.RoomOccupiedSeats(clubID, roomID)
+
+	pipe := gs.redis.TxPipeline()
+	pipe.Del(ctx, turnOrderKey)
+	for _, seat := range seats {
+		pipe.ZAdd(ctx, turnOrderKey, redis.Z{Score: float64(seat.Seat), Member: seat.UserID})
+		pipe.SAdd(ctx, seatsKey, strconv.Itoa(seat.Seat))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		gs.logger.Errorf("Ошибка при перестроении порядка мест в комнате %s:%s: %v", clubID, roomID, err)
+		return nil, err
+	}
+
+	gs.logger.Infof("Порядок мест в комнате %s:%s перестроен (%d игроков)", clubID, roomID, len(seats))
+	return seats, nil
+}
+
 // GetPlayer - получает информацию об игроке
 func (gs *GameStateService) GetPlayer(clubID, roomID, userID string) (*models.Player, error) {
 	playerKey := gs.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
@@ -329,6 +424,16 @@ func (gs *GameStateService) GetFullRoomState(clubID, roomID string) (map[string]
 		"spectators_count": spectatorsCount,
 	}
 
+	// Часы блайндов (только для комнат с расписанием уровней)
+	clockData, _ := gs.redis.HGetAll(gs.redis.GetKeys().RoomBlindClock(clubID, roomID))
+	if len(clockData) > 0 {
+		if clock, err := models.NewBlindClockFromRedis(clockData); err == nil {
+			now := utils.GetCurrentTimestampMillis()
+			clock.Advance(now)
+			fullState["blind_clock"] = clock.Status(now)
+		}
+	}
+
 	return fullState, nil
 }
 
@@ -349,6 +454,9 @@ func (gs *GameStateService) CleanupRoom(clubID, roomID string) error {
 		keys.RoomDeck(clubID, roomID),
 		keys.RoomPots(clubID, roomID),
 		keys.RoomTimers(clubID, roomID),
+		keys.RoomBlindClock(clubID, roomID),
+		keys.RoomAllInEquity(clubID, roomID),
+		keys.RoomHandActions(clubID, roomID),
 	}
 
 	// Удаляем все ключи
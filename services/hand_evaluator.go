@@ -0,0 +1,263 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/utils"
+)
+
+// HandEvaluator - сервис для оценки рук и определения победителей банков на вскрытии
+// Правила составления руки зависят от разновидности покера комнаты:
+// в холдеме - любые 5 из 7 карт, в омахе - ровно 2 карты игрока и 3 общие карты,
+// в шорт-деке - любые 5 из 7 с флешем старше фулл-хауса, в стаде - любые 5 из 7 карт игрока.
+// В омахе и стаде хай-лоу дополнительно оценивается младшая рука 8 or better,
+// в рэззе банк целиком выигрывает младшая рука от туза до пятерки
+type HandEvaluator struct {
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewHandEvaluator - создает новый экземпляр HandEvaluator
+func NewHandEvaluator(gameStateService *GameStateService) *HandEvaluator {
+	return &HandEvaluator{
+		gameStateService: gameStateService,
+		logger:           utils.NewLogger("HandEvaluator"),
+	}
+}
+
+// EvaluateHand - оценивает старшую руку игрока по правилам разновидности покера
+func (he *HandEvaluator) EvaluateHand(gameType models.GameType, holeCards, board []string) (models.HandValue, error) {
+	hole, community, err := parseHand(holeCards, board)
+	if err != nil {
+		return models.HandValue{}, err
+	}
+
+	return evaluateHigh(gameType, hole, community)
+}
+
+// EvaluateLowHand - оценивает младшую руку 8 or better (в рэззе - от туза до пятерки)
+// Возвращает nil, если в разновидности покера нет младшей руки или младшая рука не прошла
+func (he *HandEvaluator) EvaluateLowHand(gameType models.GameType, holeCards, board []string) (*models.LowHandValue, error) {
+	if !gameType.IsHiLo() && !gameType.IsRazz() {
+		return nil, nil
+	}
+
+	hole, community, err := parseHand(holeCards, board)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluateLow(gameType, hole, community)
+}
+
+// evaluateHigh - оценивает старшую руку по уже разобранным картам
+func evaluateHigh(gameType models.GameType, hole, community []models.Card) (models.HandValue, error) {
+	cards := make([]models.Card, 0, len(hole)+len(community))
+	cards = append(append(cards, hole...), community...)
+
+	if gameType.UsesOmahaRules() {
+		return models.EvaluateOmaha(hole, community)
+	}
+	if gameType.IsShortDeck() {
+		return models.EvaluateBestShortDeck(cards)
+	}
+	return models.EvaluateBest(cards)
+}
+
+// evaluateLow - оценивает младшую руку по уже разобранным картам
+// (вызывается только для хай-лоу и рэзза)
+func evaluateLow(gameType models.GameType, hole, community []models.Card) (*models.LowHandValue, error) {
+	cards := make([]models.Card, 0, len(hole)+len(community))
+	cards = append(append(cards, hole...), community...)
+
+	if gameType.IsRazz() {
+		low, err := models.EvaluateRazzBest(cards)
+		if err != nil {
+			return nil, err
+		}
+		return &low, nil
+	}
+
+	if gameType.IsStud() {
+		return models.EvaluateBestLow(cards), nil
+	}
+
+	return models.EvaluateOmahaLow(hole, community), nil
+}
+
+// EvaluatePlayers - оценивает руки всех игроков, дошедших до вскрытия
+// Возвращает руку каждого игрока (ключ - ID игрока)
+func (he *HandEvaluator) EvaluatePlayers(clubID, roomID string) (map[string]models.ShowdownHand, error) {
+	game, err := he.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	return he.EvaluatePlayersOnBoard(clubID, roomID, game.CommunityCards)
+}
+
+// EvaluatePlayersOnBoard - оценивает руки игроков, дошедших до вскрытия, на указанной доске
+// Используется при нескольких прогонах доски: руки оцениваются на каждой доске отдельно
+func (he *HandEvaluator) EvaluatePlayersOnBoard(clubID, roomID string, board []string) (map[string]models.ShowdownHand, error) {
+	room, err := he.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	playerIDs, err := he.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	hands := make(map[string]models.ShowdownHand)
+	for _, userID := range playerIDs {
+		player, err := he.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil || !player.HasCards() || player.IsFolded() {
+			continue
+		}
+
+		// В стаде рука составляется из закрытых и открытых карт игрока
+		cards := player.AllCards()
+
+		high, err := he.EvaluateHand(room.GameType, cards, board)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось оценить руку игрока %s: %w", userID, err)
+		}
+
+		low, err := he.EvaluateLowHand(room.GameType, cards, board)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось оценить младшую руку игрока %s: %w", userID, err)
+		}
+
+		hands[userID] = models.ShowdownHand{UserID: userID, Cards: cards, High: high, Low: low}
+	}
+
+	return hands, nil
+}
+
+// DetermineWinners - определяет победителей каждого банка на вскрытии
+// Результат передается в PotManager.SettleHand
+func (he *HandEvaluator) DetermineWinners(clubID, roomID string, pots []models.SidePot) ([]models.PotWinners, error) {
+	hands, err := he.EvaluatePlayers(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return he.WinnersFromHands(clubID, roomID, hands, pots)
+}
+
+// WinnersFromHands - определяет победителей каждого банка по уже оцененным рукам
+// Победители каждого банка перечислены по часовой стрелке от дилера,
+// чтобы нечетные фишки при делении доставались первым после дилера.
+// В рэззе победители по младшей руке забирают банк целиком (как победители старшей половины)
+func (he *HandEvaluator) WinnersFromHands(clubID, roomID string, hands map[string]models.ShowdownHand, pots []models.SidePot) ([]models.PotWinners, error) {
+	room, err := he.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	game, err := he.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	order, err := he.gameStateService.GetPlayerIDsFromSeat(clubID, roomID, game.DealerPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	winners := make([]models.PotWinners, len(pots))
+	for i, pot := range pots {
+		if room.GameType.IsRazz() {
+			winners[i] = models.PotWinners{High: bestLowHands(hands, pot.EligiblePlayers, order)}
+			continue
+		}
+
+		winners[i] = models.PotWinners{
+			High: bestHighHands(hands, pot.EligiblePlayers, order),
+			Low:  bestLowHands(hands, pot.EligiblePlayers, order),
+		}
+	}
+
+	return winners, nil
+}
+
+// bestHighHands - возвращает игроков с сильнейшей старшей рукой среди претендентов банка
+// Порядок результата соответствует order (по часовой стрелке от дилера)
+func bestHighHands(hands map[string]models.ShowdownHand, eligible []string, order []string) []string {
+	isEligible := toSet(eligible)
+
+	var best *models.HandValue
+	var winners []string
+	for _, userID := range order {
+		hand, ok := hands[userID]
+		if !ok || !isEligible[userID] {
+			continue
+		}
+
+		switch {
+		case best == nil || hand.High.Compare(*best) > 0:
+			high := hand.High
+			best = &high
+			winners = []string{userID}
+		case hand.High.Compare(*best) == 0:
+			winners = append(winners, userID)
+		}
+	}
+
+	return winners
+}
+
+// bestLowHands - возвращает игроков с лучшей младшей рукой среди претендентов банка
+// Пустой результат - ни одна младшая рука не прошла
+func bestLowHands(hands map[string]models.ShowdownHand, eligible []string, order []string) []string {
+	isEligible := toSet(eligible)
+
+	var best *models.LowHandValue
+	var winners []string
+	for _, userID := range order {
+		hand, ok := hands[userID]
+		if !ok || !isEligible[userID] || hand.Low == nil {
+			continue
+		}
+
+		switch {
+		case best == nil || hand.Low.Compare(*best) > 0:
+			best = hand.Low
+			winners = []string{userID}
+		case hand.Low.Compare(*best) == 0:
+			winners = append(winners, userID)
+		}
+	}
+
+	return winners
+}
+
+// parseHand - разбирает карты игрока и общие карты
+func parseHand(holeCards, board []string) ([]models.Card, []models.Card, error) {
+	hole, err := models.ParseCards(holeCards)
+	if err != nil {
+		return nil, nil, err
+	}
+	community, err := models.ParseCards(board)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hole, community, nil
+}
+
+// toSet - преобразует список ID в множество
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// HandHistoryService - сервис для сохранения и чтения истории раздач
+type HandHistoryService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewHandHistoryService - создает новый экземпляр HandHistoryService
+func NewHandHistoryService(redis *storage.RedisClient) *HandHistoryService {
+	return &HandHistoryService{
+		redis:  redis,
+		logger: utils.NewLogger("HandHistory"),
+	}
+}
+
+// Save - сохраняет историю раздачи в конец списка комнаты
+func (hs *HandHistoryService) Save(history *models.HandHistory) error {
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации истории раздачи: %w", err)
+	}
+
+	key := hs.redis.GetKeys().RoomHandHistory(history.ClubID, history.RoomID)
+	if err := hs.redis.RPush(key, string(historyJSON)); err != nil {
+		hs.logger.Errorf("Ошибка при сохранении истории раздачи %s: %v", history.GameID, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetRecent - возвращает последние N раздач комнаты
+func (hs *HandHistoryService) GetRecent(clubID, roomID string, count int64) ([]models.HandHistory, error) {
+	if count <= 0 {
+		count = 50
+	}
+
+	jsonStrings, err := hs.redis.LRange(hs.redis.GetKeys().RoomHandHistory(clubID, roomID), -count, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]models.HandHistory, 0, len(jsonStrings))
+	for _, jsonStr := range jsonStrings {
+		var history models.HandHistory
+		if err := json.Unmarshal([]byte(jsonStr), &history); err != nil {
+			hs.logger.Warningf("Ошибка при парсинге истории раздачи: %v", err)
+			continue
+		}
+		histories = append(histories, history)
+	}
+
+	return histories, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// LedgerReconciler - фоновая задача сверки стеков игроков с журналом фишек
+// Периодически обходит все активные комнаты и проверяет, что стек каждого игрока
+// совпадает с балансом его счета в журнале
+type LedgerReconciler struct {
+	redis            *storage.RedisClient
+	ledger           *ChipLedger
+	gameStateService *GameStateService
+	actionLogger     *ActionLogger
+	logger           *utils.Logger
+	interval         time.Duration
+	ctx              context.Context
+	cancelFunc       context.CancelFunc
+	isRunning        bool
+}
+
+// NewLedgerReconciler - создает новый экземпляр LedgerReconciler
+func NewLedgerReconciler(
+	redis *storage.RedisClient,
+	ledger *ChipLedger,
+	gameStateService *GameStateService,
+	actionLogger *ActionLogger,
+	interval time.Duration,
+) *LedgerReconciler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &LedgerReconciler{
+		redis:            redis,
+		ledger:           ledger,
+		gameStateService: gameStateService,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("LedgerReconciler"),
+		interval:         interval,
+		ctx:              ctx,
+		cancelFunc:       cancel,
+	}
+}
+
+// Start - запускает периодическую сверку (блокирующий вызов, запускать в горутине)
+func (lr *LedgerReconciler) Start() {
+	if lr.isRunning {
+		lr.logger.Warning("Сверка уже запущена")
+		return
+	}
+
+	lr.isRunning = true
+	lr.logger.Successf("Сверка журнала фишек запущена (интервал: %v)", lr.interval)
+
+	ticker := time.NewTicker(lr.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			lr.ReconcileAll()
+		case <-lr.ctx.Done():
+			lr.isRunning = false
+			return
+		}
+	}
+}
+
+// Stop - останавливает периодическую сверку
+func (lr *LedgerReconciler) Stop() {
+	lr.cancelFunc()
+	lr.isRunning = false
+}
+
+// IsRunning - возвращает статус работы сверки
+func (lr *LedgerReconciler) IsRunning() bool {
+	return lr.isRunning
+}
+
+// ReconcileAll - сверяет все активные комнаты всех клубов
+// Комнаты с идущей раздачей пропускаются: стеки и банк меняются во время торговли,
+// поэтому сверка выполняется между раздачами
+// Возвращает количество найденных расхождений
+func (lr *LedgerReconciler) ReconcileAll() int {
+	pattern := lr.redis.GetKeys().ClubRoomsActivePattern()
+	iter := lr.redis.Scan(pattern)
+	mismatchesCount := 0
+
+	for iter.Next(lr.ctx) {
+		clubID := lr.redis.GetKeys().ExtractClubID(iter.Val())
+		if clubID == "" {
+			continue
+		}
+
+		roomIDs, err := lr.redis.ZRange(iter.Val(), 0, -1)
+		if err != nil {
+			lr.logger.Errorf("Ошибка при получении комнат клуба %s: %v", clubID, err)
+			continue
+		}
+
+		for _, roomID := range roomIDs {
+			mismatchesCount += lr.reconcileRoom(clubID, roomID)
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		lr.logger.Errorf("Ошибка при сканировании клубов: %v", err)
+	}
+
+	return mismatchesCount
+}
+
+// reconcileRoom - сверяет одну комнату и сохраняет найденные расхождения
+func (lr *LedgerReconciler) reconcileRoom(clubID, roomID string) int {
+	isActive, err := lr.gameStateService.IsGameActive(clubID, roomID)
+	if err != nil || isActive {
+		return 0
+	}
+
+	mismatches, err := lr.ledger.ReconcileRoom(clubID, roomID)
+	if err != nil {
+		lr.logger.Errorf("Ошибка при сверке комнаты %s:%s: %v", clubID, roomID, err)
+		return 0
+	}
+
+	if len(mismatches) == 0 {
+		return 0
+	}
+
+	for _, mismatch := range mismatches {
+		lr.logger.Warningf("Расхождение в комнате %s:%s: счет %s, стол %d, журнал %d",
+			clubID, roomID, mismatch.Account, mismatch.Expected, mismatch.LedgerBalance)
+	}
+
+	if err := lr.ledger.RecordMismatches(clubID, mismatches); err != nil {
+		lr.logger.Errorf("Не удалось сохранить расхождения комнаты %s:%s: %v", clubID, roomID, err)
+	}
+	lr.actionLogger.LogError(clubID, roomID, "ledger_mismatch", "стеки игроков не совпадают с журналом фишек")
+
+	return len(mismatches)
+}
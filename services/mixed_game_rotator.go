@@ -0,0 +1,164 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// MixedGameRotator - сервис ротации разновидностей покера в смешанных играх (HORSE и т.п.)
+// В начале каждой раздачи отсчитывает раздачи текущей разновидности и при смене
+// записывает в комнату новую разновидность, структуру ставок и ставки стола.
+// Остальные сервисы читают разновидность из комнаты и не знают о ротации
+type MixedGameRotator struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewMixedGameRotator - создает новый экземпляр MixedGameRotator
+func NewMixedGameRotator(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	actionLogger *ActionLogger,
+) *MixedGameRotator {
+	return &MixedGameRotator{
+		redis:            redis,
+		gameStateService: gameStateService,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("MixedGameRotator"),
+	}
+}
+
+// StartHand - определяет разновидность покера для новой раздачи
+// Вызывается в начале раздачи до постановки блайндов и раздачи карт.
+// Возвращает nil, если комната не играет смешанную игру
+func (mr *MixedGameRotator) StartHand(clubID, roomID string) (*models.MixedGameState, error) {
+	room, err := mr.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+	if !room.IsMixedGame() {
+		return nil, nil
+	}
+
+	game, err := mr.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	state := &models.MixedGameState{
+		VariantIndex:       game.VariantIndex,
+		HandsUntilRotation: game.HandsUntilRotation - 1,
+	}
+
+	switch {
+	case game.CurrentVariant == "" || game.VariantIndex >= len(room.MixedGame):
+		// Первая раздача смешанной игры (или ротация изменилась) - начинаем сначала
+		state.VariantIndex = 0
+		state.Rotated = true
+	case game.HandsUntilRotation <= 1:
+		state.VariantIndex = (game.VariantIndex + 1) % len(room.MixedGame)
+		state.Rotated = true
+	}
+	state.Variant = room.MixedGame[state.VariantIndex]
+
+	if state.Rotated {
+		state.HandsUntilRotation, err = mr.rotationLength(clubID, roomID, room)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mr.saveState(clubID, roomID, state); err != nil {
+		return nil, err
+	}
+
+	if state.Rotated {
+		mr.actionLogger.LogVariantChanged(clubID, roomID, string(state.Variant.GameType),
+			string(state.Variant.Structure()), state.HandsUntilRotation)
+		mr.logger.Infof("Комната %s:%s переходит на %s (%s), раздач до смены: %d",
+			clubID, roomID, state.Variant.GameType, state.Variant.Structure(), state.HandsUntilRotation)
+	}
+
+	return state, nil
+}
+
+// rotationLength - количество раздач новой разновидности
+// При смене по кругам - по раздаче на каждого игрока, участвующего в игре
+func (mr *MixedGameRotator) rotationLength(clubID, roomID string, room *models.Room) (int, error) {
+	if room.RotationMode != models.RotationByOrbit {
+		return room.GetRotationHands(), nil
+	}
+
+	playerIDs, err := mr.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, userID := range playerIDs {
+		player, err := mr.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return 0, err
+		}
+		if player != nil && player.HasChips() && !player.IsSittingOut() {
+			count++
+		}
+	}
+
+	// Круг не может быть короче игры один на один
+	if count < 2 {
+		count = 2
+	}
+	return count, nil
+}
+
+// saveState - сохраняет положение ротации в состояние игры,
+// а при смене разновидности - новую разновидность и ставки в комнату
+func (mr *MixedGameRotator) saveState(clubID, roomID string, state *models.MixedGameState) error {
+	keys := mr.redis.GetKeys()
+	ctx := mr.redis.GetContext()
+	pipe := mr.redis.TxPipeline()
+
+	if state.Rotated {
+		variant := state.Variant
+		roomUpdates := map[string]interface{}{
+			"game_type":         string(variant.GameType),
+			"betting_structure": string(variant.Structure()),
+			"small_bet":         variant.SmallBet,
+			"big_bet":           variant.BigBet,
+			"bring_in":          variant.BringIn,
+		}
+		if variant.SmallBlind > 0 {
+			roomUpdates["small_blind"] = variant.SmallBlind
+		}
+		if variant.BigBlind > 0 {
+			roomUpdates["big_blind"] = variant.BigBlind
+		}
+		pipe.HSet(ctx, keys.RoomInfo(clubID, roomID), roomUpdates)
+	}
+
+	pipe.HSet(ctx, keys.GameState(clubID, roomID), map[string]interface{}{
+		"current_variant":      string(state.Variant.GameType),
+		"variant_index":        state.VariantIndex,
+		"hands_until_rotation": state.HandsUntilRotation,
+	})
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		mr.logger.Errorf("Ошибка при сохранении ротации в комнате %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("ошибка сохранения ротации смешанной игры: %w", err)
+	}
+
+	return nil
+}
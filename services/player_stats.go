@@ -0,0 +1,157 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// PlayerStatsService - сервис статистики игроков по клубу (VPIP, PFR, 3-бет, AF, WTSD, W$SD, выигрыш)
+// Действия игроков записываются в журнал текущей раздачи; в конце раздачи по журналу
+// и истории раздачи рассчитываются счетчики каждого участника и прибавляются
+// к накопленным в Redis. Рейтинг клуба ведется по чистому выигрышу
+type PlayerStatsService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewPlayerStatsService - создает новый экземпляр PlayerStatsService
+func NewPlayerStatsService(redis *storage.RedisClient) *PlayerStatsService {
+	return &PlayerStatsService{
+		redis:  redis,
+		logger: utils.NewLogger("PlayerStats"),
+	}
+}
+
+// RecordAction - записывает действие игрока в журнал текущей раздачи
+// Вызывается после применения каждого действия (BettingService.ApplyAction) и после постановки
+// блайндов, стрэддлов и bring-in (BlindPoster, действие blind)
+func (ps *PlayerStatsService) RecordAction(clubID, roomID string, action *models.HandAction) error {
+	actionJSON, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации действия раздачи: %w", err)
+	}
+
+	if err := ps.redis.RPush(ps.redis.GetKeys().RoomHandActions(clubID, roomID), string(actionJSON)); err != nil {
+		ps.logger.Errorf("Ошибка при записи действия игрока %s в комнате %s:%s: %v", action.UserID, clubID, roomID, err)
+		return err
+	}
+
+	return nil
+}
+
+// HandFinished - обновляет статистику участников завершенной раздачи
+// Вызывается в конце раздачи до сохранения истории: действия раздачи переносятся в историю,
+// а журнал действий очищается. Турнирные фишки не деньги: за турнирным столом обновляются
+// только счетчики игры, а чистый выигрыш и рейтинг клуба не меняются
+func (ps *PlayerStatsService) HandFinished(history *models.HandHistory) error {
+	clubID, roomID := history.ClubID, history.RoomID
+	actionsKey := ps.redis.GetKeys().RoomHandActions(clubID, roomID)
+
+	data, err := ps.redis.LRange(actionsKey, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	actions := make([]models.HandAction, 0, len(data))
+	for _, actionJSON := range data {
+		var action models.HandAction
+		if err := json.Unmarshal([]byte(actionJSON), &action); err != nil {
+			ps.logger.Warningf("Ошибка при парсинге действия раздачи в комнате %s:%s: %v", clubID, roomID, err)
+			continue
+		}
+		if action.GameID != history.GameID {
+			continue
+		}
+		actions = append(actions, action)
+	}
+	history.Actions = actions
+
+	keys := ps.redis.GetKeys()
+	ctx := ps.redis.GetContext()
+	pipe := ps.redis.TxPipeline()
+	isTournament := history.TournamentID != ""
+	for userID, stats := range models.ComputeHandStats(history, actions) {
+		if isTournament {
+			stats.Net = 0
+		}
+
+		statsKey := keys.UserStats(clubID, userID)
+		for field, value := range stats.ToRedisHash() {
+			if value != 0 {
+				pipe.HIncrBy(ctx, statsKey, field, int64(value))
+			}
+		}
+		if !isTournament {
+			pipe.ZIncrBy(ctx, keys.ClubStatsLeaderboard(clubID), float64(stats.Net), userID)
+		}
+	}
+	pipe.Del(ctx, actionsKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		ps.logger.Errorf("Ошибка при обновлении статистики игроков в комнате %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("ошибка обновления статистики игроков: %w", err)
+	}
+
+	ps.logger.Debugf("Раздача %s: статистика %d игроков обновлена (действий: %d)",
+		history.GameID, len(history.Players), len(actions))
+	return nil
+}
+
+// GetStats - статистика игрока в клубе
+func (ps *PlayerStatsService) GetStats(clubID, userID string) (*models.PlayerStatsReport, error) {
+	data, err := ps.redis.HGetAll(ps.redis.GetKeys().UserStats(clubID, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewPlayerStatsFromRedis(userID, data).Report(), nil
+}
+
+// GetLeaderboard - игроки клуба с наибольшим чистым выигрышем и их статистика
+// limit - размер рейтинга (0 - models.DefaultLeaderboardSize)
+func (ps *PlayerStatsService) GetLeaderboard(clubID string, limit int) ([]*models.PlayerStatsReport, error) {
+	if limit <= 0 {
+		limit = models.DefaultLeaderboardSize
+	}
+	if limit > models.MaxLeaderboardSize {
+		return nil, ErrInvalidLeaderboardSize
+	}
+
+	top, err := ps.redis.ZRevRangeWithScores(ps.redis.GetKeys().ClubStatsLeaderboard(clubID), 0, int64(limit-1))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*models.PlayerStatsReport, 0, len(top))
+	for _, entry := range top {
+		userID, ok := entry.Member.(string)
+		if !ok {
+			continue
+		}
+		report, err := ps.GetStats(clubID, userID)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+var (
+	ErrInvalidLeaderboardSize = &PlayerStatsError{message: "invalid leaderboard size"}
+)
+
+type PlayerStatsError struct {
+	message string
+}
+
+func (e *PlayerStatsError) Error() string {
+	return "player stats error: " + e.message
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PotManager - сервис для работы с банками раздачи (основной и боковые банки)
+// Отвечает за построение банков по ставкам игроков, взятие рейка и присуждение банков
+type PotManager struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// rakeService - сервис расчета рейка
+	rakeService *RakeService
+
+	// ledger - журнал движения фишек
+	ledger *ChipLedger
+
+	// buyInManager - сервис покупки фишек (зачисление отложенных докупок)
+	buyInManager *BuyInManager
+
+	// seatManager - сервис управления местами (отложенные пересадки)
+	seatManager *SeatManager
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewPotManager - создает новый экземпляр PotManager
+func NewPotManager(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	rakeService *RakeService,
+	ledger *ChipLedger,
+	buyInManager *BuyInManager,
+	seatManager *SeatManager,
+	actionLogger *ActionLogger,
+) *PotManager {
+	return &PotManager{
+		redis:            redis,
+		gameStateService: gameStateService,
+		rakeService:      rakeService,
+		ledger:           ledger,
+		buyInManager:     buyInManager,
+		seatManager:      seatManager,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("PotManager"),
+	}
+}
+
+// CollectPots - строит банки по вкладам игроков за раздачу и сохраняет их в Redis
+// Вызывается в конце каждого раунда торговли и перед вскрытием
+func (pm *PotManager) CollectPots(clubID, roomID string) ([]models.SidePot, error) {
+	playerIDs, err := pm.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить список игроков: %w", err)
+	}
+
+	contributions := make([]models.PotContribution, 0, len(playerIDs))
+	for _, userID := range playerIDs {
+		player, err := pm.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось получить данные игрока %s: %w", userID, err)
+		}
+		if player == nil || player.TotalBet == 0 {
+			continue
+		}
+
+		// Анте за весь стол - мертвые фишки основного банка, а не вклад игрока
+		contributions = append(contributions, models.PotContribution{
+			UserID:    player.UserID,
+			Amount:    player.TotalBet - player.TableAnte,
+			DeadMoney: player.TableAnte,
+			Folded:    player.IsFolded(),
+		})
+	}
+
+	pots := models.BuildPots(contributions)
+
+	if err := pm.SavePots(clubID, roomID, pots); err != nil {
+		return nil, err
+	}
+
+	return pots, nil
+}
+
+// GetPots - получает банки раздачи из Redis
+// Первый элемент - основной банк, остальные - боковые
+func (pm *PotManager) GetPots(clubID, roomID string) ([]models.SidePot, error) {
+	potsKey := pm.redis.GetKeys().RoomPots(clubID, roomID)
+
+	data, err := pm.redis.HGetAll(potsKey)
+	if err != nil {
+		pm.logger.Errorf("Ошибка при получении банков комнаты %s:%s: %v", clubID, roomID, err)
+		return nil, err
+	}
+
+	mainAmount, _ := strconv.Atoi(data["main_pot"])
+
+	mainPot := models.SidePot{Amount: mainAmount, EligiblePlayers: []string{}}
+	if data["main_pot_players"] != "" {
+		json.Unmarshal([]byte(data["main_pot_players"]), &mainPot.EligiblePlayers)
+	}
+
+	var sidePots []models.SidePot
+	if data["side_pots"] != "" {
+		if err := json.Unmarshal([]byte(data["side_pots"]), &sidePots); err != nil {
+			return nil, fmt.Errorf("ошибка при парсинге боковых банков: %w", err)
+		}
+	}
+
+	// Банки удалены или сохранены пустыми после окончания раздачи
+	if mainAmount == 0 && len(mainPot.EligiblePlayers) == 0 && len(sidePots) == 0 {
+		return []models.SidePot{}, nil
+	}
+
+	return append([]models.SidePot{mainPot}, sidePots...), nil
+}
+
+// SavePots - сохраняет банки раздачи в Redis и обновляет общий банк в состоянии игры
+func (pm *PotManager) SavePots(clubID, roomID string, pots []models.SidePot) error {
+	pipe := pm.redis.TxPipeline()
+	pm.appendSavePots(pipe, clubID, roomID, pots)
+
+	if _, err := pipe.Exec(pm.redis.GetContext()); err != nil {
+		pm.logger.Errorf("Ошибка при сохранении банков комнаты %s:%s: %v", clubID, roomID, err)
+		return fmt.Errorf("ошибка сохранения банков: %w", err)
+	}
+
+	return nil
+}
+
+// appendSavePots - добавляет в pipeline сохранение банков раздачи и общего банка в состоянии игры
+// Без банков ключ банков удаляется, чтобы GetPots не возвращал пустой основной банк
+func (pm *PotManager) appendSavePots(pipe redis.Pipeliner, clubID, roomID string, pots []models.SidePot) {
+	ctx := pm.redis.GetContext()
+	keys := pm.redis.GetKeys()
+
+	pipe.HSet(ctx, keys.GameState(clubID, roomID), "pot", models.GetPotsTotal(pots))
+	if len(pots) == 0 {
+		pipe.Del(ctx, keys.RoomPots(clubID, roomID))
+		return
+	}
+
+	mainPlayersJSON, _ := json.Marshal(pots[0].EligiblePlayers)
+	sidePotsJSON, _ := json.Marshal(pots[1:])
+
+	pipe.HSet(ctx, keys.RoomPots(clubID, roomID), map[string]interface{}{
+		"main_pot":         pots[0].Amount,
+		"main_pot_players": string(mainPlayersJSON),
+		"side_pots":        string(sidePotsJSON),
+	})
+}
+
+// AwardPots - присуждает банки победителям и добавляет начисления фишек в pipeline вызывающего кода
+// Каждое начисление записывается в журнал фишек: выигрыш банка или возврат
+// неуравненной ставки (банк с единственным претендентом)
+// Параметры:
+//   - pipe: транзакционный pipeline (TxPipeline), выполняется вызывающим кодом
+//   - reference: ссылка на раздачу для журнала (models.HandReference)
+//   - pots: банки раздачи (уже за вычетом рейка)
+//   - winners: победители каждого банка (winners[i] для pots[i]) в порядке мест от дилера.
+//     Если победители банка не указаны, а претендент один - банк возвращается ему
+//
+// Возвращает итог розыгрыша каждого банка и общий выигрыш каждого игрока
+func (pm *PotManager) AwardPots(pipe redis.Pipeliner, clubID, roomID, reference string, pots []models.SidePot, winners []models.PotWinners) ([]models.PotSettlement, map[string]int, error) {
+	settlements := make([]models.PotSettlement, 0, len(pots))
+	payouts := make(map[string]int)
+
+	for i, pot := range pots {
+		var potWinners models.PotWinners
+		if i < len(winners) {
+			potWinners = winners[i]
+		}
+
+		potWinners, ok := resolvePotWinners(pot, potWinners)
+		if !ok {
+			return nil, nil, fmt.Errorf("не указаны победители банка #%d", i)
+		}
+
+		highShares, lowShares := models.SplitHiLo(pot.Amount, potWinners.High, potWinners.Low)
+
+		shares := make(map[string]int, len(highShares)+len(lowShares))
+		for userID, share := range highShares {
+			shares[userID] += share
+		}
+		for userID, share := range lowShares {
+			shares[userID] += share
+		}
+
+		for userID, share := range shares {
+			var err error
+			if pot.IsContested() {
+				err = pm.ledger.AppendPotWin(pipe, clubID, roomID, userID, share, reference)
+			} else {
+				err = pm.ledger.AppendRefund(pipe, clubID, roomID, userID, share, reference)
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("не удалось начислить выигрыш игроку %s: %w", userID, err)
+			}
+
+			payouts[userID] += share
+		}
+
+		settlements = append(settlements, models.PotSettlement{
+			Amount:          pot.Amount,
+			EligiblePlayers: pot.EligiblePlayers,
+			HighWinners:     potWinners.High,
+			LowWinners:      potWinners.Low,
+			HighShares:      highShares,
+			LowShares:       lowShares,
+		})
+	}
+
+	return settlements, payouts, nil
+}
+
+// SettleHand - завершает раздачу: строит банки, берет рейк и присуждает банки
+// Параметры:
+//   - winners: победители каждого банка (см. AwardPots)
+//
+// Возвращает итог розыгрыша банков раздачи
+func (pm *PotManager) SettleHand(clubID, roomID string, winners []models.PotWinners) (*models.HandSettlement, error) {
+	return pm.SettleHandRuns(clubID, roomID, [][]models.PotWinners{winners})
+}
+
+// SettleHandRuns - завершает раздачу, в которой доска прогонялась несколько раз
+// Каждый банк после рейка делится на равные доли прогонов (нечетные фишки - первым прогонам),
+// и доля каждого прогона присуждается отдельно победителям на его доске.
+// Рейк, все выигрыши, очистка банков и сброс ставок игроков записываются одной транзакцией:
+// после нее вкладов в банк не остается, и повторный расчет раздачи не берет рейк и не платит дважды.
+// Параметры:
+//   - runWinners: победители каждого банка для каждого прогона (runWinners[r][i] - банк i на доске r)
+//
+// Возвращает итог розыгрыша банков: сначала банки первого прогона, затем второго и т.д.
+func (pm *PotManager) SettleHandRuns(clubID, roomID string, runWinners [][]models.PotWinners) (*models.HandSettlement, error) {
+	if len(runWinners) == 0 {
+		return nil, fmt.Errorf("не указаны победители ни одного прогона")
+	}
+
+	room, err := pm.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+	room.ClubID, room.RoomID = clubID, roomID
+
+	game, err := pm.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	pots, err := pm.CollectPots(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	playersDealt, err := pm.countPlayersDealt(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := pm.redis.TxPipeline()
+	ctx := pm.redis.GetContext()
+
+	// Рейк берется с каждого банка до присуждения
+	rakedPots, rakeRecord, err := pm.rakeService.TakeRake(pipe, room, game, pots, playersDealt)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := models.HandReference(game.GameID, game.RoundNumber)
+	settlement := &models.HandSettlement{Payouts: make(map[string]int)}
+
+	for run, runPots := range splitPotsByRuns(rakedPots, len(runWinners)) {
+		potSettlements, payouts, err := pm.AwardPots(pipe, clubID, roomID, reference, runPots, runWinners[run])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(runWinners) > 1 {
+			for i := range potSettlements {
+				potSettlements[i].Run = run + 1
+			}
+		}
+
+		// Рейк банка взят один раз до деления на прогоны - он записывается
+		// к банку первого прогона с тем же номером
+		if rakeRecord != nil && run == 0 {
+			for i := range potSettlements {
+				if i < len(rakeRecord.PotRakes) {
+					potSettlements[i].Rake = rakeRecord.PotRakes[i]
+				}
+			}
+		}
+
+		settlement.Pots = append(settlement.Pots, potSettlements...)
+		for userID, amount := range payouts {
+			settlement.Payouts[userID] += amount
+		}
+	}
+
+	if rakeRecord != nil {
+		settlement.Rake = rakeRecord.Amount
+	}
+
+	// Раздача окончена - удаляем банки и обнуляем ставки игроков
+	pm.appendSavePots(pipe, clubID, roomID, nil)
+	if err := pm.appendResetPlayerBets(pipe, clubID, roomID); err != nil {
+		return nil, err
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		pm.logger.Errorf("Ошибка при расчете банков раздачи #%d в комнате %s:%s: %v", game.RoundNumber, clubID, roomID, err)
+		return nil, fmt.Errorf("ошибка расчета банков раздачи: %w", err)
+	}
+
+	if rakeRecord != nil {
+		pm.rakeService.LogRake(rakeRecord)
+	}
+	for userID, amount := range settlement.Payouts {
+		pm.actionLogger.LogPotAwarded(clubID, roomID, userID, amount)
+	}
+
+	// Зачисляем фишки, докупленные во время раздачи
+	if err := pm.buyInManager.ApplyPendingChips(clubID, roomID); err != nil {
+		pm.logger.Warningf("Не удалось зачислить отложенные фишки в комнате %s:%s: %v", clubID, roomID, err)
+	}
+
+	// Выполняем пересадки, запрошенные во время раздачи
+	if err := pm.seatManager.ProcessSeatChanges(clubID, roomID); err != nil {
+		pm.logger.Warningf("Не удалось выполнить пересадки в комнате %s:%s: %v", clubID, roomID, err)
+	}
+
+	pm.actionLogger.LogRoundFinished(clubID, roomID, game.RoundNumber, models.GetPotsTotal(pots))
+	pm.logger.Infof("Раздача #%d в комнате %s:%s завершена (банк: %d)",
+		game.RoundNumber, clubID, roomID, models.GetPotsTotal(pots))
+
+	return settlement, nil
+}
+
+// resolvePotWinners - победители банка: указанные или, если они не указаны,
+// единственный претендент (неуравненная ставка или раздача, выигранная сбросами)
+// Возвращает false, если победители не указаны, а претендентов несколько
+func resolvePotWinners(pot models.SidePot, winners models.PotWinners) (models.PotWinners, bool) {
+	if len(winners.High) > 0 {
+		return winners, true
+	}
+	if len(pot.EligiblePlayers) != 1 {
+		return winners, false
+	}
+	return models.PotWinners{High: pot.EligiblePlayers}, true
+}
+
+// splitPotsByRuns - делит каждый банк на доли прогонов доски
+// Возвращает банки каждого прогона с теми же претендентами
+func splitPotsByRuns(pots []models.SidePot, runs int) [][]models.SidePot {
+	runPots := make([][]models.SidePot, runs)
+	for run := range runPots {
+		runPots[run] = make([]models.SidePot, len(pots))
+	}
+
+	for i, pot := range pots {
+		for run, share := range models.SplitRuns(pot.Amount, runs) {
+			runPots[run][i] = models.SidePot{Amount: share, EligiblePlayers: pot.EligiblePlayers}
+		}
+	}
+
+	return runPots
+}
+
+// countPlayersDealt - считает игроков, получивших карты в текущей раздаче
+func (pm *PotManager) countPlayersDealt(clubID, roomID string) (int, error) {
+	playerIDs, err := pm.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, userID := range playerIDs {
+		player, err := pm.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return 0, err
+		}
+		if player != nil && player.HasCards() {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// appendResetPlayerBets - добавляет в pipeline обнуление ставок игроков после завершения раздачи
+func (pm *PotManager) appendResetPlayerBets(pipe redis.Pipeliner, clubID, roomID string) error {
+	playerIDs, err := pm.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return fmt.Errorf("не удалось получить список игроков для сброса ставок: %w", err)
+	}
+
+	ctx := pm.redis.GetContext()
+	for _, userID := range playerIDs {
+		pipe.HSet(ctx, pm.redis.GetKeys().PlayerInfo(clubID, roomID, userID), map[string]interface{}{
+			"bet":        0,
+			"total_bet":  0,
+			"table_ante": 0,
+			"straddle":   0,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RakeService - сервис для расчета и учета рейка
+type RakeService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// ledger - журнал движения фишек
+	ledger *ChipLedger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewRakeService - создает новый экземпляр RakeService
+func NewRakeService(redis *storage.RedisClient, actionLogger *ActionLogger, ledger *ChipLedger) *RakeService {
+	return &RakeService{
+		redis:        redis,
+		actionLogger: actionLogger,
+		ledger:       ledger,
+		logger:       utils.NewLogger("RakeService"),
+	}
+}
+
+// TakeRake - берет рейк с банков раздачи (вызывается в конце раздачи, до присуждения банков)
+// Запись о рейке добавляется в pipeline вызывающего кода: рейк и выигрыши раздачи
+// записываются одной транзакцией, поэтому повторный расчет раздачи не берет рейк дважды.
+// После выполнения pipeline вызывающий код записывает рейк в лог через LogRake
+// Параметры:
+//   - pipe: транзакционный pipeline (TxPipeline), выполняется вызывающим кодом
+//   - room: комната с настройками рейка
+//   - game: текущая игра (нужны game_id, номер раздачи и общие карты)
+//   - pots: банки раздачи (основной + боковые)
+//   - playersDealt: количество игроков, получивших карты
+//
+// Возвращает банки за вычетом рейка и запись о рейке (nil если рейк не взят)
+func (rs *RakeService) TakeRake(pipe redis.Pipeliner, room *models.Room, game *models.Game, pots []models.SidePot, playersDealt int) ([]models.SidePot, *models.RakeRecord, error) {
+	// В стаде и рэззе общих карт нет - вместо флопа правило no flop, no drop смотрит на четвертую улицу
+	flopDealt := game.GetCommunityCardsCount() >= 3
+	if room.GameType.IsStud() {
+		flopDealt = game.IsPastThirdStreet()
+	}
+
+	// Считаем рейк только с банков, на которые претендует больше одного игрока
+	rakeablePot := models.GetRakeablePot(pots)
+	rake := room.CalculateRake(rakeablePot, playersDealt, flopDealt)
+	if rake == 0 {
+		return pots, nil, nil
+	}
+
+	// Распределяем рейк по банкам и вычитаем его
+	potRakes := models.DistributeRake(pots, rake)
+	rakedPots := make([]models.SidePot, len(pots))
+	for i, pot := range pots {
+		rakedPots[i] = models.SidePot{
+			Amount:          pot.Amount - potRakes[i],
+			EligiblePlayers: pot.EligiblePlayers,
+		}
+	}
+
+	record := &models.RakeRecord{
+		ClubID:       room.ClubID,
+		RoomID:       room.RoomID,
+		GameID:       game.GameID,
+		RoundNumber:  game.RoundNumber,
+		PlayersDealt: playersDealt,
+		TotalPot:     models.GetPotsTotal(pots),
+		Amount:       rake,
+		PotRakes:     potRakes,
+		Timestamp:    utils.GetCurrentTimestamp(),
+	}
+
+	if err := rs.appendRake(pipe, record); err != nil {
+		return nil, nil, err
+	}
+
+	return rakedPots, record, nil
+}
+
+// LogRake - записывает взятый рейк в журнал действий и лог (после выполнения транзакции)
+func (rs *RakeService) LogRake(record *models.RakeRecord) {
+	rs.actionLogger.LogRakeTaken(record.ClubID, record.RoomID, record.GameID, record.Amount, record.TotalPot)
+	rs.logger.Infof("Рейк %d взят с раздачи #%d в комнате %s:%s (банк: %d)",
+		record.Amount, record.RoundNumber, record.ClubID, record.RoomID, record.TotalPot)
+}
+
+// appendRake - добавляет в pipeline запись о рейке в историю, счетчики клуба и журнал фишек
+func (rs *RakeService) appendRake(pipe redis.Pipeliner, record *models.RakeRecord) error {
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации записи о рейке: %w", err)
+	}
+
+	keys := rs.redis.GetKeys()
+	totalsKey := keys.ClubRakeTotals(record.ClubID)
+	ctx := rs.redis.GetContext()
+
+	// История, счетчики и журнал фишек обновляются в одной транзакции
+	pipe.RPush(ctx, keys.ClubRakeHistory(record.ClubID), string(recordJSON))
+	pipe.HIncrBy(ctx, totalsKey, "total", int64(record.Amount))
+	pipe.HIncrBy(ctx, totalsKey, "hands", 1)
+	pipe.HIncrBy(ctx, totalsKey, "room:"+record.RoomID, int64(record.Amount))
+
+	reference := models.HandReference(record.GameID, record.RoundNumber)
+	ledgerEntry := rs.ledger.NewRakeEntry(record.ClubID, record.RoomID, record.Amount, reference)
+	return rs.ledger.Append(pipe, ledgerEntry)
+}
+
+// GetClubRakeTotal - возвращает общий рейк клуба
+func (rs *RakeService) GetClubRakeTotal(clubID string) (int, error) {
+	value, err := rs.redis.HGet(rs.redis.GetKeys().ClubRakeTotals(clubID), "total")
+	if err != nil {
+		return 0, err
+	}
+	total, _ := strconv.Atoi(value)
+	return total, nil
+}
+
+// GetRoomRakeTotal - возвращает общий рейк, собранный в комнате
+func (rs *RakeService) GetRoomRakeTotal(clubID, roomID string) (int, error) {
+	value, err := rs.redis.HGet(rs.redis.GetKeys().ClubRakeTotals(clubID), "room:"+roomID)
+	if err != nil {
+		return 0, err
+	}
+	total, _ := strconv.Atoi(value)
+	return total, nil
+}
+
+// GetRakeHistory - возвращает последние N записей о рейке клуба
+func (rs *RakeService) GetRakeHistory(clubID string, count int64) ([]models.RakeRecord, error) {
+	if count <= 0 {
+		count = 100
+	}
+
+	jsonStrings, err := rs.redis.LRange(rs.redis.GetKeys().ClubRakeHistory(clubID), -count, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.RakeRecord, 0, len(jsonStrings))
+	for _, jsonStr := range jsonStrings {
+		var record models.RakeRecord
+		if err := json.Unmarshal([]byte(jsonStr), &record); err != nil {
+			rs.logger.Warningf("Ошибка при парсинге записи о рейке: %v", err)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"poker-engine/config"
+	"poker-engine/models"
 	"poker-engine/storage"
 	"poker-engine/utils"
 )
@@ -17,6 +18,8 @@ type RoomMonitor struct {
 	ctx              context.Context
 	cancelFunc       context.CancelFunc
 	gameStateService *GameStateService
+	blindClock       *BlindClockService
+	mixedGameRotator *MixedGameRotator
 	actionLogger     *ActionLogger
 	deckManager      *DeckManager
 	cardDealer       *CardDealer
@@ -29,6 +32,8 @@ func NewRoomMonitor(
 	redis *storage.RedisClient,
 	cfg *config.EngineConfig,
 	gameStateService *GameStateService,
+	blindClock *BlindClockService,
+	mixedGameRotator *MixedGameRotator,
 	actionLogger *ActionLogger,
 ) *RoomMonitor {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -40,6 +45,8 @@ func NewRoomMonitor(
 		ctx:              ctx,
 		cancelFunc:       cancel,
 		gameStateService: gameStateService,
+		blindClock:       blindClock,
+		mixedGameRotator: mixedGameRotator,
 		actionLogger:     actionLogger,
 		isRunning:        false,
 	}
@@ -156,6 +163,9 @@ func (rm *RoomMonitor) checkRoom(clubID, roomID string) {
 
 	// СЛУЧАЙ 1: Достаточно игроков (≥2) и игра не началась -> ЗАПУСКАЕМ
 	if playersCount >= int64(rm.config.MinPlayersToStart) && currentPhase == "waiting" {
+		if !rm.canStartHands(clubID, roomID) {
+			return
+		}
 		rm.logger.Debugf("Комната %s:%s готова к запуску (игроков: %d)", clubID, roomID, playersCount)
 		err := rm.handleGameStart(clubID, roomID, int(playersCount))
 		if err != nil {
@@ -180,10 +190,65 @@ func (rm *RoomMonitor) checkRoom(clubID, roomID string) {
 	}
 }
 
+// canStartHands - проверяет, можно ли начинать раздачи в комнате
+// Турнирная комната ждет старта турнира и остальные столы при игре рука в руку;
+// во время перерыва в расписании блайндов раздачи не начинаются
+func (rm *RoomMonitor) canStartHands(clubID, roomID string) bool {
+	keys := rm.redis.GetKeys()
+
+	room, err := rm.gameStateService.GetRoomInfo(clubID, roomID)
+	if err == nil && room != nil && room.IsTournament() {
+		status, err := rm.redis.HGet(keys.Tournament(clubID, room.TournamentID), "status")
+		if err != nil {
+			rm.logger.Errorf("Ошибка при получении статуса турнира %s: %v", room.TournamentID, err)
+			return false
+		}
+		if models.TournamentStatus(status) != models.TournamentStatusRunning {
+			return false
+		}
+
+		// Рука в руку: стол, доигравший раздачу, ждет остальные столы
+		handForHand, _ := rm.redis.HGet(keys.Tournament(clubID, room.TournamentID), "hand_for_hand")
+		if handForHand == "true" || handForHand == "1" {
+			waiting, err := rm.redis.SIsMember(keys.TournamentHandForHand(clubID, room.TournamentID), roomID)
+			if err != nil || waiting {
+				return false
+			}
+		}
+	}
+
+	data, err := rm.redis.HGetAll(keys.RoomBlindClock(clubID, roomID))
+	if err != nil || len(data) == 0 {
+		return true
+	}
+	clock, err := models.NewBlindClockFromRedis(data)
+	if err != nil {
+		return true
+	}
+	clock.Advance(utils.GetCurrentTimestampMillis())
+
+	return !clock.IsOnBreak()
+}
+
 // handleGameStart - внутренняя логика запуска игры
+// Перед раздачей часы блайндов переходят на текущий уровень, а смешанная игра -
+// на разновидность этой раздачи
 func (rm *RoomMonitor) handleGameStart(clubID, roomID string, playersCount int) error {
 	rm.logger.Infof("Запуск игры в комнате %s:%s", clubID, roomID)
 
+	onBreak, err := rm.blindClock.StartHand(clubID, roomID)
+	if err != nil {
+		return err
+	}
+	if onBreak {
+		rm.logger.Infof("В комнате %s:%s перерыв, раздача не начинается", clubID, roomID)
+		return nil
+	}
+
+	if _, err := rm.mixedGameRotator.StartHand(clubID, roomID); err != nil {
+		return err
+	}
+
 	gameID := utils.GetCurrentTime().Format("20060102150405") + "_" + clubID + "_" + roomID
 	startedAt := utils.GetISO8601Time()
 
@@ -198,8 +263,10 @@ func (rm *RoomMonitor) handleGameStart(clubID, roomID string, playersCount int)
 	pipe.HSet(ctx, gameStateKey, "started_at", startedAt)
 	pipe.HSet(ctx, gameStateKey, "pot", 0)
 	pipe.HSet(ctx, gameStateKey, "current_bet", 0)
+	pipe.HSet(ctx, gameStateKey, "last_raise", 0)
+	pipe.HSet(ctx, gameStateKey, "raise_count", 0)
 
-	_, err := pipe.Exec(ctx)
+	_, err = pipe.Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -225,7 +292,12 @@ func (rm *RoomMonitor) handleGameStop(clubID, roomID, previousPhase, reason stri
 	pipe.HSet(ctx, gameStateKey, "started_at", "")
 	pipe.HSet(ctx, gameStateKey, "pot", 0)
 	pipe.HSet(ctx, gameStateKey, "current_bet", 0)
+	pipe.HSet(ctx, gameStateKey, "last_raise", 0)
+	pipe.HSet(ctx, gameStateKey, "raise_count", 0)
 	pipe.HSet(ctx, gameStateKey, "community_cards", "[]")
+	pipe.HSet(ctx, gameStateKey, "boards", "[]")
+	pipe.HSet(ctx, gameStateKey, "run_count", 0)
+	pipe.HSet(ctx, gameStateKey, "bomb_pot", false)
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// runItOfferField - поле хэша предложения с JSON участников и срока ответа
+// Остальные поля хэша - выбор игроков (userId -> количество прогонов)
+const runItOfferField = "offer"
+
+// RunItService - сервис нескольких прогонов доски (run it twice / three times)
+// Когда все оставшиеся игроки в олл-ине до ривера, им предлагается прогнать
+// оставшуюся доску несколько раз. На ответ дается offerTimeout; доска прогоняется
+// столько раз, сколько выбрал самый осторожный игрок (не ответивший - один раз).
+// Согласованное количество прогонов записывается в состояние игры, после чего
+// CardDealer раздает карты на каждую доску, а PotManager делит банки между прогонами
+type RunItService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// deckManager - менеджер колоды (остаток колоды ограничивает количество прогонов)
+	deckManager *DeckManager
+
+	// allInEV - сервис учета эквити олл-инов (эквити фиксируется, когда предлагаются прогоны)
+	allInEV *AllInEVService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+
+	// offerTimeout - время на ответ о прогонах доски
+	offerTimeout time.Duration
+}
+
+// NewRunItService - создает новый экземпляр RunItService
+func NewRunItService(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	deckManager *DeckManager,
+	allInEV *AllInEVService,
+	actionLogger *ActionLogger,
+	offerTimeout time.Duration,
+) *RunItService {
+	return &RunItService{
+		redis:            redis,
+		gameStateService: gameStateService,
+		deckManager:      deckManager,
+		allInEV:          allInEV,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("RunIt"),
+		offerTimeout:     offerTimeout,
+	}
+}
+
+// Offer - предлагает игрокам в олл-ине прогнать оставшуюся доску несколько раз
+// Вызывается, когда торговля закончена и все оставшиеся игроки (кроме, возможно, одного,
+// уравнявшего ставку) в олл-ине, а ривер еще не открыт
+func (rs *RunItService) Offer(clubID, roomID string) (*models.RunItOffer, error) {
+	existing, err := rs.GetOffer(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrRunItAlreadyOffered
+	}
+
+	room, err := rs.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+	if !room.AllowsRunItMultiple() || room.GameType.IsStud() {
+		return nil, ErrRunItNotAllowed
+	}
+
+	game, err := rs.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+	if !game.IsActive() || game.GetRunCount() > 1 {
+		return nil, ErrRunItNotAllowed
+	}
+
+	missing := models.MaxCommunityCards - len(game.CommunityCards)
+	if missing <= 0 {
+		return nil, ErrRunItBoardComplete
+	}
+
+	players, err := allInPlayers(rs.gameStateService, clubID, roomID, game)
+	if err != nil {
+		return nil, err
+	}
+
+	// Эквити фиксируется до прогонов: ожидаемый выигрыш не зависит от их количества
+	if _, err := rs.allInEV.Capture(clubID, roomID); err != nil {
+		rs.logger.Warningf("Не удалось зафиксировать эквити олл-ина в комнате %s:%s: %v", clubID, roomID, err)
+	}
+
+	// Каждый прогон требует своих карт - прогонов не больше, чем позволяет колода
+	deckSize, err := rs.deckManager.GetDeckSize(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	maxRuns := room.GetMaxRuns()
+	if byDeck := int(deckSize) / missing; byDeck < maxRuns {
+		maxRuns = byDeck
+	}
+	if maxRuns < 2 {
+		return nil, ErrRunItNotAllowed
+	}
+
+	offer := &models.RunItOffer{
+		Players:   players,
+		MaxRuns:   maxRuns,
+		ExpiresAt: utils.GetCurrentTimestampMillis() + rs.offerTimeout.Milliseconds(),
+	}
+
+	offerJSON, err := json.Marshal(offer)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при сериализации предложения прогонов: %w", err)
+	}
+
+	offerKey := rs.redis.GetKeys().RoomRunItOffer(clubID, roomID)
+	if err := rs.redis.HSet(offerKey, runItOfferField, string(offerJSON)); err != nil {
+		rs.logger.Errorf("Ошибка при сохранении предложения прогонов в комнате %s:%s: %v", clubID, roomID, err)
+		return nil, fmt.Errorf("ошибка сохранения предложения прогонов: %w", err)
+	}
+
+	rs.actionLogger.LogRunItOffered(clubID, roomID, players, maxRuns, offer.ExpiresAt)
+	rs.logger.Infof("В комнате %s:%s предложено прогнать доску до %d раз (игроков: %d)",
+		clubID, roomID, maxRuns, len(players))
+
+	return offer, nil
+}
+
+// Respond - игрок выбирает, сколько раз прогнать доску (1 - отказ от нескольких прогонов)
+// Возвращает предложение с учетом выбора игрока
+func (rs *RunItService) Respond(clubID, roomID, userID string, runs int) (*models.RunItOffer, error) {
+	offer, err := rs.GetOffer(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if offer == nil {
+		return nil, ErrNoRunItOffer
+	}
+	if offer.IsExpired(utils.GetCurrentTimestampMillis()) {
+		return nil, ErrRunItOfferExpired
+	}
+	if !offer.IsParticipant(userID) {
+		return nil, ErrNotInRunItOffer
+	}
+	if runs < 1 || runs > offer.MaxRuns {
+		return nil, ErrInvalidRunCount
+	}
+
+	if err := rs.redis.HSet(rs.redis.GetKeys().RoomRunItOffer(clubID, roomID), userID, runs); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения выбора прогонов: %w", err)
+	}
+	offer.Choices[userID] = runs
+
+	rs.actionLogger.LogRunItChosen(clubID, roomID, userID, runs)
+	rs.logger.Infof("Игрок %s в комнате %s:%s выбрал прогонов: %d", userID, clubID, roomID, runs)
+
+	return offer, nil
+}
+
+// Resolve - подводит итог предложения, если все ответили или срок ответа истек
+// Согласованное количество прогонов записывается в состояние игры, предложение удаляется.
+// Возвращает количество прогонов и признак того, что решение принято
+// (false - игроки еще думают). Без предложения доска прогоняется один раз
+func (rs *RunItService) Resolve(clubID, roomID string) (int, bool, error) {
+	offer, err := rs.GetOffer(clubID, roomID)
+	if err != nil {
+		return 0, false, err
+	}
+	if offer == nil {
+		return 1, true, nil
+	}
+	if !offer.AllResponded() && !offer.IsExpired(utils.GetCurrentTimestampMillis()) {
+		return 0, false, nil
+	}
+
+	runs := offer.AgreedRuns()
+
+	keys := rs.redis.GetKeys()
+	ctx := rs.redis.GetContext()
+
+	pipe := rs.redis.TxPipeline()
+	pipe.HSet(ctx, keys.GameState(clubID, roomID), "run_count", runs)
+	pipe.Del(ctx, keys.RoomRunItOffer(clubID, roomID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		rs.logger.Errorf("Ошибка при сохранении количества прогонов в комнате %s:%s: %v", clubID, roomID, err)
+		return 0, false, fmt.Errorf("ошибка сохранения количества прогонов: %w", err)
+	}
+
+	rs.actionLogger.LogRunItAgreed(clubID, roomID, runs)
+	rs.logger.Infof("В комнате %s:%s доска прогоняется %d раз(а)", clubID, roomID, runs)
+
+	return runs, true, nil
+}
+
+// GetOffer - возвращает текущее предложение прогонов с выбором игроков (nil если предложения нет)
+func (rs *RunItService) GetOffer(clubID, roomID string) (*models.RunItOffer, error) {
+	data, err := rs.redis.HGetAll(rs.redis.GetKeys().RoomRunItOffer(clubID, roomID))
+	if err != nil {
+		return nil, err
+	}
+	if data[runItOfferField] == "" {
+		return nil, nil
+	}
+
+	var offer models.RunItOffer
+	if err := json.Unmarshal([]byte(data[runItOfferField]), &offer); err != nil {
+		return nil, fmt.Errorf("ошибка при парсинге предложения прогонов: %w", err)
+	}
+
+	offer.Choices = make(map[string]int, len(data)-1)
+	for field, value := range data {
+		if field == runItOfferField {
+			continue
+		}
+		runs, _ := strconv.Atoi(value)
+		offer.Choices[field] = runs
+	}
+
+	return &offer, nil
+}
+
+// allInPlayers - участники раздачи, если торговля закончена и все они в олл-ине
+// Допускается один игрок с фишками, уже уравнявший текущую ставку: ему не с кем торговаться
+// Используется также для фиксации эквити в момент олл-ина (AllInEVService)
+func allInPlayers(gameStateService *GameStateService, clubID, roomID string, game *models.Game) ([]string, error) {
+	playerIDs, err := gameStateService.GetPlayerIDsFromSeat(clubID, roomID, game.DealerPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]string, 0, len(playerIDs))
+	withChips := 0
+	for _, userID := range playerIDs {
+		player, err := gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil || !player.HasCards() || player.IsFolded() {
+			continue
+		}
+
+		if !player.IsAllIn() && player.HasChips() {
+			withChips++
+			if withChips > 1 || player.Bet < game.CurrentBet {
+				return nil, ErrRunItNotAllIn
+			}
+		}
+		players = append(players, userID)
+	}
+
+	if len(players) < 2 {
+		return nil, ErrRunItNotAllIn
+	}
+
+	return players, nil
+}
+
+var (
+	ErrRunItNotAllowed     = &RunItError{message: "running the board more than once is not allowed"}
+	ErrRunItNotAllIn       = &RunItError{message: "not all remaining players are all-in"}
+	ErrRunItBoardComplete  = &RunItError{message: "board is already complete"}
+	ErrRunItAlreadyOffered = &RunItError{message: "run it offer already exists"}
+	ErrNoRunItOffer        = &RunItError{message: "no run it offer"}
+	ErrRunItOfferExpired   = &RunItError{message: "run it offer has expired"}
+	ErrNotInRunItOffer     = &RunItError{message: "player is not part of the run it offer"}
+	ErrInvalidRunCount     = &RunItError{message: "invalid number of runs"}
+)
+
+type RunItError struct {
+	message string
+}
+
+func (e *RunItError) Error() string {
+	return "run it error: " + e.message
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SeatManager - сервис для управления местами за столом
+// Резервирование места атомарно: SADD в occupied_seats возвращает 1 только одному
+// из нескольких игроков, одновременно запросивших одно и то же место
+type SeatManager struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewSeatManager - создает новый экземпляр SeatManager
+func NewSeatManager(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	actionLogger *ActionLogger,
+) *SeatManager {
+	return &SeatManager{
+		redis:            redis,
+		gameStateService: gameStateService,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("SeatManager"),
+	}
+}
+
+// ReserveSeat - атомарно резервирует место за столом
+// Возвращает ErrSeatTaken, если место уже занято
+func (sm *SeatManager) ReserveSeat(clubID, roomID string, seat, maxPlayers int) error {
+	if !models.IsValidSeat(seat, maxPlayers) {
+		return ErrInvalidSeat
+	}
+
+	seatsKey := sm.redis.GetKeys().RoomOccupiedSeats(clubID, roomID)
+	added, err := sm.redis.GetClient().SAdd(sm.redis.GetContext(), seatsKey, strconv.Itoa(seat)).Result()
+	if err != nil {
+		sm.logger.Errorf("Ошибка при резервировании места %d в комнате %s:%s: %v", seat, clubID, roomID, err)
+		return err
+	}
+
+	if added == 0 {
+		return ErrSeatTaken
+	}
+
+	return nil
+}
+
+// AutoSelectSeat - резервирует первое свободное место за столом
+// Возвращает номер зарезервированного места или ErrNoFreeSeats
+func (sm *SeatManager) AutoSelectSeat(clubID, roomID string, maxPlayers int) (int, error) {
+	for seat := 0; seat < maxPlayers; seat++ {
+		err := sm.ReserveSeat(clubID, roomID, seat, maxPlayers)
+		if err == nil {
+			return seat, nil
+		}
+		if err != ErrSeatTaken {
+			return -1, err
+		}
+	}
+
+	return -1, ErrNoFreeSeats
+}
+
+// AssignSeat - закрепляет зарезервированное место за игроком
+// Обновляет позицию игрока и порядок ходов (turn_order)
+func (sm *SeatManager) AssignSeat(clubID, roomID, userID string, seat int) error {
+	keys := sm.redis.GetKeys()
+	ctx := sm.redis.GetContext()
+
+	pipe := sm.redis.TxPipeline()
+	pipe.HSet(ctx, keys.PlayerInfo(clubID, roomID, userID), "position", seat)
+	pipe.ZAdd(ctx, keys.RoomTurnOrder(clubID, roomID), redis.Z{Score: float64(seat), Member: userID})
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		sm.logger.Errorf("Ошибка при закреплении места %d за игроком %s: %v", seat, userID, err)
+		return fmt.Errorf("ошибка закрепления места: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseSeat - освобождает место игрока и удаляет его из порядка ходов
+func (sm *SeatManager) ReleaseSeat(clubID, roomID, userID string, seat int) error {
+	keys := sm.redis.GetKeys()
+	ctx := sm.redis.GetContext()
+
+	pipe := sm.redis.TxPipeline()
+	pipe.SRem(ctx, keys.RoomOccupiedSeats(clubID, roomID), strconv.Itoa(seat))
+	pipe.ZRem(ctx, keys.RoomTurnOrder(clubID, roomID), userID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		sm.logger.Errorf("Ошибка при освобождении места %d в комнате %s:%s: %v", seat, clubID, roomID, err)
+		return fmt.Errorf("ошибка освобождения места: %w", err)
+	}
+
+	return nil
+}
+
+// CancelReservation - отменяет резервирование места, за которым еще не закреплен игрок
+// Используется, если посадка за стол не удалась после резервирования
+func (sm *SeatManager) CancelReservation(clubID, roomID string, seat int) error {
+	seatsKey := sm.redis.GetKeys().RoomOccupiedSeats(clubID, roomID)
+	return sm.redis.SRem(seatsKey, strconv.Itoa(seat))
+}
+
+// RequestSeatChange - запрашивает пересадку игрока на другое место
+// Если раздача не идет - пересадка выполняется сразу, иначе - после окончания раздачи
+func (sm *SeatManager) RequestSeatChange(clubID, roomID, userID string, newSeat int) error {
+	room, err := sm.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil {
+		return err
+	}
+	if room == nil {
+		return ErrRoomNotFound
+	}
+	if !models.IsValidSeat(newSeat, room.MaxPlayers) {
+		return ErrInvalidSeat
+	}
+
+	player, err := sm.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if player == nil {
+		return ErrPlayerNotSeated
+	}
+	if player.Position == newSeat {
+		return nil
+	}
+
+	isActive, err := sm.gameStateService.IsGameActive(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	if isActive {
+		playerKey := sm.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
+		if err := sm.redis.HSet(playerKey, "requested_seat", newSeat); err != nil {
+			return err
+		}
+		sm.logger.Infof("Игрок %s запросил пересадку на место %d (после раздачи)", userID, newSeat)
+		return nil
+	}
+
+	return sm.changeSeat(clubID, roomID, player, newSeat, room.MaxPlayers)
+}
+
+// ProcessSeatChanges - выполняет отложенные пересадки (вызывается между раздачами)
+// Если запрошенное место к этому моменту занято, запрос отменяется
+func (sm *SeatManager) ProcessSeatChanges(clubID, roomID string) error {
+	room, err := sm.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return err
+	}
+
+	playerIDs, err := sm.gameStateService.GetPlayerIDsBySeat(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range playerIDs {
+		playerKey := sm.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
+		requested, err := sm.redis.HGet(playerKey, "requested_seat")
+		if err != nil || requested == "" {
+			continue
+		}
+
+		sm.redis.HDel(playerKey, "requested_seat")
+
+		newSeat, err := strconv.Atoi(requested)
+		if err != nil {
+			continue
+		}
+
+		player, err := sm.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil || player == nil {
+			continue
+		}
+
+		if err := sm.changeSeat(clubID, roomID, player, newSeat, room.MaxPlayers); err != nil {
+			sm.logger.Warningf("Пересадка игрока %s на место %d не выполнена: %v", userID, newSeat, err)
+		}
+	}
+
+	return nil
+}
+
+// changeSeat - пересаживает игрока: резервирует новое место, затем освобождает старое
+func (sm *SeatManager) changeSeat(clubID, roomID string, player *models.Player, newSeat, maxPlayers int) error {
+	if err := sm.ReserveSeat(clubID, roomID, newSeat, maxPlayers); err != nil {
+		return err
+	}
+
+	oldSeat := player.Position
+	if err := sm.AssignSeat(clubID, roomID, player.UserID, newSeat); err != nil {
+		sm.CancelReservation(clubID, roomID, newSeat)
+		return err
+	}
+
+	seatsKey := sm.redis.GetKeys().RoomOccupiedSeats(clubID, roomID)
+	if err := sm.redis.SRem(seatsKey, strconv.Itoa(oldSeat)); err != nil {
+		return err
+	}
+
+	sm.actionLogger.LogSeatChanged(clubID, roomID, player.UserID, oldSeat, newSeat)
+	sm.logger.Infof("Игрок %s пересел с места %d на место %d", player.UserID, oldSeat, newSeat)
+
+	return nil
+}
+
+// GetOccupiedSeats - возвращает номера занятых мест
+func (sm *SeatManager) GetOccupiedSeats(clubID, roomID string) ([]int, error) {
+	members, err := sm.redis.SMembers(sm.redis.GetKeys().RoomOccupiedSeats(clubID, roomID))
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make([]int, 0, len(members))
+	for _, member := range members {
+		if seat, err := strconv.Atoi(member); err == nil {
+			seats = append(seats, seat)
+		}
+	}
+
+	return seats, nil
+}
+
+var (
+	ErrInvalidSeat = &SeatError{message: "seat number is out of range"}
+	ErrSeatTaken   = &SeatError{message: "seat is already taken"}
+	ErrNoFreeSeats = &SeatError{message: "no free seats"}
+)
+
+type SeatError struct {
+	message string
+}
+
+func (e *SeatError) Error() string {
+	return "seat error: " + e.message
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/utils"
+)
+
+// ShowdownService - сервис вскрытия карт
+// Оценивает руки оставшихся игроков, присуждает банки (в хай-лоу - старшую и
+// младшую половины отдельно) и сохраняет историю раздачи
+type ShowdownService struct {
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// handEvaluator - сервис оценки рук
+	handEvaluator *HandEvaluator
+
+	// potManager - сервис банков раздачи
+	potManager *PotManager
+
+	// handHistory - сервис истории раздач
+	handHistory *HandHistoryService
+
+	// allInEV - сервис учета эквити олл-инов
+	allInEV *AllInEVService
+
+	// playerStats - сервис статистики игроков
+	playerStats *PlayerStatsService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewShowdownService - создает новый экземпляр ShowdownService
+func NewShowdownService(
+	gameStateService *GameStateService,
+	handEvaluator *HandEvaluator,
+	potManager *PotManager,
+	handHistory *HandHistoryService,
+	allInEV *AllInEVService,
+	playerStats *PlayerStatsService,
+	actionLogger *ActionLogger,
+) *ShowdownService {
+	return &ShowdownService{
+		gameStateService: gameStateService,
+		handEvaluator:    handEvaluator,
+		potManager:       potManager,
+		handHistory:      handHistory,
+		allInEV:          allInEV,
+		playerStats:      playerStats,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("Showdown"),
+	}
+}
+
+// Showdown - проводит вскрытие и завершает раздачу
+// Вызывается и для раздачи, выигранной сбросом карт соперников (BettingService): банк
+// присуждается без сравнения рук, а статистика игроков учитывает такую раздачу.
+// Возвращает историю раздачи с руками игроков и итогом каждого банка
+func (ss *ShowdownService) Showdown(clubID, roomID string) (*models.HandHistory, error) {
+	room, err := ss.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+
+	game, err := ss.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	// Участников раздачи запоминаем до расчета: после него ставки обнуляются
+	players, err := ss.snapshotPlayers(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	pots, err := ss.potManager.CollectPots(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Руки оцениваются на каждой доске: при нескольких прогонах доля каждого банка
+	// разыгрывается на своей доске
+	boards := game.GetBoards()
+	contested := isShowdownContested(players)
+	if !contested {
+		// Раздача выиграна сбросами: доска может быть неполной, и оценивать руки нечего.
+		// Без указанных победителей каждый банк достается единственному претенденту
+		boards = boards[:1]
+	}
+	runHands := make([]map[string]models.ShowdownHand, len(boards))
+	runWinners := make([][]models.PotWinners, len(boards))
+	for run, board := range boards {
+		if !contested {
+			runHands[run] = map[string]models.ShowdownHand{}
+			runWinners[run] = make([]models.PotWinners, len(pots))
+			continue
+		}
+
+		runHands[run], err = ss.handEvaluator.EvaluatePlayersOnBoard(clubID, roomID, board)
+		if err != nil {
+			return nil, err
+		}
+
+		runWinners[run], err = ss.handEvaluator.WinnersFromHands(clubID, roomID, runHands[run], pots)
+		if err != nil {
+			return nil, err
+		}
+	}
+	hands := runHands[0]
+
+	settlement, err := ss.potManager.SettleHandRuns(clubID, roomID, runWinners)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.HandHistory{
+		GameID:       game.GameID,
+		ClubID:       clubID,
+		RoomID:       roomID,
+		RoundNumber:  game.RoundNumber,
+		GameType:     room.GameType,
+		TournamentID: room.TournamentID,
+		Board:        boards[0],
+		Pots:         settlement.Pots,
+		Rake:         settlement.Rake,
+		Timestamp:    utils.GetCurrentTimestamp(),
+	}
+
+	if len(boards) > 1 {
+		history.Runs = buildHistoryRuns(boards, runHands, settlement.Pots)
+	}
+
+	for _, player := range players {
+		if hand, ok := hands[player.UserID]; ok {
+			// В рэззе старшая рука не играет
+			if !room.GameType.IsRazz() {
+				high := hand.High
+				player.HighHand = &high
+			}
+			player.LowHand = hand.Low
+		} else {
+			// Карты сбросивших игроков не показываются
+			player.Cards = nil
+		}
+		player.Won = settlement.Payouts[player.UserID]
+		history.Players = append(history.Players, player)
+	}
+
+	if err := ss.allInEV.Apply(history); err != nil {
+		ss.logger.Warningf("Не удалось учесть эквити олл-ина в раздаче %s: %v", game.GameID, err)
+	}
+
+	if err := ss.playerStats.HandFinished(history); err != nil {
+		ss.logger.Warningf("Не удалось обновить статистику игроков по раздаче %s: %v", game.GameID, err)
+	}
+
+	if err := ss.handHistory.Save(history); err != nil {
+		ss.logger.Warningf("Не удалось сохранить историю раздачи %s: %v", game.GameID, err)
+	}
+
+	ss.actionLogger.LogShowdown(clubID, roomID, game.GameID, settlement.Payouts)
+	ss.logger.Infof("Вскрытие в комнате %s:%s: банков %d, прогонов %d, рейк %d",
+		clubID, roomID, len(settlement.Pots), len(boards), settlement.Rake)
+
+	return history, nil
+}
+
+// isShowdownContested - дошли ли до вскрытия хотя бы два не сбросивших карты игрока
+func isShowdownContested(players []models.HandHistoryPlayer) bool {
+	remaining := 0
+	for _, player := range players {
+		if !player.Folded {
+			remaining++
+		}
+	}
+	return remaining >= 2
+}
+
+// snapshotPlayers - собирает участников раздачи по местам
+func (ss *ShowdownService) snapshotPlayers(clubID, roomID string) ([]models.HandHistoryPlayer, error) {
+	playerIDs, err := ss.gameStateService.GetPlayerIDsBySeat(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]models.HandHistoryPlayer, 0, len(playerIDs))
+	for _, userID := range playerIDs {
+		player, err := ss.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if player == nil || !player.HasCards() {
+			continue
+		}
+
+		players = append(players, models.HandHistoryPlayer{
+			UserID:   player.UserID,
+			Seat:     player.Position,
+			Cards:    player.Cards,
+			UpCards:  player.UpCards,
+			TotalBet: player.TotalBet,
+			Straddle: player.Straddle,
+			Folded:   player.IsFolded(),
+		})
+	}
+
+	return players, nil
+}
+
+// buildHistoryRuns - раскладывает итог розыгрыша по прогонам доски для истории раздачи
+func buildHistoryRuns(boards [][]string, runHands []map[string]models.ShowdownHand, pots []models.PotSettlement) []models.HandHistoryRun {
+	runs := make([]models.HandHistoryRun, len(boards))
+	for i, board := range boards {
+		runs[i] = models.HandHistoryRun{
+			Run:     i + 1,
+			Board:   board,
+			Hands:   runHands[i],
+			Pots:    []models.PotSettlement{},
+			Payouts: make(map[string]int),
+		}
+	}
+
+	for _, pot := range pots {
+		if pot.Run < 1 || pot.Run > len(runs) {
+			continue
+		}
+
+		run := &runs[pot.Run-1]
+		run.Pots = append(run.Pots, pot)
+		for userID, share := range pot.HighShares {
+			run.Payouts[userID] += share
+		}
+		for userID, share := range pot.LowShares {
+			run.Payouts[userID] += share
+		}
+	}
+
+	return runs
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	"poker-engine/models"
+)
+
+func TestShowdownPreFlopFoldOut(t *testing.T) {
+	// Блайнды 10/20, игрок c повысил до 60, блайнды сбросили карты
+	players := []models.HandHistoryPlayer{
+		{UserID: "a", TotalBet: 10, Folded: true},
+		{UserID: "b", TotalBet: 20, Folded: true},
+		{UserID: "c", TotalBet: 60},
+	}
+	if isShowdownContested(players) {
+		t.Fatal("раздача, выигранная сбросами, не должна доходить до вскрытия")
+	}
+
+	contributions := make([]models.PotContribution, len(players))
+	for i, player := range players {
+		contributions[i] = models.PotContribution{UserID: player.UserID, Amount: player.TotalBet, Folded: player.Folded}
+	}
+	pots := models.BuildPots(contributions)
+
+	payouts := make(map[string]int)
+	for i, pot := range pots {
+		winners, ok := resolvePotWinners(pot, models.PotWinners{})
+		if !ok {
+			t.Fatalf("банк #%d %+v: не удалось определить победителя без вскрытия", i, pot)
+		}
+		high, low := models.SplitHiLo(pot.Amount, winners.High, winners.Low)
+		for userID, share := range high {
+			payouts[userID] += share
+		}
+		for userID, share := range low {
+			payouts[userID] += share
+		}
+	}
+
+	if len(payouts) != 1 || payouts["c"] != 90 {
+		t.Errorf("выигрыш = %v, ожидается весь банк 90 у игрока c", payouts)
+	}
+}
+
+func TestShowdownContested(t *testing.T) {
+	players := []models.HandHistoryPlayer{
+		{UserID: "a", TotalBet: 100},
+		{UserID: "b", TotalBet: 100, Folded: true},
+		{UserID: "c", TotalBet: 100},
+	}
+	if !isShowdownContested(players) {
+		t.Error("двое не сбросивших карты игроков должны вскрываться")
+	}
+
+	pot := models.SidePot{Amount: 300, EligiblePlayers: []string{"a", "c"}}
+	if _, ok := resolvePotWinners(pot, models.PotWinners{}); ok {
+		t.Error("разыгрываемый банк без победителей не должен присуждаться")
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// SitOutManager - сервис для пропуска раздач (sit out) и возвращения в игру (sit in)
+// Пока игрок сидит вне игры, за ним отмечаются пропущенные блайнды: при возвращении
+// их нужно оплатить либо дождаться большого блайнда. Каждый пропущенный большой
+// блайнд - это один круг; после maxSitOutOrbits кругов игрок поднимается из-за стола
+type SitOutManager struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// buyInManager - сервис покупки фишек (подъем игрока из-за стола)
+	buyInManager *BuyInManager
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+
+	// maxSitOutOrbits - сколько кругов игрок может пропустить до подъема из-за стола
+	maxSitOutOrbits int
+}
+
+// NewSitOutManager - создает новый экземпляр SitOutManager
+func NewSitOutManager(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	buyInManager *BuyInManager,
+	actionLogger *ActionLogger,
+	maxSitOutOrbits int,
+) *SitOutManager {
+	return &SitOutManager{
+		redis:            redis,
+		gameStateService: gameStateService,
+		buyInManager:     buyInManager,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("SitOutManager"),
+		maxSitOutOrbits:  maxSitOutOrbits,
+	}
+}
+
+// RequestSitOut - игрок просит пропускать раздачи
+// Если игрок участвует в текущей раздаче, он сядет вне игры после ее окончания
+func (sm *SitOutManager) RequestSitOut(clubID, roomID, userID string) error {
+	player, err := sm.getPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if player.IsSittingOut() {
+		return nil
+	}
+
+	isActive, err := sm.gameStateService.IsGameActive(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	playerKey := sm.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
+
+	if isActive && player.HasCards() {
+		if err := sm.redis.HSet(playerKey, "sit_out_next_hand", true); err != nil {
+			return err
+		}
+		sm.actionLogger.LogPlayerSatOut(clubID, roomID, userID, true)
+		sm.logger.Infof("Игрок %s сядет вне игры после текущей раздачи", userID)
+		return nil
+	}
+
+	if err := sm.sitOut(clubID, roomID, userID); err != nil {
+		return err
+	}
+
+	sm.actionLogger.LogPlayerSatOut(clubID, roomID, userID, false)
+	sm.logger.Infof("Игрок %s сел вне игры в комнате %s:%s", userID, clubID, roomID)
+	return nil
+}
+
+// RequestSitIn - игрок возвращается в игру
+// Параметры:
+//   - waitForBigBlind: если у игрока есть пропущенные блайнды - ждать большого блайнда
+//     (true) или оплатить пропущенные блайнды в следующей раздаче (false)
+func (sm *SitOutManager) RequestSitIn(clubID, roomID, userID string, waitForBigBlind bool) error {
+	player, err := sm.getPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	playerKey := sm.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
+
+	// Игрок передумал до окончания раздачи - просто снимаем запрос
+	if player.SitOutNextHand {
+		return sm.redis.HSet(playerKey, "sit_out_next_hand", false)
+	}
+
+	if !player.IsSittingOut() {
+		return nil
+	}
+
+	if !player.HasChips() {
+		return ErrUseRebuy
+	}
+
+	updates := map[string]interface{}{
+		"status":             string(models.PlayerStatusWaiting),
+		"sit_out_orbits":     0,
+		"wait_for_big_blind": false,
+		"post_missed_blinds": false,
+	}
+	if player.HasMissedBlinds() {
+		if waitForBigBlind {
+			updates["wait_for_big_blind"] = true
+		} else {
+			updates["post_missed_blinds"] = true
+		}
+	}
+
+	if err := sm.redis.HMSet(playerKey, updates); err != nil {
+		sm.logger.Errorf("Ошибка при возвращении игрока %s в игру: %v", userID, err)
+		return err
+	}
+
+	sm.actionLogger.LogPlayerSatIn(clubID, roomID, userID, waitForBigBlind && player.HasMissedBlinds())
+	sm.logger.Infof("Игрок %s вернулся в игру в комнате %s:%s", userID, clubID, roomID)
+	return nil
+}
+
+// ApplyPendingSitOuts - сажает вне игры игроков, запросивших это во время раздачи
+// Вызывается перед началом следующей раздачи
+func (sm *SitOutManager) ApplyPendingSitOuts(clubID, roomID string) error {
+	playerIDs, err := sm.gameStateService.GetPlayerIDs(clubID, roomID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range playerIDs {
+		player, err := sm.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return err
+		}
+		if player == nil || !player.SitOutNextHand {
+			continue
+		}
+
+		if err := sm.sitOut(clubID, roomID, userID); err != nil {
+			return err
+		}
+		sm.logger.Infof("Игрок %s сел вне игры после окончания раздачи", userID)
+	}
+
+	return nil
+}
+
+// RecordMissedBlind - отмечает блайнд, пропущенный игроком, сидящим вне игры
+// Пропущенный большой блайнд завершает круг; если игрок пропустил больше
+// maxSitOutOrbits кругов - он поднимается из-за стола
+// Возвращает true, если игрок был поднят из-за стола
+func (sm *SitOutManager) RecordMissedBlind(clubID, roomID string, player *models.Player, bigBlind bool) (bool, error) {
+	playerKey := sm.redis.GetKeys().PlayerInfo(clubID, roomID, player.UserID)
+
+	if !bigBlind {
+		player.MissedSmallBlind = true
+		sm.actionLogger.LogBlindMissed(clubID, roomID, player.UserID, "small")
+		return false, sm.redis.HSet(playerKey, "missed_small_blind", true)
+	}
+
+	player.MissedBigBlind = true
+	if err := sm.redis.HSet(playerKey, "missed_big_blind", true); err != nil {
+		return false, err
+	}
+	sm.actionLogger.LogBlindMissed(clubID, roomID, player.UserID, "big")
+
+	orbits, err := sm.redis.HIncrBy(playerKey, "sit_out_orbits", 1)
+	if err != nil {
+		return false, err
+	}
+	player.SitOutOrbits = int(orbits)
+
+	if player.SitOutOrbits <= sm.maxSitOutOrbits {
+		return false, nil
+	}
+
+	sm.logger.Infof("Игрок %s пропустил %d кругов - поднимаем из-за стола", player.UserID, player.SitOutOrbits)
+	if err := sm.buyInManager.StandUp(clubID, roomID, player.UserID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// sitOut - переводит игрока в статус sit_out
+func (sm *SitOutManager) sitOut(clubID, roomID, userID string) error {
+	playerKey := sm.redis.GetKeys().PlayerInfo(clubID, roomID, userID)
+	return sm.redis.HMSet(playerKey, map[string]interface{}{
+		"status":             string(models.PlayerStatusSitOut),
+		"sit_out_next_hand":  false,
+		"wait_for_big_blind": false,
+		"post_missed_blinds": false,
+	})
+}
+
+// getPlayer - получает сидящего за столом игрока или возвращает ErrPlayerNotSeated
+func (sm *SitOutManager) getPlayer(clubID, roomID, userID string) (*models.Player, error) {
+	player, err := sm.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if player == nil {
+		return nil, ErrPlayerNotSeated
+	}
+	return player, nil
+}
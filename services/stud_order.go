@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/utils"
+)
+
+// StudActionOrder - сервис для определения порядка торговли в стаде
+// В стаде нет блайндов и фиксированной позиции: на третьей улице торговлю
+// открывает bring-in по младшей открытой карте, с четвертой улицы первым
+// ходит игрок с лучшими открытыми картами
+type StudActionOrder struct {
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewStudActionOrder - создает новый экземпляр StudActionOrder
+func NewStudActionOrder(gameStateService *GameStateService) *StudActionOrder {
+	return &StudActionOrder{
+		gameStateService: gameStateService,
+		logger:           utils.NewLogger("StudActionOrder"),
+	}
+}
+
+// GetBringIn - определяет игрока, который ставит bring-in на третьей улице
+// В стаде - младшая открытая карта, в рэззе - старшая; при равенстве решает масть
+func (so *StudActionOrder) GetBringIn(clubID, roomID string) (string, error) {
+	room, upCards, _, err := so.loadUpCards(clubID, roomID)
+	if err != nil {
+		return "", err
+	}
+
+	doorCards := make(map[string]models.Card, len(upCards))
+	for userID, cards := range upCards {
+		if len(cards) > 0 {
+			doorCards[userID] = cards[0]
+		}
+	}
+
+	bringIn := models.FindBringIn(doorCards, room.GameType.IsRazz())
+	if bringIn == "" {
+		return "", fmt.Errorf("нет игроков с открытыми картами в комнате %s:%s", clubID, roomID)
+	}
+
+	return bringIn, nil
+}
+
+// GetFirstToAct - определяет игрока, открывающего торговлю с четвертой улицы
+// В стаде - лучшая открытая рука, в рэззе - лучшая младшая открытая рука.
+// При равенстве ходит игрок, ближайший к дилеру по часовой стрелке
+func (so *StudActionOrder) GetFirstToAct(clubID, roomID string) (string, error) {
+	room, upCards, order, err := so.loadUpCards(clubID, roomID)
+	if err != nil {
+		return "", err
+	}
+
+	first := models.FindFirstToAct(upCards, order, room.GameType.IsRazz())
+	if first == "" {
+		return "", fmt.Errorf("нет игроков с открытыми картами в комнате %s:%s", clubID, roomID)
+	}
+
+	return first, nil
+}
+
+// loadUpCards - загружает открытые карты игроков, оставшихся в раздаче
+// Возвращает также порядок игроков по часовой стрелке от дилера
+func (so *StudActionOrder) loadUpCards(clubID, roomID string) (*models.Room, map[string][]models.Card, []string, error) {
+	room, err := so.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return nil, nil, nil, fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+	if !room.GameType.IsStud() {
+		return nil, nil, nil, fmt.Errorf("комната %s:%s не играет в стад", clubID, roomID)
+	}
+
+	game, err := so.gameStateService.GetGameState(clubID, roomID)
+	if err != nil || game == nil {
+		return nil, nil, nil, fmt.Errorf("не удалось получить состояние игры: %v", err)
+	}
+
+	order, err := so.gameStateService.GetPlayerIDsFromSeat(clubID, roomID, game.DealerPosition)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	upCards := make(map[string][]models.Card, len(order))
+	for _, userID := range order {
+		player, err := so.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if player == nil || player.IsFolded() || len(player.UpCards) == 0 {
+			continue
+		}
+
+		cards, err := models.ParseCards(player.UpCards)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("не удалось разобрать открытые карты игрока %s: %w", userID, err)
+		}
+		upCards[userID] = cards
+	}
+
+	return room, upCards, order, nil
+}
@@ -0,0 +1,792 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TournamentService - сервис турниров Sit & Go
+// Участники платят buy-in и комиссию и получают одинаковые стартовые стеки турнирных фишек.
+// Турнир стартует, когда заняты все места; блайнды растут по расписанию уровней.
+// Игроки, проигравшие все фишки, выбывают с записью занятого места, а призовой фонд
+// делится по таблице выплат
+type TournamentService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// ledger - журнал движения фишек
+	ledger *ChipLedger
+
+	// buyInManager - сервис посадки за стол
+	buyInManager *BuyInManager
+
+	// blindClock - часы уровней блайндов
+	blindClock *BlindClockService
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+}
+
+// NewTournamentService - создает новый экземпляр TournamentService
+func NewTournamentService(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	ledger *ChipLedger,
+	buyInManager *BuyInManager,
+	blindClock *BlindClockService,
+	actionLogger *ActionLogger,
+) *TournamentService {
+	return &TournamentService{
+		redis:            redis,
+		gameStateService: gameStateService,
+		ledger:           ledger,
+		buyInManager:     buyInManager,
+		blindClock:       blindClock,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("Tournament"),
+	}
+}
+
+// CreateSitAndGo - создает Sit & Go в пустой комнате клуба и открывает регистрацию
+// Заполняются TournamentID, RoomID, BuyIn, Fee, StartingStack, MaxPlayers, BlindLevels
+// и при необходимости PayoutPercents или PayoutStructure; MaxPlayers не может превышать количество мест в комнате
+func (ts *TournamentService) CreateSitAndGo(tournament *models.Tournament) error {
+	if err := validateTournament(tournament); err != nil {
+		return err
+	}
+
+	seats, err := ts.checkTables(tournament.ClubID, []string{tournament.RoomID})
+	if err != nil {
+		return err
+	}
+	if tournament.MaxPlayers > seats {
+		return ErrTournamentRoomBusy
+	}
+
+	tournament.Type = models.TournamentSitAndGo
+	if err := ts.create(tournament, []string{tournament.RoomID}); err != nil {
+		return err
+	}
+
+	ts.logger.Infof("Создан Sit & Go %s в комнате %s:%s (%d мест, buy-in %d+%d)",
+		tournament.TournamentID, tournament.ClubID, tournament.RoomID, tournament.MaxPlayers, tournament.BuyIn, tournament.Fee)
+
+	return nil
+}
+
+// validateTournament - проверяет настройки турнира
+func validateTournament(tournament *models.Tournament) error {
+	if tournament.TournamentID == "" || tournament.StartingStack <= 0 || tournament.BuyIn < 0 ||
+		tournament.Fee < 0 || tournament.MaxPlayers < 2 || len(tournament.BlindLevels) == 0 ||
+		tournament.BlindLevels[0].IsBreak {
+		return ErrInvalidTournament
+	}
+	for _, level := range tournament.BlindLevels {
+		if !level.IsValid() {
+			return ErrInvalidTournament
+		}
+	}
+	if len(tournament.PayoutPercents) > 0 && !models.IsValidPayoutPercents(tournament.PayoutPercents) {
+		return ErrInvalidTournament
+	}
+	if len(tournament.PayoutStructure) > 0 && !tournament.PayoutStructure.IsValid() {
+		return ErrInvalidTournament
+	}
+	if tournament.Bounty < 0 || tournament.Bounty > tournament.BuyIn ||
+		tournament.BountyCashPercent < 0 || tournament.BountyCashPercent > 100 {
+		return ErrInvalidTournament
+	}
+	if tournament.RebuyUntilLevel < 0 || tournament.MaxRebuys < 0 || tournament.LateRegUntilLevel < 0 ||
+		tournament.AddOnChips < 0 || tournament.AddOnCost < 0 || (tournament.AddOnCost > 0 && tournament.AddOnChips == 0) {
+		return ErrInvalidTournament
+	}
+	return nil
+}
+
+// checkTables - проверяет, что столы существуют, пусты и не заняты другим турниром
+// Возвращает общее количество мест за столами
+func (ts *TournamentService) checkTables(clubID string, roomIDs []string) (int, error) {
+	seats := 0
+	for _, roomID := range roomIDs {
+		room, err := ts.gameStateService.GetRoomInfo(clubID, roomID)
+		if err != nil {
+			return 0, err
+		}
+		if room == nil {
+			return 0, ErrRoomNotFound
+		}
+		if room.IsTournament() || room.CurrentPlayers > 0 {
+			return 0, ErrTournamentRoomBusy
+		}
+		seats += room.MaxPlayers
+	}
+	return seats, nil
+}
+
+// create - сохраняет турнир, его столы и привязывает столы к турниру
+func (ts *TournamentService) create(tournament *models.Tournament, roomIDs []string) error {
+	now := utils.GetCurrentTimestampMillis()
+	tournament.Status = models.TournamentStatusRegistering
+	tournament.PrizePool = 0
+	tournament.Entrants = 0
+	tournament.HandForHand = false
+
+	keys := ts.redis.GetKeys()
+	ctx := ts.redis.GetContext()
+
+	pipe := ts.redis.TxPipeline()
+	pipe.HSet(ctx, keys.Tournament(tournament.ClubID, tournament.TournamentID), tournament.ToRedisHash())
+	pipe.ZAdd(ctx, keys.ClubTournaments(tournament.ClubID), redis.Z{Score: float64(now), Member: tournament.TournamentID})
+	for _, roomID := range roomIDs {
+		pipe.SAdd(ctx, keys.TournamentTables(tournament.ClubID, tournament.TournamentID), roomID)
+		pipe.HSet(ctx, keys.RoomInfo(tournament.ClubID, roomID), "tournament_id", tournament.TournamentID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		ts.logger.Errorf("Ошибка при создании турнира %s: %v", tournament.TournamentID, err)
+		return fmt.Errorf("ошибка создания турнира: %w", err)
+	}
+
+	return nil
+}
+
+// GetTournament - получает турнир из Redis (nil, если турнира нет)
+func (ts *TournamentService) GetTournament(clubID, tournamentID string) (*models.Tournament, error) {
+	data, err := ts.redis.HGetAll(ts.redis.GetKeys().Tournament(clubID, tournamentID))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return models.NewTournamentFromRedis(data)
+}
+
+// GetEntries - возвращает участников турнира
+func (ts *TournamentService) GetEntries(clubID, tournamentID string) (map[string]*models.TournamentEntry, error) {
+	data, err := ts.redis.HGetAll(ts.redis.GetKeys().TournamentEntries(clubID, tournamentID))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*models.TournamentEntry, len(data))
+	for userID, entryJSON := range data {
+		var entry models.TournamentEntry
+		if err := json.Unmarshal([]byte(entryJSON), &entry); err != nil {
+			ts.logger.Warningf("Ошибка при парсинге участника %s турнира %s: %v", userID, tournamentID, err)
+			continue
+		}
+		entries[userID] = &entry
+	}
+
+	return entries, nil
+}
+
+// GetTables - возвращает столы турнира, за которыми еще идет игра (по возрастанию ID)
+func (ts *TournamentService) GetTables(clubID, tournamentID string) ([]string, error) {
+	tables, err := ts.redis.SMembers(ts.redis.GetKeys().TournamentTables(clubID, tournamentID))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// Register - регистрирует игрока в турнире: списывает взнос, сажает его за стол
+// со стартовым стеком и запускает турнир, когда заняты все места
+// После старта регистрация возможна до конца уровня LateRegUntilLevel (поздняя регистрация)
+func (ts *TournamentService) Register(clubID, tournamentID, userID, username string) error {
+	tournament, err := ts.getTournament(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	operation := models.TournamentAuditRegistration
+	level := 0
+	if !tournament.IsRegistering() {
+		if !tournament.IsRunning() || tournament.LateRegUntilLevel == 0 {
+			return ErrRegistrationClosed
+		}
+		level, err = ts.currentBlindLevel(clubID, tournamentID)
+		if err != nil {
+			return err
+		}
+		if !tournament.IsLateRegistration(level) {
+			return ErrRegistrationClosed
+		}
+		operation = models.TournamentAuditLateRegistration
+	}
+
+	entry := &models.TournamentEntry{
+		UserID:       userID,
+		Username:     username,
+		Status:       models.TournamentEntryRegistering,
+		Bounty:       tournament.Bounty,
+		RegisteredAt: utils.GetCurrentTimestampMillis(),
+	}
+
+	// Атомарно занимаем запись участника: повторная регистрация того же игрока отклоняется
+	keys := ts.redis.GetKeys()
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации участника турнира: %w", err)
+	}
+	claimed, err := ts.redis.HSetNX(keys.TournamentEntries(clubID, tournamentID), userID, string(entryJSON))
+	if err != nil {
+		return fmt.Errorf("ошибка регистрации в турнире: %w", err)
+	}
+	if !claimed {
+		return ErrAlreadyRegistered
+	}
+
+	// Атомарно занимаем место среди участников и вносим взнос в призовой фонд.
+	// Флаг досрочного закрытия поздней регистрации читается в той же транзакции:
+	// если он еще не выставлен, участник уже учтен при закреплении мест (см. closeLateRegistration)
+	tournamentKey := keys.Tournament(clubID, tournamentID)
+	ctx := ts.redis.GetContext()
+	pipe := ts.redis.TxPipeline()
+	entrantsCmd := pipe.HIncrBy(ctx, tournamentKey, "entrants", 1)
+	prizePoolCmd := pipe.HIncrBy(ctx, tournamentKey, "prize_pool", int64(tournament.PrizePoolPart()))
+	lateRegClosedCmd := pipe.HGet(ctx, tournamentKey, "late_reg_closed")
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		ts.redis.HDel(keys.TournamentEntries(clubID, tournamentID), userID)
+		return fmt.Errorf("ошибка регистрации в турнире: %w", err)
+	}
+	entrants, prizePool := entrantsCmd.Val(), prizePoolCmd.Val()
+
+	registration := &tournamentRegistration{tournament: tournament, userID: userID, prizeAdded: true}
+	if lateRegClosed := lateRegClosedCmd.Val(); lateRegClosed == "1" || lateRegClosed == "true" {
+		ts.cancelRegistration(clubID, registration)
+		return ErrRegistrationClosed
+	}
+	if int(entrants) > tournament.MaxPlayers {
+		ts.cancelRegistration(clubID, registration)
+		return ErrTournamentFull
+	}
+
+	registration.reference = tournamentReference(tournamentID, "entry", userID)
+	if err := ts.ledger.RecordTournamentEntry(clubID, tournamentID, tournament.BuyIn, tournament.Fee, registration.reference); err != nil {
+		ts.cancelRegistration(clubID, registration)
+		return err
+	}
+	registration.paid = true
+
+	roomID, err := ts.pickTable(clubID, tournamentID)
+	if err != nil {
+		ts.cancelRegistration(clubID, registration)
+		return err
+	}
+
+	entry.Status = models.TournamentEntryPlaying
+	entry.RoomID = roomID
+	if err := ts.saveEntry(clubID, tournamentID, entry); err != nil {
+		ts.cancelRegistration(clubID, registration)
+		return err
+	}
+
+	if _, err := ts.buyInManager.SitDownTournament(clubID, roomID, userID, username, tournamentID, -1, tournament.StartingStack); err != nil {
+		ts.cancelRegistration(clubID, registration)
+		return err
+	}
+
+	ts.recordAudit(clubID, tournamentID, &models.TournamentAuditRecord{
+		Operation: operation,
+		UserID:    userID,
+		Paid:      tournament.BuyIn + tournament.Fee,
+		PrizePart: tournament.PrizePoolPart(),
+		Chips:     tournament.StartingStack,
+		PrizePool: int(prizePool),
+		Level:     level,
+	})
+
+	ts.actionLogger.LogTournamentRegistered(clubID, roomID, tournamentID, userID, int(entrants))
+	ts.logger.Infof("Игрок %s зарегистрирован в турнире %s (%d из %d)", userID, tournamentID, entrants, tournament.MaxPlayers)
+
+	if operation == models.TournamentAuditRegistration && int(entrants) == tournament.MaxPlayers {
+		return ts.Start(clubID, tournamentID)
+	}
+
+	return nil
+}
+
+// tournamentRegistration - выполненные шаги регистрации участника (для отката)
+type tournamentRegistration struct {
+	tournament *models.Tournament
+	userID     string
+
+	// reference - ссылка на запись журнала о взносе
+	reference string
+
+	// paid - взнос списан, prizeAdded - призовой фонд пополнен
+	paid       bool
+	prizeAdded bool
+}
+
+// cancelRegistration - откатывает незавершенную регистрацию: возвращает взнос,
+// уменьшает призовой фонд и количество участников и удаляет запись участника
+// Ошибки отката только логируются: исходная ошибка регистрации важнее
+func (ts *TournamentService) cancelRegistration(clubID string, registration *tournamentRegistration) {
+	tournament := registration.tournament
+	tournamentID := tournament.TournamentID
+	userID := registration.userID
+
+	keys := ts.redis.GetKeys()
+	tournamentKey := keys.Tournament(clubID, tournamentID)
+
+	if registration.paid {
+		if err := ts.ledger.RefundTournamentEntry(clubID, tournamentID, tournament.BuyIn, tournament.Fee, registration.reference+":refund"); err != nil {
+			ts.logger.Errorf("Турнир %s: не удалось вернуть взнос игроку %s: %v", tournamentID, userID, err)
+		}
+	}
+	if registration.prizeAdded {
+		if _, err := ts.redis.HIncrBy(tournamentKey, "prize_pool", int64(-tournament.PrizePoolPart())); err != nil {
+			ts.logger.Errorf("Турнир %s: не удалось уменьшить призовой фонд: %v", tournamentID, err)
+		}
+	}
+	if _, err := ts.redis.HIncrBy(tournamentKey, "entrants", -1); err != nil {
+		ts.logger.Errorf("Турнир %s: не удалось уменьшить количество участников: %v", tournamentID, err)
+	}
+	if err := ts.redis.HDel(keys.TournamentEntries(clubID, tournamentID), userID); err != nil {
+		ts.logger.Errorf("Турнир %s: не удалось удалить участника %s: %v", tournamentID, userID, err)
+	}
+
+	ts.logger.Warningf("Турнир %s: регистрация игрока %s отменена", tournamentID, userID)
+}
+
+// pickTable - стол для нового участника: стол с наименьшим количеством игроков
+func (ts *TournamentService) pickTable(clubID, tournamentID string) (string, error) {
+	tables, err := ts.GetTables(clubID, tournamentID)
+	if err != nil {
+		return "", err
+	}
+
+	best, bestCount := "", int64(-1)
+	for _, roomID := range tables {
+		count, err := ts.gameStateService.GetPlayersCount(clubID, roomID)
+		if err != nil {
+			return "", err
+		}
+		if bestCount < 0 || count < bestCount {
+			best, bestCount = roomID, count
+		}
+	}
+	if best == "" {
+		return "", ErrTournamentRoomBusy
+	}
+
+	return best, nil
+}
+
+// Start - запускает турнир: закрывает регистрацию и запускает часы уровней блайндов
+// Раздачи начинает RoomMonitor, когда турнир переходит в статус running
+func (ts *TournamentService) Start(clubID, tournamentID string) error {
+	tournament, err := ts.getTournament(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+	if !tournament.IsRegistering() {
+		return ErrTournamentNotRegistering
+	}
+
+	tables, err := ts.GetTables(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	// Блайнды первого уровня ставятся за столы до того, как RoomMonitor начнет раздачи.
+	// Часы всех столов запускаются одновременно, поэтому уровни меняются синхронно
+	for _, roomID := range tables {
+		if err := ts.blindClock.Start(clubID, roomID, tournament.BlindLevels); err != nil {
+			return err
+		}
+	}
+
+	now := utils.GetCurrentTimestampMillis()
+	updates := map[string]interface{}{
+		"status":     string(models.TournamentStatusRunning),
+		"started_at": now,
+	}
+	if err := ts.redis.HMSet(ts.redis.GetKeys().Tournament(clubID, tournamentID), updates); err != nil {
+		ts.logger.Errorf("Ошибка при старте турнира %s: %v", tournamentID, err)
+		return fmt.Errorf("ошибка старта турнира: %w", err)
+	}
+
+	for _, roomID := range tables {
+		ts.actionLogger.LogTournamentStarted(clubID, roomID, tournamentID, tournament.Entrants, tournament.PrizePool)
+	}
+	ts.logger.Successf("Турнир %s стартовал (участников: %d, призовой фонд: %d)", tournamentID, tournament.Entrants, tournament.PrizePool)
+
+	return nil
+}
+
+// ProcessEliminations - выбывание игроков, проигравших все фишки в раздаче за столом
+// Вызывается после расчета банков. Из выбывших в одной раздаче выше место занимает тот,
+// у кого в начале раздачи было больше фишек (по истории раздачи; без истории - по месту за столом).
+// Первое выбывание закрывает позднюю регистрацию, чтобы места и призы не менялись от новых участников.
+// Когда в турнире остается один участник, турнир завершается; в многостольном турнире
+// после выбываний включается игра рука в руку и балансируются столы
+func (ts *TournamentService) ProcessEliminations(clubID, roomID string, history *models.HandHistory) error {
+	room, err := ts.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil || room == nil {
+		return fmt.Errorf("не удалось получить информацию о комнате: %v", err)
+	}
+	if !room.IsTournament() {
+		return nil
+	}
+
+	tournament, err := ts.getTournament(clubID, room.TournamentID)
+	if err != nil {
+		return err
+	}
+	if !tournament.IsRunning() {
+		return nil
+	}
+
+	entries, err := ts.GetEntries(clubID, tournament.TournamentID)
+	if err != nil {
+		return err
+	}
+
+	startStacks := make(map[string]int)
+	if history != nil {
+		for _, hp := range history.Players {
+			startStacks[hp.UserID] = hp.TotalBet - hp.Won
+		}
+	}
+
+	// Пока идет период ребаев, проигравший все фишки участник может сделать ребай и не выбывает.
+	// Если фишки остались меньше чем у двух участников, ждать ребаев некого - выбывают все
+	rebuyOpen := false
+	if tournament.RebuyUntilLevel > 0 {
+		level, err := ts.currentBlindLevel(clubID, tournament.TournamentID)
+		if err != nil {
+			return err
+		}
+		rebuyOpen = tournament.IsRebuyPeriod(level)
+	}
+
+	remaining, alive := 0, 0
+	var busted []*models.Player
+	for userID, entry := range entries {
+		if !entry.IsPlaying() {
+			continue
+		}
+		remaining++
+
+		player, err := ts.gameStateService.GetPlayer(clubID, entry.RoomID, userID)
+		if err != nil {
+			return err
+		}
+		if player == nil {
+			continue
+		}
+		if player.HasChips() || player.HasPendingChips() || (entry.RoomID != roomID && player.TotalBet > 0) {
+			alive++
+			continue
+		}
+		busted = append(busted, player)
+	}
+
+	// Баунти выбитого участника получают победители его последнего банка в этой раздаче.
+	// Если участник может сделать ребай, выбившие запоминаются до его отказа от ребая
+	eliminated := busted[:0]
+	for _, player := range busted {
+		entry := entries[player.UserID]
+
+		shares := entry.PendingKnockout
+		if tournament.IsBounty() && history != nil && entry.RoomID == roomID {
+			shares = models.KnockoutShares(player.UserID, history.Pots)
+		}
+
+		if alive >= 2 && (entry.RoomID != roomID || (rebuyOpen && tournament.CanRebuy(entry))) {
+			if entry.RoomID == roomID && len(shares) > 0 {
+				entry.PendingKnockout = shares
+				if err := ts.saveEntry(clubID, tournament.TournamentID, entry); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := ts.awardBounty(clubID, roomID, tournament, entry, shares, entries); err != nil {
+			return err
+		}
+		eliminated = append(eliminated, player)
+	}
+	busted = eliminated
+
+	// Меньший стек в начале раздачи - худшее место
+	sort.Slice(busted, func(i, j int) bool {
+		if startStacks[busted[i].UserID] != startStacks[busted[j].UserID] {
+			return startStacks[busted[i].UserID] < startStacks[busted[j].UserID]
+		}
+		return busted[i].Position < busted[j].Position
+	})
+
+	// Места и призы закрепляются только после закрытия поздней регистрации
+	if len(busted) > 0 {
+		remaining, err = ts.closeLateRegistration(clubID, tournament, entries)
+		if err != nil {
+			return err
+		}
+	}
+
+	prizes := models.CalculatePrizes(tournament.PrizePool, tournament.GetPayoutPercents())
+	now := utils.GetCurrentTimestampMillis()
+
+	for _, player := range busted {
+		if err := ts.eliminate(clubID, tournament, entries[player.UserID], remaining, prizes, now); err != nil {
+			return err
+		}
+		remaining--
+	}
+
+	if remaining == 1 {
+		return ts.finish(clubID, tournament, entries, prizes)
+	}
+
+	if tournament.IsMultiTable() {
+		if err := ts.updateHandForHand(clubID, tournament, roomID, remaining); err != nil {
+			return err
+		}
+		return ts.Rebalance(clubID, tournament.TournamentID)
+	}
+
+	return nil
+}
+
+// closeLateRegistration - закрывает позднюю регистрацию перед тем, как закрепить место выбывающего участника
+// Флаг закрытия выставляется в одной транзакции с чтением количества участников и призового фонда:
+// регистрация, увидевшая флаг невыставленным, уже учтена в них, а увидевшая его - отменяется (см. Register).
+// Обновляет призовой фонд турнира и возвращает количество участников, еще не выбывших из турнира
+func (ts *TournamentService) closeLateRegistration(clubID string, tournament *models.Tournament, entries map[string]*models.TournamentEntry) (int, error) {
+	tournamentKey := ts.redis.GetKeys().Tournament(clubID, tournament.TournamentID)
+	ctx := ts.redis.GetContext()
+
+	pipe := ts.redis.TxPipeline()
+	pipe.HSet(ctx, tournamentKey, "late_reg_closed", true)
+	entrantsCmd := pipe.HGet(ctx, tournamentKey, "entrants")
+	prizePoolCmd := pipe.HGet(ctx, tournamentKey, "prize_pool")
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		ts.logger.Errorf("Ошибка при закрытии поздней регистрации турнира %s: %v", tournament.TournamentID, err)
+		return 0, fmt.Errorf("ошибка закрытия поздней регистрации: %w", err)
+	}
+
+	if !tournament.LateRegClosed && tournament.LateRegUntilLevel > 0 {
+		ts.logger.Infof("Турнир %s: поздняя регистрация закрыта перед первым выбыванием", tournament.TournamentID)
+	}
+	tournament.LateRegClosed = true
+	tournament.Entrants, _ = strconv.Atoi(entrantsCmd.Val())
+	tournament.PrizePool, _ = strconv.Atoi(prizePoolCmd.Val())
+
+	// Участники, чья регистрация еще не завершена, учтены в entrants и тоже еще в игре
+	remaining := tournament.Entrants
+	for _, entry := range entries {
+		if entry.Status == models.TournamentEntryEliminated {
+			remaining--
+		}
+	}
+
+	return remaining, nil
+}
+
+// eliminate - участник выбывает на месте place: получает приз, если место призовое, и встает из-за стола
+func (ts *TournamentService) eliminate(clubID string, tournament *models.Tournament, entry *models.TournamentEntry, place int, prizes []int, now int64) error {
+	entry.Status = models.TournamentEntryEliminated
+	entry.Place = place
+	entry.EliminatedAt = now
+
+	if err := ts.payPrize(clubID, tournament.TournamentID, entry, prizes); err != nil {
+		return err
+	}
+	if err := ts.saveEntry(clubID, tournament.TournamentID, entry); err != nil {
+		return err
+	}
+	if err := ts.buyInManager.StandUpTournament(clubID, entry.RoomID, entry.UserID, tournament.TournamentID); err != nil {
+		return err
+	}
+
+	ts.actionLogger.LogPlayerEliminated(clubID, entry.RoomID, tournament.TournamentID, entry.UserID, entry.Place, entry.Prize)
+	ts.logger.Infof("Турнир %s: игрок %s выбыл на %d месте (приз %d)",
+		tournament.TournamentID, entry.UserID, entry.Place, entry.Prize)
+
+	return nil
+}
+
+// finish - завершает турнир: оставшийся участник занимает первое место и получает приз,
+// его фишки возвращаются на счет турнирных фишек, столы турнира закрываются
+func (ts *TournamentService) finish(clubID string, tournament *models.Tournament, entries map[string]*models.TournamentEntry, prizes []int) error {
+	var winner *models.TournamentEntry
+	for _, entry := range entries {
+		if entry.IsPlaying() {
+			winner = entry
+			break
+		}
+	}
+	if winner == nil {
+		return ErrTournamentNoWinner
+	}
+
+	winner.Status = models.TournamentEntryWinner
+	winner.Place = 1
+
+	if err := ts.payOwnBounty(clubID, tournament.TournamentID, winner); err != nil {
+		return err
+	}
+
+	if err := ts.buyInManager.StandUpTournament(clubID, winner.RoomID, winner.UserID, tournament.TournamentID); err != nil {
+		return err
+	}
+	if err := ts.payPrize(clubID, tournament.TournamentID, winner, prizes); err != nil {
+		return err
+	}
+	if err := ts.saveEntry(clubID, tournament.TournamentID, winner); err != nil {
+		return err
+	}
+
+	return ts.complete(clubID, tournament, entries, winner)
+}
+
+// complete - переводит турнир в статус finished, закрывает оставшиеся столы
+// и записывает итог турнира (призы участников уже выплачены)
+func (ts *TournamentService) complete(clubID string, tournament *models.Tournament, entries map[string]*models.TournamentEntry, winner *models.TournamentEntry) error {
+	updates := map[string]interface{}{
+		"status":      string(models.TournamentStatusFinished),
+		"finished_at": utils.GetCurrentTimestampMillis(),
+	}
+	if err := ts.redis.HMSet(ts.redis.GetKeys().Tournament(clubID, tournament.TournamentID), updates); err != nil {
+		return fmt.Errorf("ошибка завершения турнира: %w", err)
+	}
+
+	tables, err := ts.GetTables(clubID, tournament.TournamentID)
+	if err != nil {
+		return err
+	}
+	for _, roomID := range tables {
+		if err := ts.closeTable(clubID, tournament.TournamentID, roomID); err != nil {
+			return err
+		}
+	}
+	if err := ts.redis.Del(ts.redis.GetKeys().TournamentHandForHand(clubID, tournament.TournamentID),
+		ts.redis.GetKeys().TournamentDeal(clubID, tournament.TournamentID),
+		ts.redis.GetKeys().TournamentPurchases(clubID, tournament.TournamentID)); err != nil {
+		return err
+	}
+
+	paid := make(map[string]int)
+	for userID, entry := range entries {
+		if entry.Prize > 0 {
+			paid[userID] = entry.Prize
+		}
+	}
+
+	ts.actionLogger.LogTournamentFinished(clubID, winner.RoomID, tournament.TournamentID, winner.UserID, paid)
+	ts.logger.Successf("Турнир %s завершен, победитель %s (приз %d)", tournament.TournamentID, winner.UserID, winner.Prize)
+
+	return nil
+}
+
+// closeTable - закрывает стол турнира и останавливает его часы блайндов
+func (ts *TournamentService) closeTable(clubID, tournamentID, roomID string) error {
+	if err := ts.gameStateService.UpdateRoomStatus(clubID, roomID, models.RoomStatusClosed); err != nil {
+		return err
+	}
+	if err := ts.blindClock.Stop(clubID, roomID); err != nil {
+		return err
+	}
+	if err := ts.redis.SRem(ts.redis.GetKeys().TournamentTables(clubID, tournamentID), roomID); err != nil {
+		return err
+	}
+	return ts.removeHandForHandTable(clubID, tournamentID, roomID)
+}
+
+// payPrize - выплачивает участнику приз за занятое место, если место призовое
+func (ts *TournamentService) payPrize(clubID, tournamentID string, entry *models.TournamentEntry, prizes []int) error {
+	if entry.Place < 1 || entry.Place > len(prizes) || prizes[entry.Place-1] <= 0 {
+		return nil
+	}
+
+	entry.Prize = prizes[entry.Place-1]
+	reference := tournamentReference(tournamentID, "prize", entry.UserID)
+	return ts.ledger.RecordTournamentPrize(clubID, tournamentID, entry.Prize, reference)
+}
+
+// getTournament - получает турнир или возвращает ошибку, если его нет
+func (ts *TournamentService) getTournament(clubID, tournamentID string) (*models.Tournament, error) {
+	tournament, err := ts.GetTournament(clubID, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if tournament == nil {
+		return nil, ErrTournamentNotFound
+	}
+	return tournament, nil
+}
+
+// saveEntry - сохраняет участника турнира
+func (ts *TournamentService) saveEntry(clubID, tournamentID string, entry *models.TournamentEntry) error {
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("ошибка при сериализации участника турнира: %w", err)
+	}
+
+	if err := ts.redis.HSet(ts.redis.GetKeys().TournamentEntries(clubID, tournamentID), entry.UserID, string(entryJSON)); err != nil {
+		ts.logger.Errorf("Ошибка при сохранении участника %s турнира %s: %v", entry.UserID, tournamentID, err)
+		return err
+	}
+
+	return nil
+}
+
+// tournamentReference - формирует ссылку на турнирную операцию для журнала
+func tournamentReference(tournamentID, operation, userID string) string {
+	return fmt.Sprintf("tournament:%s:%s:%s", tournamentID, operation, userID)
+}
+
+var (
+	ErrTournamentNotFound       = &TournamentError{message: "tournament not found"}
+	ErrInvalidTournament        = &TournamentError{message: "invalid tournament settings"}
+	ErrTournamentRoomBusy       = &TournamentError{message: "room is not available for a tournament"}
+	ErrRegistrationClosed       = &TournamentError{message: "tournament registration is closed"}
+	ErrAlreadyRegistered        = &TournamentError{message: "player is already registered"}
+	ErrTournamentFull           = &TournamentError{message: "tournament is full"}
+	ErrTournamentNotRegistering = &TournamentError{message: "tournament has already started"}
+	ErrTournamentNoWinner       = &TournamentError{message: "tournament has no remaining player"}
+
+	ErrTournamentNotRunning = &TournamentError{message: "tournament is not running"}
+	ErrNotFinalTable        = &TournamentError{message: "deals are allowed only at the final table"}
+	ErrInvalidDealMethod    = &TournamentError{message: "invalid deal method"}
+	ErrNoDeal               = &TournamentError{message: "no deal has been proposed"}
+	ErrNotInDeal            = &TournamentError{message: "player is not part of the deal"}
+	ErrDealStale            = &TournamentError{message: "stacks changed since the deal was proposed"}
+
+	ErrNotRegistered      = &TournamentError{message: "player is not playing in this tournament"}
+	ErrRebuyClosed        = &TournamentError{message: "rebuy period is over"}
+	ErrRebuyLimit         = &TournamentError{message: "rebuy limit reached"}
+	ErrRebuyInProgress    = &TournamentError{message: "another rebuy is already in progress"}
+	ErrRebuyStackTooLarge = &TournamentError{message: "rebuy is allowed only at or below the starting stack"}
+	ErrNoAddOn            = &TournamentError{message: "tournament has no add-on"}
+	ErrAddOnTaken         = &TournamentError{message: "add-on has already been taken"}
+	ErrAddOnNotOnBreak    = &TournamentError{message: "add-on is allowed only during a break"}
+)
+
+type TournamentError struct {
+	message string
+}
+
+func (e *TournamentError) Error() string {
+	return "tournament error: " + e.message
+}
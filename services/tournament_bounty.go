@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+)
+
+// awardBounty - выплачивает баунти выбитого участника тем, кто его выбил
+// shares - выигрыш каждого выбившего из последнего банка участника (models.KnockoutShares).
+// В прогрессивном нокауте выбивший получает KnockoutCashPercent процентов своей доли,
+// остальное добавляется к его собственному баунти. Если выбивших нет (неизвестна раздача),
+// баунти переходит в призовой фонд
+func (ts *TournamentService) awardBounty(clubID, roomID string, tournament *models.Tournament, victim *models.TournamentEntry, shares map[string]int, entries map[string]*models.TournamentEntry) error {
+	bounty := victim.Bounty
+	if bounty <= 0 {
+		return nil
+	}
+	victim.Bounty = 0
+	victim.PendingKnockout = nil
+
+	tournamentID := tournament.TournamentID
+	parts := models.SplitBounty(bounty, shares)
+	if len(parts) == 0 {
+		prizePool, err := ts.redis.HIncrBy(ts.redis.GetKeys().Tournament(clubID, tournamentID), "prize_pool", int64(bounty))
+		if err != nil {
+			return fmt.Errorf("ошибка пополнения призового фонда: %w", err)
+		}
+		tournament.PrizePool = int(prizePool)
+
+		ts.logger.Warningf("Турнир %s: не удалось определить, кто выбил игрока %s, баунти %d переходит в призовой фонд",
+			tournamentID, victim.UserID, bounty)
+		return nil
+	}
+
+	for userID, part := range parts {
+		hunter := entries[userID]
+		if hunter == nil {
+			continue
+		}
+
+		cash := part * tournament.KnockoutCashPercent() / 100
+		headIncrease := part - cash
+
+		if cash > 0 {
+			reference := tournamentReference(tournamentID, "bounty:"+victim.UserID, userID)
+			if err := ts.ledger.RecordTournamentBounty(clubID, tournamentID, cash, reference); err != nil {
+				return err
+			}
+		}
+
+		hunter.Bounty += headIncrease
+		hunter.BountiesWon += cash
+		hunter.Knockouts++
+		if err := ts.saveEntry(clubID, tournamentID, hunter); err != nil {
+			return err
+		}
+
+		ts.actionLogger.LogTournamentKnockout(clubID, roomID, tournamentID, userID, victim.UserID, cash, headIncrease)
+		ts.logger.Infof("Турнир %s: игрок %s получил баунти %d за игрока %s (к своей голове +%d)",
+			tournamentID, userID, cash, victim.UserID, headIncrease)
+	}
+
+	return nil
+}
+
+// payOwnBounty - выплачивает участнику его собственный баунти при завершении турнира
+// (победителю или участникам сделки за финальным столом)
+func (ts *TournamentService) payOwnBounty(clubID, tournamentID string, entry *models.TournamentEntry) error {
+	if entry.Bounty <= 0 {
+		return nil
+	}
+
+	reference := tournamentReference(tournamentID, "bounty:"+entry.UserID, entry.UserID)
+	if err := ts.ledger.RecordTournamentBounty(clubID, tournamentID, entry.Bounty, reference); err != nil {
+		return err
+	}
+
+	entry.BountiesWon += entry.Bounty
+	entry.Bounty = 0
+	return nil
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/utils"
+)
+
+// dealField - поле hash предложения сделки, в котором хранится JSON предложения
+// Остальные поля hash - согласие участников (userId -> 1)
+const dealField = "deal"
+
+// dealSettledField - поле hash предложения сделки, которое занимает исполняющий сделку
+// (защита от повторной выплаты, если последние согласия пришли одновременно)
+const dealSettledField = "settled"
+
+// CalculateDeal - рассчитывает сделку за финальным столом по текущим стекам
+// Делятся призы оставшихся мест; призы выбывших участников уже выплачены.
+// Сделка возможна, когда все оставшиеся участники сидят за одним столом и раздача не идет
+func (ts *TournamentService) CalculateDeal(clubID, tournamentID string, method models.DealMethod) (*models.TournamentDeal, string, error) {
+	if !models.IsValidDealMethod(string(method)) {
+		return nil, "", ErrInvalidDealMethod
+	}
+
+	tournament, err := ts.getTournament(clubID, tournamentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !tournament.IsRunning() {
+		return nil, "", ErrTournamentNotRunning
+	}
+
+	tables, err := ts.GetTables(clubID, tournamentID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(tables) != 1 {
+		return nil, "", ErrNotFinalTable
+	}
+	roomID := tables[0]
+
+	busy, err := ts.gameStateService.IsGameActive(clubID, roomID)
+	if err != nil {
+		return nil, "", err
+	}
+	if busy {
+		return nil, "", ErrPlayerInHand
+	}
+
+	entries, err := ts.GetEntries(clubID, tournamentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	deal := &models.TournamentDeal{
+		Method:     method,
+		Stacks:     make(map[string]int),
+		Payouts:    make(map[string]int),
+		ProposedAt: utils.GetCurrentTimestampMillis(),
+	}
+	var userIDs []string
+	var stacks []int
+	for userID, entry := range entries {
+		if !entry.IsPlaying() {
+			continue
+		}
+
+		player, err := ts.gameStateService.GetPlayer(clubID, roomID, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		if player == nil {
+			return nil, "", ErrPlayerNotSeated
+		}
+
+		deal.Stacks[userID] = player.Chips
+		userIDs = append(userIDs, userID)
+		stacks = append(stacks, player.Chips)
+	}
+	if len(userIDs) < 2 {
+		return nil, "", ErrNotFinalTable
+	}
+
+	// Призы оставшихся мест (места без приза - нулевые)
+	prizes := models.CalculatePrizes(tournament.PrizePool, tournament.GetPayoutPercents())
+	remaining := make([]int, len(userIDs))
+	copy(remaining, prizes)
+
+	var payouts []int
+	if method == models.DealMethodICM {
+		payouts = models.CalculateICM(stacks, remaining)
+	} else {
+		payouts = models.CalculateChipChop(stacks, remaining)
+	}
+	for i, userID := range userIDs {
+		deal.Payouts[userID] = payouts[i]
+	}
+
+	return deal, roomID, nil
+}
+
+// ProposeDeal - участник финального стола предлагает сделку
+// Предложивший участник сразу считается согласившимся; предыдущее предложение заменяется
+func (ts *TournamentService) ProposeDeal(clubID, tournamentID, userID string, method models.DealMethod) (*models.TournamentDeal, error) {
+	deal, roomID, err := ts.CalculateDeal(clubID, tournamentID, method)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := deal.Stacks[userID]; !ok {
+		return nil, ErrNotInDeal
+	}
+
+	deal.ProposedBy = userID
+	deal.Accepted = map[string]bool{userID: true}
+
+	dealJSON, err := json.Marshal(deal)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при сериализации сделки: %w", err)
+	}
+
+	dealKey := ts.redis.GetKeys().TournamentDeal(clubID, tournamentID)
+	ctx := ts.redis.GetContext()
+
+	pipe := ts.redis.TxPipeline()
+	pipe.Del(ctx, dealKey)
+	pipe.HSet(ctx, dealKey, dealField, string(dealJSON), userID, 1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		ts.logger.Errorf("Ошибка при сохранении сделки турнира %s: %v", tournamentID, err)
+		return nil, fmt.Errorf("ошибка сохранения сделки: %w", err)
+	}
+
+	ts.actionLogger.LogDealProposed(clubID, roomID, tournamentID, userID, string(method), deal.Payouts)
+	ts.logger.Infof("Турнир %s: игрок %s предлагает сделку (%s)", tournamentID, userID, method)
+
+	return deal, nil
+}
+
+// GetDeal - возвращает текущее предложение сделки с согласиями участников (nil, если предложения нет)
+func (ts *TournamentService) GetDeal(clubID, tournamentID string) (*models.TournamentDeal, error) {
+	data, err := ts.redis.HGetAll(ts.redis.GetKeys().TournamentDeal(clubID, tournamentID))
+	if err != nil {
+		return nil, err
+	}
+	if data[dealField] == "" {
+		return nil, nil
+	}
+
+	var deal models.TournamentDeal
+	if err := json.Unmarshal([]byte(data[dealField]), &deal); err != nil {
+		return nil, fmt.Errorf("ошибка при парсинге сделки: %w", err)
+	}
+
+	deal.Accepted = make(map[string]bool, len(data)-1)
+	for field := range data {
+		if field != dealField && field != dealSettledField {
+			deal.Accepted[field] = true
+		}
+	}
+
+	return &deal, nil
+}
+
+// AcceptDeal - участник соглашается на сделку
+// Когда согласились все, сделка исполняется: призы выплачиваются через журнал,
+// места распределяются по стекам и турнир завершается. Если стеки изменились
+// после предложения (была сыграна раздача), сделка отменяется
+// Возвращает true, если сделка исполнена
+func (ts *TournamentService) AcceptDeal(clubID, tournamentID, userID string) (bool, error) {
+	deal, err := ts.GetDeal(clubID, tournamentID)
+	if err != nil {
+		return false, err
+	}
+	if deal == nil {
+		return false, ErrNoDeal
+	}
+	if _, ok := deal.Stacks[userID]; !ok {
+		return false, ErrNotInDeal
+	}
+
+	current, roomID, err := ts.CalculateDeal(clubID, tournamentID, deal.Method)
+	if err != nil {
+		return false, err
+	}
+	if !sameStacks(deal.Stacks, current.Stacks) {
+		if err := ts.DeclineDeal(clubID, tournamentID, userID, "stacks_changed"); err != nil {
+			return false, err
+		}
+		return false, ErrDealStale
+	}
+
+	// Согласие записывается и все согласия читаются в одной транзакции: из одновременно
+	// пришедших последних согласий все согласия увидит хотя бы одно
+	dealKey := ts.redis.GetKeys().TournamentDeal(clubID, tournamentID)
+	ctx := ts.redis.GetContext()
+	pipe := ts.redis.TxPipeline()
+	pipe.HSet(ctx, dealKey, userID, 1)
+	fieldsCmd := pipe.HGetAll(ctx, dealKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("ошибка сохранения согласия на сделку: %w", err)
+	}
+
+	fields := fieldsCmd.Val()
+	if fields[dealField] == "" {
+		// Сделку успели отменить - убираем записанное согласие
+		ts.redis.Del(dealKey)
+		return false, ErrNoDeal
+	}
+	for field := range fields {
+		if field != dealField && field != dealSettledField {
+			deal.Accepted[field] = true
+		}
+	}
+
+	ts.actionLogger.LogDealAccepted(clubID, roomID, tournamentID, userID)
+	ts.logger.Infof("Турнир %s: игрок %s согласен на сделку", tournamentID, userID)
+
+	if !deal.AllAccepted() {
+		return false, nil
+	}
+
+	return true, ts.settleDeal(clubID, tournamentID, deal)
+}
+
+// DeclineDeal - отменяет предложение сделки (отказ участника или изменение стеков)
+func (ts *TournamentService) DeclineDeal(clubID, tournamentID, userID, reason string) error {
+	deal, err := ts.GetDeal(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+	if deal == nil {
+		return ErrNoDeal
+	}
+
+	if err := ts.redis.Del(ts.redis.GetKeys().TournamentDeal(clubID, tournamentID)); err != nil {
+		return err
+	}
+
+	tables, err := ts.GetTables(clubID, tournamentID)
+	if err == nil && len(tables) > 0 {
+		ts.actionLogger.LogDealCancelled(clubID, tables[0], tournamentID, userID, reason)
+	}
+	ts.logger.Infof("Турнир %s: сделка отменена (%s)", tournamentID, reason)
+
+	return nil
+}
+
+// settleDeal - исполняет сделку: выплачивает суммы по сделке и завершает турнир
+// Места распределяются по убыванию стеков, фишки возвращаются на счет турнирных фишек
+// Сделку исполняет только тот, кто первым занял поле settled: повторный вызов ничего не делает
+func (ts *TournamentService) settleDeal(clubID, tournamentID string, deal *models.TournamentDeal) error {
+	first, err := ts.redis.HSetNX(ts.redis.GetKeys().TournamentDeal(clubID, tournamentID), dealSettledField, 1)
+	if err != nil {
+		return fmt.Errorf("ошибка исполнения сделки: %w", err)
+	}
+	if !first {
+		ts.logger.Infof("Турнир %s: сделка уже исполняется", tournamentID)
+		return nil
+	}
+
+	// Исполненная сделка удаляется вместе с завершением турнира, поэтому
+	// опоздавший вызов может снова занять поле settled - турнир к этому времени уже завершен
+	tournament, err := ts.getTournament(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+	if !tournament.IsRunning() {
+		return nil
+	}
+
+	entries, err := ts.GetEntries(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetCurrentTimestampMillis()
+	var winner *models.TournamentEntry
+	for i, userID := range deal.PlacesByStack() {
+		entry := entries[userID]
+		if entry == nil {
+			continue
+		}
+
+		entry.Place = i + 1
+		entry.Prize = deal.Payouts[userID]
+		if i == 0 {
+			entry.Status = models.TournamentEntryWinner
+			winner = entry
+		} else {
+			entry.Status = models.TournamentEntryEliminated
+			entry.EliminatedAt = now
+		}
+
+		if err := ts.buyInManager.StandUpTournament(clubID, entry.RoomID, userID, tournamentID); err != nil {
+			return err
+		}
+		if err := ts.payOwnBounty(clubID, tournamentID, entry); err != nil {
+			return err
+		}
+		if entry.Prize > 0 {
+			reference := tournamentReference(tournamentID, "deal", userID)
+			if err := ts.ledger.RecordTournamentPrize(clubID, tournamentID, entry.Prize, reference); err != nil {
+				return err
+			}
+		}
+		if err := ts.saveEntry(clubID, tournamentID, entry); err != nil {
+			return err
+		}
+	}
+	if winner == nil {
+		return ErrTournamentNoWinner
+	}
+
+	ts.logger.Successf("Турнир %s: сделка (%s) исполнена", tournamentID, deal.Method)
+
+	return ts.complete(clubID, tournament, entries, winner)
+}
+
+// sameStacks - проверяет, что стеки участников не изменились
+func sameStacks(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for userID, stack := range a {
+		if b[userID] != stack {
+			return false
+		}
+	}
+	return true
+}
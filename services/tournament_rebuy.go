@@ -0,0 +1,277 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"poker-engine/models"
+	"poker-engine/utils"
+)
+
+// Rebuy - ребай участника турнира в период ребаев
+// Ребай стоит столько же, сколько вход (BuyIn+Fee), и дает стартовый стек. Доступен, пока
+// стек участника (с учетом поставленных в раздаче и отложенных фишек) не больше стартового
+// и не исчерпан лимит MaxRebuys. Во время раздачи фишки зачисляются после ее окончания.
+// Номер ребая занимается атомарно до оплаты: из одновременных запросов проходит только один
+func (ts *TournamentService) Rebuy(clubID, tournamentID, userID string) error {
+	tournament, entry, player, err := ts.getPlayingEntry(clubID, tournamentID, userID)
+	if err != nil {
+		return err
+	}
+
+	level, err := ts.currentBlindLevel(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+	if !tournament.IsRebuyPeriod(level) {
+		return ErrRebuyClosed
+	}
+	if !tournament.CanRebuy(entry) {
+		return ErrRebuyLimit
+	}
+	if player.GetTotalChips()+player.PendingChips > tournament.StartingStack {
+		return ErrRebuyStackTooLarge
+	}
+
+	purchasesKey := ts.redis.GetKeys().TournamentPurchases(clubID, tournamentID)
+	rebuyField := "rebuys:" + userID
+
+	// Счетчик ребаев идет вровень с entry.Rebuys: если он ушел дальше, ребай уже делает другой запрос
+	rebuys, err := ts.redis.HIncrBy(purchasesKey, rebuyField, 1)
+	if err != nil {
+		return fmt.Errorf("ошибка ребая: %w", err)
+	}
+	if tournament.MaxRebuys > 0 && int(rebuys) > tournament.MaxRebuys {
+		ts.redis.HIncrBy(purchasesKey, rebuyField, -1)
+		return ErrRebuyLimit
+	}
+	if int(rebuys) != entry.Rebuys+1 {
+		ts.redis.HIncrBy(purchasesKey, rebuyField, -1)
+		return ErrRebuyInProgress
+	}
+
+	reference := tournamentReference(tournamentID, fmt.Sprintf("rebuy:%d", rebuys), userID)
+	if err := ts.ledger.RecordTournamentRebuy(clubID, tournamentID, tournament.BuyIn, tournament.Fee, reference); err != nil {
+		ts.redis.HIncrBy(purchasesKey, rebuyField, -1)
+		return err
+	}
+
+	// Баунти-часть ребая добавляется к голове участника; раз он остался в игре, выбивших нет
+	entry.Rebuys = int(rebuys)
+	entry.Bounty += tournament.Bounty
+	entry.PendingKnockout = nil
+	return ts.purchaseChips(clubID, tournament, entry, &models.TournamentAuditRecord{
+		Operation: models.TournamentAuditRebuy,
+		UserID:    userID,
+		Paid:      tournament.BuyIn + tournament.Fee,
+		PrizePart: tournament.PrizePoolPart(),
+		Chips:     tournament.StartingStack,
+		Level:     level,
+	})
+}
+
+// AddOn - аддон участника турнира: одноразовая докупка AddOnChips фишек за AddOnCost
+// Доступен только во время перерыва за столом участника. Право на аддон занимается атомарно
+// до оплаты: из одновременных запросов проходит только один
+func (ts *TournamentService) AddOn(clubID, tournamentID, userID string) error {
+	tournament, entry, _, err := ts.getPlayingEntry(clubID, tournamentID, userID)
+	if err != nil {
+		return err
+	}
+	if !tournament.HasAddOn() {
+		return ErrNoAddOn
+	}
+	if entry.AddOn {
+		return ErrAddOnTaken
+	}
+
+	clock, err := ts.blindClock.GetClock(clubID, entry.RoomID)
+	if err != nil {
+		return err
+	}
+	if clock == nil {
+		return ErrAddOnNotOnBreak
+	}
+	clock.Advance(utils.GetCurrentTimestampMillis())
+	if !clock.IsOnBreak() {
+		return ErrAddOnNotOnBreak
+	}
+
+	purchasesKey := ts.redis.GetKeys().TournamentPurchases(clubID, tournamentID)
+	addOnField := "add_on:" + userID
+	claimed, err := ts.redis.HSetNX(purchasesKey, addOnField, 1)
+	if err != nil {
+		return fmt.Errorf("ошибка аддона: %w", err)
+	}
+	if !claimed {
+		return ErrAddOnTaken
+	}
+
+	reference := tournamentReference(tournamentID, "add_on", userID)
+	if err := ts.ledger.RecordTournamentAddOn(clubID, tournamentID, tournament.AddOnCost, reference); err != nil {
+		ts.redis.HDel(purchasesKey, addOnField)
+		return err
+	}
+
+	entry.AddOn = true
+	return ts.purchaseChips(clubID, tournament, entry, &models.TournamentAuditRecord{
+		Operation: models.TournamentAuditAddOn,
+		UserID:    userID,
+		Paid:      tournament.AddOnCost,
+		PrizePart: tournament.AddOnCost,
+		Chips:     tournament.AddOnChips,
+		Level:     clock.BlindLevelNumber(),
+	})
+}
+
+// DeclineRebuy - участник, проигравший все фишки в период ребаев, отказывается от ребая и выбывает
+// Выбывание закрывает позднюю регистрацию (см. closeLateRegistration)
+func (ts *TournamentService) DeclineRebuy(clubID, tournamentID, userID string) error {
+	tournament, entry, player, err := ts.getPlayingEntry(clubID, tournamentID, userID)
+	if err != nil {
+		return err
+	}
+	if player.HasChips() || player.HasPendingChips() || player.TotalBet > 0 {
+		return ErrNotBusted
+	}
+
+	entries, err := ts.GetEntries(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	entry = entries[userID]
+	if err := ts.awardBounty(clubID, entry.RoomID, tournament, entry, entry.PendingKnockout, entries); err != nil {
+		return err
+	}
+
+	// Место и приз закрепляются только после закрытия поздней регистрации
+	remaining, err := ts.closeLateRegistration(clubID, tournament, entries)
+	if err != nil {
+		return err
+	}
+
+	prizes := models.CalculatePrizes(tournament.PrizePool, tournament.GetPayoutPercents())
+	if err := ts.eliminate(clubID, tournament, entry, remaining, prizes, utils.GetCurrentTimestampMillis()); err != nil {
+		return err
+	}
+
+	if remaining-1 == 1 {
+		return ts.finish(clubID, tournament, entries, prizes)
+	}
+	return ts.Rebalance(clubID, tournamentID)
+}
+
+// GetAudit - журнал аудита турнира: регистрации, ребаи и аддоны в порядке совершения
+func (ts *TournamentService) GetAudit(clubID, tournamentID string) ([]*models.TournamentAuditRecord, error) {
+	data, err := ts.redis.LRange(ts.redis.GetKeys().TournamentAudit(clubID, tournamentID), 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*models.TournamentAuditRecord, 0, len(data))
+	for _, recordJSON := range data {
+		var record models.TournamentAuditRecord
+		if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+			ts.logger.Warningf("Ошибка при парсинге записи аудита турнира %s: %v", tournamentID, err)
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// purchaseChips - пополняет призовой фонд оплаченным ребаем или аддоном (платеж уже проведен
+// через журнал), выдает фишки, сохраняет участника и записывает операцию в аудит
+func (ts *TournamentService) purchaseChips(clubID string, tournament *models.Tournament, entry *models.TournamentEntry, record *models.TournamentAuditRecord) error {
+	tournamentID := tournament.TournamentID
+
+	prizePool, err := ts.redis.HIncrBy(ts.redis.GetKeys().Tournament(clubID, tournamentID), "prize_pool", int64(record.PrizePart))
+	if err != nil {
+		return fmt.Errorf("ошибка пополнения призового фонда: %w", err)
+	}
+	record.PrizePool = int(prizePool)
+
+	operation := BuyInOperationRebuy
+	if record.Operation == models.TournamentAuditAddOn {
+		operation = BuyInOperationAddOn
+	}
+	if err := ts.buyInManager.AddTournamentChips(clubID, entry.RoomID, entry.UserID, tournamentID, record.Chips, operation); err != nil {
+		return err
+	}
+	if err := ts.saveEntry(clubID, tournamentID, entry); err != nil {
+		return err
+	}
+
+	ts.recordAudit(clubID, tournamentID, record)
+
+	ts.actionLogger.LogTournamentChipsPurchased(clubID, entry.RoomID, tournamentID, entry.UserID, record.Operation, record.Chips, record.PrizePool)
+	ts.logger.Infof("Турнир %s: %s игрока %s на %d фишек (призовой фонд: %d)",
+		tournamentID, record.Operation, entry.UserID, record.Chips, record.PrizePool)
+
+	return nil
+}
+
+// recordAudit - добавляет запись в журнал аудита турнира
+// Ошибка записи аудита не отменяет уже проведенную операцию и только логируется
+func (ts *TournamentService) recordAudit(clubID, tournamentID string, record *models.TournamentAuditRecord) {
+	record.Timestamp = utils.GetCurrentTimestampMillis()
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		ts.logger.Errorf("Ошибка при сериализации записи аудита турнира %s: %v", tournamentID, err)
+		return
+	}
+
+	if err := ts.redis.RPush(ts.redis.GetKeys().TournamentAudit(clubID, tournamentID), string(recordJSON)); err != nil {
+		ts.logger.Errorf("Ошибка при записи аудита турнира %s: %v", tournamentID, err)
+	}
+}
+
+// currentBlindLevel - номер текущего уровня блайндов турнира без учета перерывов
+// Часы всех столов запущены одновременно, поэтому уровень берется по первому столу (0 - часы не запущены)
+func (ts *TournamentService) currentBlindLevel(clubID, tournamentID string) (int, error) {
+	tables, err := ts.GetTables(clubID, tournamentID)
+	if err != nil || len(tables) == 0 {
+		return 0, err
+	}
+
+	clock, err := ts.blindClock.GetClock(clubID, tables[0])
+	if err != nil || clock == nil {
+		return 0, err
+	}
+
+	clock.Advance(utils.GetCurrentTimestampMillis())
+	return clock.BlindLevelNumber(), nil
+}
+
+// getPlayingEntry - получает идущий турнир, участника, который еще в игре, и его место за столом
+func (ts *TournamentService) getPlayingEntry(clubID, tournamentID, userID string) (*models.Tournament, *models.TournamentEntry, *models.Player, error) {
+	tournament, err := ts.getTournament(clubID, tournamentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !tournament.IsRunning() {
+		return nil, nil, nil, ErrTournamentNotRunning
+	}
+
+	entries, err := ts.GetEntries(clubID, tournamentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	entry := entries[userID]
+	if entry == nil || !entry.IsPlaying() {
+		return nil, nil, nil, ErrNotRegistered
+	}
+
+	player, err := ts.gameStateService.GetPlayer(clubID, entry.RoomID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if player == nil {
+		return nil, nil, nil, ErrPlayerNotSeated
+	}
+
+	return tournament, entry, player, nil
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"fmt"
+
+	"poker-engine/models"
+)
+
+// CreateMultiTable - создает многостольный турнир на пустых комнатах клуба и открывает регистрацию
+// Участники рассаживаются за наименее заполненные столы; MaxPlayers не может превышать
+// общее количество мест за столами
+func (ts *TournamentService) CreateMultiTable(tournament *models.Tournament, roomIDs []string) error {
+	if err := validateTournament(tournament); err != nil {
+		return err
+	}
+	if len(roomIDs) < 2 {
+		return ErrInvalidTournament
+	}
+
+	seats, err := ts.checkTables(tournament.ClubID, roomIDs)
+	if err != nil {
+		return err
+	}
+	if tournament.MaxPlayers > seats {
+		return ErrTournamentRoomBusy
+	}
+
+	tournament.Type = models.TournamentMultiTable
+	tournament.RoomID = ""
+	if err := ts.create(tournament, roomIDs); err != nil {
+		return err
+	}
+
+	ts.logger.Infof("Создан многостольный турнир %s в клубе %s (%d столов, %d мест, buy-in %d+%d)",
+		tournament.TournamentID, tournament.ClubID, len(roomIDs), tournament.MaxPlayers, tournament.BuyIn, tournament.Fee)
+
+	return nil
+}
+
+// Rebalance - закрывает лишние столы и пересаживает игроков, чтобы столы отличались
+// не больше чем на одного игрока
+// Пересадки выполняются только между столами, где раздача не идет: игрок не покидает
+// раздачу и не садится за стол посреди раздачи. Остальные пересадки выполнятся после
+// окончания раздачи за этими столами (ProcessEliminations вызывает Rebalance после
+// каждой раздачи). Со стола пересаживаются игроки, которым предстоит
+// большой блайнд, и садятся на место, которое сразу попадает на блайнды
+func (ts *TournamentService) Rebalance(clubID, tournamentID string) error {
+	tournament, err := ts.getTournament(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+	if !tournament.IsRunning() || !tournament.IsMultiTable() {
+		return nil
+	}
+
+	tables, err := ts.GetTables(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int, len(tables))
+	seatsPerTable := 0
+	for _, roomID := range tables {
+		room, err := ts.gameStateService.GetRoomInfo(clubID, roomID)
+		if err != nil || room == nil {
+			return fmt.Errorf("не удалось получить информацию о столе %s: %v", roomID, err)
+		}
+		if seatsPerTable == 0 || room.MaxPlayers < seatsPerTable {
+			seatsPerTable = room.MaxPlayers
+		}
+
+		count, err := ts.gameStateService.GetPlayersCount(clubID, roomID)
+		if err != nil {
+			return err
+		}
+		counts[roomID] = int(count)
+	}
+
+	plan := models.PlanTableBalance(counts, seatsPerTable)
+	if plan.IsEmpty() {
+		return nil
+	}
+
+	entries, err := ts.GetEntries(clubID, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	for _, move := range plan.Moves {
+		busy, err := ts.gameStateService.IsGameActive(clubID, move.FromRoomID)
+		if err != nil {
+			return err
+		}
+		if busy {
+			ts.logger.Debugf("Турнир %s: пересадка со стола %s отложена до конца раздачи", tournamentID, move.FromRoomID)
+			continue
+		}
+
+		busy, err = ts.gameStateService.IsGameActive(clubID, move.ToRoomID)
+		if err != nil {
+			return err
+		}
+		if busy {
+			ts.logger.Debugf("Турнир %s: пересадка за стол %s отложена до конца раздачи", tournamentID, move.ToRoomID)
+			continue
+		}
+
+		movers, err := ts.nextBigBlinds(clubID, move.FromRoomID, move.Count)
+		if err != nil {
+			return err
+		}
+		for _, userID := range movers {
+			entry := entries[userID]
+			if entry == nil {
+				continue
+			}
+			if err := ts.movePlayer(clubID, tournamentID, entry, move.ToRoomID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, roomID := range plan.Breaks {
+		count, err := ts.gameStateService.GetPlayersCount(clubID, roomID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := ts.closeTable(clubID, tournamentID, roomID); err != nil {
+			return err
+		}
+
+		ts.actionLogger.LogTournamentTableBroken(clubID, roomID, tournamentID)
+		ts.logger.Infof("Турнир %s: стол %s закрыт", tournamentID, roomID)
+	}
+
+	return nil
+}
+
+// nextBigBlinds - первые count игроков стола в порядке, в котором им предстоит большой блайнд
+func (ts *TournamentService) nextBigBlinds(clubID, roomID string, count int) ([]string, error) {
+	seats, err := ts.gameStateService.GetSeatedPlayers(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := ts.gameStateService.GetGameState(clubID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	dealerPosition := -1
+	if game != nil {
+		dealerPosition = game.DealerPosition
+	}
+
+	order := models.UserIDs(models.NextBigBlindOrder(seats, dealerPosition))
+	if count < len(order) {
+		order = order[:count]
+	}
+	return order, nil
+}
+
+// movePlayer - пересаживает участника на другой стол с его текущим стеком
+func (ts *TournamentService) movePlayer(clubID, tournamentID string, entry *models.TournamentEntry, toRoomID string) error {
+	fromRoomID := entry.RoomID
+
+	player, err := ts.gameStateService.GetPlayer(clubID, fromRoomID, entry.UserID)
+	if err != nil {
+		return err
+	}
+	if player == nil {
+		return ErrPlayerNotSeated
+	}
+	stack := player.Chips
+
+	room, err := ts.gameStateService.GetRoomInfo(clubID, toRoomID)
+	if err != nil || room == nil {
+		return fmt.Errorf("не удалось получить информацию о столе %s: %v", toRoomID, err)
+	}
+	seats, err := ts.gameStateService.GetSeatedPlayers(clubID, toRoomID)
+	if err != nil {
+		return err
+	}
+	game, err := ts.gameStateService.GetGameState(clubID, toRoomID)
+	if err != nil {
+		return err
+	}
+	dealerPosition := -1
+	if game != nil {
+		dealerPosition = game.DealerPosition
+	}
+
+	seat := models.BlindFairSeat(seats, dealerPosition, room.MaxPlayers)
+	if seat < 0 {
+		return ErrTournamentRoomBusy
+	}
+
+	if err := ts.buyInManager.StandUpTournament(clubID, fromRoomID, entry.UserID, tournamentID); err != nil {
+		return err
+	}
+	if _, err := ts.buyInManager.SitDownTournament(clubID, toRoomID, entry.UserID, entry.Username, tournamentID, seat, stack); err != nil {
+		return err
+	}
+
+	entry.RoomID = toRoomID
+	if err := ts.saveEntry(clubID, tournamentID, entry); err != nil {
+		return err
+	}
+
+	ts.actionLogger.LogTournamentPlayerMoved(clubID, fromRoomID, tournamentID, entry.UserID, fromRoomID, toRoomID, seat)
+	ts.actionLogger.LogTournamentPlayerMoved(clubID, toRoomID, tournamentID, entry.UserID, fromRoomID, toRoomID, seat)
+	ts.logger.Infof("Турнир %s: игрок %s пересажен со стола %s за стол %s (место %d, стек %d)",
+		tournamentID, entry.UserID, fromRoomID, toRoomID, seat, stack)
+
+	return nil
+}
+
+// updateHandForHand - включает и выключает игру рука в руку и отмечает столы, доигравшие раздачу
+// Игра рука в руку начинается, когда до призовых мест остается один игрок, и заканчивается,
+// когда пузырь лопается. Стол, доигравший раздачу, ждет остальные столы (RoomMonitor
+// не начинает за ним раздачу), пока раздачу не доиграют все столы
+func (ts *TournamentService) updateHandForHand(clubID string, tournament *models.Tournament, roomID string, remaining int) error {
+	keys := ts.redis.GetKeys()
+	tournamentKey := keys.Tournament(clubID, tournament.TournamentID)
+	handForHandKey := keys.TournamentHandForHand(clubID, tournament.TournamentID)
+	paidPlaces := tournament.PaidPlaces()
+
+	tables, err := ts.GetTables(clubID, tournament.TournamentID)
+	if err != nil {
+		return err
+	}
+
+	if !tournament.HandForHand {
+		if remaining != paidPlaces+1 || len(tables) < 2 {
+			return nil
+		}
+		if err := ts.redis.Del(handForHandKey); err != nil {
+			return err
+		}
+		if err := ts.redis.HSet(tournamentKey, "hand_for_hand", true); err != nil {
+			return fmt.Errorf("ошибка включения игры рука в руку: %w", err)
+		}
+
+		for _, table := range tables {
+			ts.actionLogger.LogHandForHand(clubID, table, tournament.TournamentID, true)
+		}
+		ts.logger.Infof("Турнир %s: призовой пузырь, игра рука в руку (осталось %d)", tournament.TournamentID, remaining)
+		return nil
+	}
+
+	if remaining <= paidPlaces || len(tables) < 2 {
+		if err := ts.redis.HSet(tournamentKey, "hand_for_hand", false); err != nil {
+			return fmt.Errorf("ошибка выключения игры рука в руку: %w", err)
+		}
+		if err := ts.redis.Del(handForHandKey); err != nil {
+			return err
+		}
+
+		for _, table := range tables {
+			ts.actionLogger.LogHandForHand(clubID, table, tournament.TournamentID, false)
+		}
+		ts.logger.Infof("Турнир %s: пузырь лопнул, игра рука в руку закончена", tournament.TournamentID)
+		return nil
+	}
+
+	if err := ts.redis.SAdd(handForHandKey, roomID); err != nil {
+		return err
+	}
+	return ts.finishHandForHandRound(clubID, tournament.TournamentID, len(tables))
+}
+
+// removeHandForHandTable - убирает закрытый стол из столов, доигравших раздачу рука в руку
+// Если закрытый стол был последним, кого ждали остальные, начинается следующая раздача
+func (ts *TournamentService) removeHandForHandTable(clubID, tournamentID, roomID string) error {
+	keys := ts.redis.GetKeys()
+	if err := ts.redis.SRem(keys.TournamentHandForHand(clubID, tournamentID), roomID); err != nil {
+		return err
+	}
+
+	tables, err := ts.redis.SCard(keys.TournamentTables(clubID, tournamentID))
+	if err != nil {
+		return err
+	}
+	if tables == 0 {
+		return nil
+	}
+	return ts.finishHandForHandRound(clubID, tournamentID, int(tables))
+}
+
+// finishHandForHandRound - начинает следующую раздачу рука в руку, если ее доиграли все столы
+func (ts *TournamentService) finishHandForHandRound(clubID, tournamentID string, tables int) error {
+	handForHandKey := ts.redis.GetKeys().TournamentHandForHand(clubID, tournamentID)
+
+	done, err := ts.redis.SCard(handForHandKey)
+	if err != nil {
+		return err
+	}
+	if done > 0 && int(done) >= tables {
+		// Все столы доиграли раздачу - начинается следующая раздача рука в руку
+		return ts.redis.Del(handForHandKey)
+	}
+
+	return nil
+}
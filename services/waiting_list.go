@@ -0,0 +1,445 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"poker-engine/models"
+	"poker-engine/storage"
+	"poker-engine/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Причины удаления из листа ожидания
+const (
+	WaitingListReasonLeft         = "left"          // Игрок сам покинул лист ожидания
+	WaitingListReasonDeclined     = "declined"      // Игрок отказался от предложенного места
+	WaitingListReasonOfferExpired = "offer_expired" // Игрок не принял место вовремя
+	WaitingListReasonSeated       = "seated"        // Игрок сел за стол в клубе
+)
+
+// WaitingListService - сервис листов ожидания комнат
+// Лист ожидания - FIFO очередь (ZSET по времени записи). Когда за столом
+// освобождается место, оно резервируется и предлагается первому в очереди
+// на offerTimeout. Игрок может стоять в листах ожидания нескольких комнат;
+// как только он садится за стол в клубе (UserCurrentRoom), он удаляется из всех
+type WaitingListService struct {
+	// redis - клиент для работы с Redis
+	redis *storage.RedisClient
+
+	// gameStateService - сервис состояния игры
+	gameStateService *GameStateService
+
+	// buyInManager - сервис посадки за стол
+	buyInManager *BuyInManager
+
+	// seatManager - сервис управления местами
+	seatManager *SeatManager
+
+	// actionLogger - сервис для записи действий
+	actionLogger *ActionLogger
+
+	// logger - логгер для вывода сообщений
+	logger *utils.Logger
+
+	// offerTimeout - время на принятие предложенного места
+	offerTimeout time.Duration
+}
+
+// NewWaitingListService - создает новый экземпляр WaitingListService
+func NewWaitingListService(
+	redis *storage.RedisClient,
+	gameStateService *GameStateService,
+	buyInManager *BuyInManager,
+	seatManager *SeatManager,
+	actionLogger *ActionLogger,
+	offerTimeout time.Duration,
+) *WaitingListService {
+	return &WaitingListService{
+		redis:            redis,
+		gameStateService: gameStateService,
+		buyInManager:     buyInManager,
+		seatManager:      seatManager,
+		actionLogger:     actionLogger,
+		logger:           utils.NewLogger("WaitingList"),
+		offerTimeout:     offerTimeout,
+	}
+}
+
+// Join - записывает игрока в лист ожидания комнаты
+// Повторная запись не меняет место в очереди
+// Возвращает позицию игрока в очереди (1 - следующий)
+func (ws *WaitingListService) Join(clubID, roomID, userID string) (int, error) {
+	room, err := ws.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil {
+		return 0, err
+	}
+	if room == nil {
+		return 0, ErrRoomNotFound
+	}
+
+	isSeated, err := ws.isSeatedInClub(clubID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if isSeated {
+		return 0, ErrAlreadySeated
+	}
+
+	keys := ws.redis.GetKeys()
+	ctx := ws.redis.GetContext()
+
+	pipe := ws.redis.TxPipeline()
+	pipe.ZAddNX(ctx, keys.RoomWaitingList(clubID, roomID), redis.Z{
+		Score:  float64(utils.GetCurrentTimestampMillis()),
+		Member: userID,
+	})
+	pipe.SAdd(ctx, keys.UserWaitingLists(clubID, userID), roomID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		ws.logger.Errorf("Ошибка при записи игрока %s в лист ожидания %s:%s: %v", userID, clubID, roomID, err)
+		return 0, fmt.Errorf("ошибка записи в лист ожидания: %w", err)
+	}
+
+	position, err := ws.GetPosition(clubID, roomID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	ws.actionLogger.LogWaitingListJoined(clubID, roomID, userID, position)
+	ws.logger.Infof("Игрок %s записан в лист ожидания %s:%s (позиция %d)", userID, clubID, roomID, position)
+
+	// Если за столом есть свободное место - сразу предлагаем его
+	if _, err := ws.OfferSeats(clubID, roomID); err != nil {
+		ws.logger.Warningf("Не удалось предложить места в комнате %s:%s: %v", clubID, roomID, err)
+	}
+
+	return position, nil
+}
+
+// Leave - удаляет игрока из листа ожидания комнаты
+// Если игроку уже было предложено место, оно передается следующему
+func (ws *WaitingListService) Leave(clubID, roomID, userID string) error {
+	return ws.dropFromRoom(clubID, roomID, userID, WaitingListReasonLeft)
+}
+
+// AcceptOffer - игрок принимает предложенное место и садится за стол
+// После посадки игрок удаляется из всех листов ожидания клуба
+func (ws *WaitingListService) AcceptOffer(clubID, roomID, userID, username string, amount int) error {
+	offer, err := ws.GetOffer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if offer == nil {
+		return ErrNoSeatOffer
+	}
+
+	if offer.IsExpired(utils.GetCurrentTimestampMillis()) {
+		ws.dropFromRoom(clubID, roomID, userID, WaitingListReasonOfferExpired)
+		return ErrSeatOfferExpired
+	}
+
+	if err := ws.buyInManager.SitDownReserved(clubID, roomID, userID, username, offer.Seat, amount); err != nil {
+		return err
+	}
+
+	if err := ws.redis.HDel(ws.redis.GetKeys().RoomSeatOffers(clubID, roomID), userID); err != nil {
+		return err
+	}
+
+	return ws.RemoveFromAll(clubID, userID)
+}
+
+// DeclineOffer - игрок отказывается от предложенного места
+// Игрок теряет место в очереди, а место предлагается следующему
+func (ws *WaitingListService) DeclineOffer(clubID, roomID, userID string) error {
+	offer, err := ws.GetOffer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if offer == nil {
+		return ErrNoSeatOffer
+	}
+
+	return ws.dropFromRoom(clubID, roomID, userID, WaitingListReasonDeclined)
+}
+
+// RemoveFromAll - удаляет игрока из листов ожидания всех комнат клуба
+// Предложенные ему места в других комнатах передаются следующим в очереди
+func (ws *WaitingListService) RemoveFromAll(clubID, userID string) error {
+	keys := ws.redis.GetKeys()
+
+	roomIDs, err := ws.redis.SMembers(keys.UserWaitingLists(clubID, userID))
+	if err != nil {
+		return err
+	}
+
+	currentRoom, err := ws.redis.Get(keys.UserCurrentRoom(clubID, userID))
+	if err != nil {
+		return err
+	}
+
+	for _, roomID := range roomIDs {
+		// В комнате, где игрок уже сидит, предложенное ему место освобождается,
+		// если только это не то место, которое он занял
+		if roomID == currentRoom {
+			if err := ws.releaseOfferedSeat(clubID, roomID, userID); err != nil {
+				ws.logger.Warningf("Не удалось снять резерв места игрока %s в комнате %s:%s: %v", userID, clubID, roomID, err)
+			}
+			ws.redis.HDel(keys.RoomSeatOffers(clubID, roomID), userID)
+			ws.redis.ZRem(keys.RoomWaitingList(clubID, roomID), userID)
+			ws.actionLogger.LogWaitingListLeft(clubID, roomID, userID, WaitingListReasonSeated)
+			continue
+		}
+
+		if err := ws.dropFromRoom(clubID, roomID, userID, WaitingListReasonSeated); err != nil {
+			ws.logger.Warningf("Не удалось удалить игрока %s из листа ожидания %s:%s: %v", userID, clubID, roomID, err)
+		}
+	}
+
+	return ws.redis.Del(keys.UserWaitingLists(clubID, userID))
+}
+
+// OfferSeats - предлагает свободные места следующим игрокам из листа ожидания
+// Игроки, которые уже сидят за столом в клубе, удаляются из всех листов ожидания
+// Возвращает количество сделанных предложений
+func (ws *WaitingListService) OfferSeats(clubID, roomID string) (int, error) {
+	room, err := ws.gameStateService.GetRoomInfo(clubID, roomID)
+	if err != nil {
+		return 0, err
+	}
+	if room == nil {
+		return 0, ErrRoomNotFound
+	}
+
+	keys := ws.redis.GetKeys()
+
+	waiting, err := ws.redis.ZRange(keys.RoomWaitingList(clubID, roomID), 0, -1)
+	if err != nil {
+		return 0, err
+	}
+
+	offers, err := ws.redis.HGetAll(keys.RoomSeatOffers(clubID, roomID))
+	if err != nil {
+		return 0, err
+	}
+
+	offered := 0
+	for _, userID := range waiting {
+		if _, hasOffer := offers[userID]; hasOffer {
+			continue
+		}
+
+		isSeated, err := ws.isSeatedInClub(clubID, userID)
+		if err != nil {
+			return offered, err
+		}
+		if isSeated {
+			ws.RemoveFromAll(clubID, userID)
+			continue
+		}
+
+		// Резервируем место: пока предложение действует, его не займет никто другой
+		seat, err := ws.seatManager.AutoSelectSeat(clubID, roomID, room.MaxPlayers)
+		if err == ErrNoFreeSeats {
+			break
+		}
+		if err != nil {
+			return offered, err
+		}
+
+		now := utils.GetCurrentTimestampMillis()
+		offer := &models.SeatOffer{
+			UserID:    userID,
+			RoomID:    roomID,
+			Seat:      seat,
+			OfferedAt: now,
+			ExpiresAt: now + ws.offerTimeout.Milliseconds(),
+		}
+
+		offerJSON, _ := json.Marshal(offer)
+		if err := ws.redis.HSet(keys.RoomSeatOffers(clubID, roomID), userID, string(offerJSON)); err != nil {
+			ws.seatManager.CancelReservation(clubID, roomID, seat)
+			return offered, err
+		}
+
+		ws.actionLogger.LogSeatOffered(clubID, roomID, userID, seat, offer.ExpiresAt)
+		ws.logger.Infof("Игроку %s предложено место %d в комнате %s:%s", userID, seat, clubID, roomID)
+		offered++
+	}
+
+	return offered, nil
+}
+
+// ExpireOffers - снимает просроченные предложения мест
+// Игроки, не принявшие место вовремя, удаляются из листа ожидания
+// Возвращает количество снятых предложений
+func (ws *WaitingListService) ExpireOffers(clubID, roomID string) (int, error) {
+	offers, err := ws.redis.HGetAll(ws.redis.GetKeys().RoomSeatOffers(clubID, roomID))
+	if err != nil {
+		return 0, err
+	}
+
+	now := utils.GetCurrentTimestampMillis()
+	expired := 0
+
+	for userID, offerJSON := range offers {
+		var offer models.SeatOffer
+		if err := json.Unmarshal([]byte(offerJSON), &offer); err != nil {
+			ws.logger.Warningf("Ошибка при парсинге предложения места игроку %s: %v", userID, err)
+			continue
+		}
+		if !offer.IsExpired(now) {
+			continue
+		}
+
+		if err := ws.dropFromRoom(clubID, roomID, userID, WaitingListReasonOfferExpired); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// ProcessRoom - снимает просроченные предложения и предлагает освободившиеся места
+func (ws *WaitingListService) ProcessRoom(clubID, roomID string) error {
+	if _, err := ws.ExpireOffers(clubID, roomID); err != nil {
+		return err
+	}
+
+	_, err := ws.OfferSeats(clubID, roomID)
+	return err
+}
+
+// GetWaitingList - возвращает лист ожидания комнаты в порядке очереди
+func (ws *WaitingListService) GetWaitingList(clubID, roomID string) ([]models.WaitingListEntry, error) {
+	members, err := ws.redis.ZRangeWithScores(ws.redis.GetKeys().RoomWaitingList(clubID, roomID), 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.WaitingListEntry, 0, len(members))
+	for i, member := range members {
+		userID, ok := member.Member.(string)
+		if !ok {
+			continue
+		}
+		entries = append(entries, models.WaitingListEntry{
+			UserID:   userID,
+			Position: i + 1,
+			JoinedAt: int64(member.Score),
+		})
+	}
+
+	return entries, nil
+}
+
+// GetPosition - возвращает позицию игрока в листе ожидания (0 - игрока нет в очереди)
+func (ws *WaitingListService) GetPosition(clubID, roomID, userID string) (int, error) {
+	key := ws.redis.GetKeys().RoomWaitingList(clubID, roomID)
+	rank, err := ws.redis.GetClient().ZRank(ws.redis.GetContext(), key, userID).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int(rank) + 1, nil
+}
+
+// GetUserWaitingLists - возвращает комнаты, в листах ожидания которых стоит игрок
+func (ws *WaitingListService) GetUserWaitingLists(clubID, userID string) ([]string, error) {
+	return ws.redis.SMembers(ws.redis.GetKeys().UserWaitingLists(clubID, userID))
+}
+
+// GetOffer - возвращает предложение места игроку (nil если предложения нет)
+func (ws *WaitingListService) GetOffer(clubID, roomID, userID string) (*models.SeatOffer, error) {
+	offerJSON, err := ws.redis.HGet(ws.redis.GetKeys().RoomSeatOffers(clubID, roomID), userID)
+	if err != nil {
+		return nil, err
+	}
+	if offerJSON == "" {
+		return nil, nil
+	}
+
+	var offer models.SeatOffer
+	if err := json.Unmarshal([]byte(offerJSON), &offer); err != nil {
+		return nil, fmt.Errorf("ошибка при парсинге предложения места: %w", err)
+	}
+
+	return &offer, nil
+}
+
+// dropFromRoom - удаляет игрока из листа ожидания комнаты и снимает резерв предложенного ему места
+// Освободившееся место сразу предлагается следующему в очереди
+func (ws *WaitingListService) dropFromRoom(clubID, roomID, userID, reason string) error {
+	offer, err := ws.GetOffer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+
+	keys := ws.redis.GetKeys()
+	ctx := ws.redis.GetContext()
+
+	pipe := ws.redis.TxPipeline()
+	pipe.ZRem(ctx, keys.RoomWaitingList(clubID, roomID), userID)
+	pipe.HDel(ctx, keys.RoomSeatOffers(clubID, roomID), userID)
+	pipe.SRem(ctx, keys.UserWaitingLists(clubID, userID), roomID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		ws.logger.Errorf("Ошибка при удалении игрока %s из листа ожидания %s:%s: %v", userID, clubID, roomID, err)
+		return fmt.Errorf("ошибка удаления из листа ожидания: %w", err)
+	}
+
+	ws.actionLogger.LogWaitingListLeft(clubID, roomID, userID, reason)
+	ws.logger.Infof("Игрок %s удален из листа ожидания %s:%s (%s)", userID, clubID, roomID, reason)
+
+	if offer == nil {
+		return nil
+	}
+
+	if err := ws.seatManager.CancelReservation(clubID, roomID, offer.Seat); err != nil {
+		return err
+	}
+
+	_, err = ws.OfferSeats(clubID, roomID)
+	return err
+}
+
+// releaseOfferedSeat - снимает резерв места, предложенного игроку, который сел за этот же стол
+// Место, на котором игрок сидит, остается занятым
+func (ws *WaitingListService) releaseOfferedSeat(clubID, roomID, userID string) error {
+	offer, err := ws.GetOffer(clubID, roomID, userID)
+	if err != nil || offer == nil {
+		return err
+	}
+
+	player, err := ws.gameStateService.GetPlayer(clubID, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if player != nil && player.Position == offer.Seat {
+		return nil
+	}
+
+	return ws.seatManager.CancelReservation(clubID, roomID, offer.Seat)
+}
+
+// isSeatedInClub - проверяет, сидит ли игрок за каким-либо столом клуба
+func (ws *WaitingListService) isSeatedInClub(clubID, userID string) (bool, error) {
+	return ws.redis.Exists(ws.redis.GetKeys().UserCurrentRoom(clubID, userID))
+}
+
+var (
+	ErrNoSeatOffer      = &WaitingListError{message: "no seat offer for player"}
+	ErrSeatOfferExpired = &WaitingListError{message: "seat offer has expired"}
+)
+
+type WaitingListError struct {
+	message string
+}
+
+func (e *WaitingListError) Error() string {
+	return "waiting list error: " + e.message
+}
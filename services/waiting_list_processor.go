@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"poker-engine/storage"
+	"poker-engine/utils"
+)
+
+// WaitingListProcessor - фоновая задача обслуживания листов ожидания
+// Периодически обходит все активные комнаты: снимает просроченные предложения мест
+// и предлагает освободившиеся места следующим игрокам в очереди
+type WaitingListProcessor struct {
+	redis       *storage.RedisClient
+	waitingList *WaitingListService
+	logger      *utils.Logger
+	interval    time.Duration
+	ctx         context.Context
+	cancelFunc  context.CancelFunc
+	isRunning   bool
+}
+
+// NewWaitingListProcessor - создает новый экземпляр WaitingListProcessor
+func NewWaitingListProcessor(
+	redis *storage.RedisClient,
+	waitingList *WaitingListService,
+	interval time.Duration,
+) *WaitingListProcessor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &WaitingListProcessor{
+		redis:       redis,
+		waitingList: waitingList,
+		logger:      utils.NewLogger("WaitingListProcessor"),
+		interval:    interval,
+		ctx:         ctx,
+		cancelFunc:  cancel,
+	}
+}
+
+// Start - запускает обработку листов ожидания (блокирующий вызов, запускать в горутине)
+func (wp *WaitingListProcessor) Start() {
+	if wp.isRunning {
+		wp.logger.Warning("Обработка листов ожидания уже запущена")
+		return
+	}
+
+	wp.isRunning = true
+	wp.logger.Successf("Обработка листов ожидания запущена (интервал: %v)", wp.interval)
+
+	ticker := time.NewTicker(wp.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			wp.ProcessAll()
+		case <-wp.ctx.Done():
+			wp.isRunning = false
+			return
+		}
+	}
+}
+
+// Stop - останавливает обработку листов ожидания
+func (wp *WaitingListProcessor) Stop() {
+	wp.cancelFunc()
+	wp.isRunning = false
+}
+
+// IsRunning - возвращает статус работы обработки
+func (wp *WaitingListProcessor) IsRunning() bool {
+	return wp.isRunning
+}
+
+// ProcessAll - обрабатывает листы ожидания всех активных комнат всех клубов
+func (wp *WaitingListProcessor) ProcessAll() {
+	pattern := wp.redis.GetKeys().ClubRoomsActivePattern()
+	iter := wp.redis.Scan(pattern)
+
+	for iter.Next(wp.ctx) {
+		clubID := wp.redis.GetKeys().ExtractClubID(iter.Val())
+		if clubID == "" {
+			continue
+		}
+
+		roomIDs, err := wp.redis.ZRange(iter.Val(), 0, -1)
+		if err != nil {
+			wp.logger.Errorf("Ошибка при получении комнат клуба %s: %v", clubID, err)
+			continue
+		}
+
+		for _, roomID := range roomIDs {
+			if err := wp.waitingList.ProcessRoom(clubID, roomID); err != nil {
+				wp.logger.Errorf("Ошибка при обработке листа ожидания %s:%s: %v", clubID, roomID, err)
+			}
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		wp.logger.Errorf("Ошибка при сканировании клубов: %v", err)
+	}
+}
@@ -29,6 +29,62 @@ func (k *Keys) ClubRoomsActivePattern() string {
 	return "club:*:rooms:active"
 }
 
+// ClubRakeHistory - возвращает ключ для истории рейка клуба
+// Формат: "club:{clubId}:rake:history"
+// Пример: "club:1:rake:history"
+// Тип: LIST - хранит JSON объекты RakeRecord (по одному на раздачу)
+func (k *Keys) ClubRakeHistory(clubID string) string {
+	return fmt.Sprintf("club:%s:rake:history", clubID)
+}
+
+// ClubRakeTotals - возвращает ключ для накопленного рейка клуба
+// Формат: "club:{clubId}:rake:totals"
+// Пример: "club:1:rake:totals"
+// Тип: HASH - хранит total (весь рейк клуба), hands (раздач с рейком) и room:{roomId} (рейк по комнатам)
+func (k *Keys) ClubRakeTotals(clubID string) string {
+	return fmt.Sprintf("club:%s:rake:totals", clubID)
+}
+
+// ClubLedger - возвращает ключ для журнала движения фишек клуба
+// Формат: "club:{clubId}:ledger"
+// Пример: "club:1:ledger"
+// Тип: LIST - append-only список JSON объектов LedgerEntry
+func (k *Keys) ClubLedger(clubID string) string {
+	return fmt.Sprintf("club:%s:ledger", clubID)
+}
+
+// ClubLedgerBalances - возвращает ключ для балансов счетов журнала клуба
+// Формат: "club:{clubId}:ledger:balances"
+// Пример: "club:1:ledger:balances"
+// Тип: HASH - хранит баланс каждого счета (account -> amount)
+func (k *Keys) ClubLedgerBalances(clubID string) string {
+	return fmt.Sprintf("club:%s:ledger:balances", clubID)
+}
+
+// ClubLedgerSequence - возвращает ключ для счетчика записей журнала клуба
+// Формат: "club:{clubId}:ledger:seq"
+// Пример: "club:1:ledger:seq"
+// Тип: STRING - последний выданный номер записи (INCR)
+func (k *Keys) ClubLedgerSequence(clubID string) string {
+	return fmt.Sprintf("club:%s:ledger:seq", clubID)
+}
+
+// ClubLedgerMismatches - возвращает ключ для найденных расхождений журнала
+// Формат: "club:{clubId}:ledger:mismatches"
+// Пример: "club:1:ledger:mismatches"
+// Тип: LIST - JSON объекты ReconciliationMismatch
+func (k *Keys) ClubLedgerMismatches(clubID string) string {
+	return fmt.Sprintf("club:%s:ledger:mismatches", clubID)
+}
+
+// ClubStatsLeaderboard - возвращает ключ для рейтинга игроков клуба по чистому выигрышу
+// Формат: "club:{clubId}:stats:leaderboard"
+// Пример: "club:1:stats:leaderboard"
+// Тип: SORTED SET - userId, score - чистый выигрыш игрока во всех раздачах клуба
+func (k *Keys) ClubStatsLeaderboard(clubID string) string {
+	return fmt.Sprintf("club:%s:stats:leaderboard", clubID)
+}
+
 // === КЛЮЧИ КОМНАТ ===
 
 // RoomInfo - возвращает ключ для информации о комнате
@@ -111,6 +167,71 @@ func (k *Keys) RoomTimers(clubID, roomID string) string {
 	return fmt.Sprintf("club:%s:room:%s:timers", clubID, roomID)
 }
 
+// RoomWaitingList - возвращает ключ для листа ожидания комнаты
+// Формат: "club:{clubId}:room:{roomId}:waiting_list"
+// Пример: "club:1:room:3:waiting_list"
+// Тип: ZSET - хранит userId со временем записи (мс) как score (FIFO)
+func (k *Keys) RoomWaitingList(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:waiting_list", clubID, roomID)
+}
+
+// RoomSeatOffers - возвращает ключ для предложенных мест из листа ожидания
+// Формат: "club:{clubId}:room:{roomId}:seat_offers"
+// Пример: "club:1:room:3:seat_offers"
+// Тип: HASH - userId -> JSON предложения (место, срок действия)
+func (k *Keys) RoomSeatOffers(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:seat_offers", clubID, roomID)
+}
+
+// RoomRunItOffer - возвращает ключ для предложения прогнать доску несколько раз
+// Формат: "club:{clubId}:room:{roomId}:run_it_offer"
+// Пример: "club:1:room:3:run_it_offer"
+// Тип: HASH - поле offer с JSON предложения (участники, срок ответа) и выбор каждого игрока (userId -> количество прогонов)
+func (k *Keys) RoomRunItOffer(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:run_it_offer", clubID, roomID)
+}
+
+// RoomBombPotVotes - возвращает ключ для голосов за бомб-пот
+// Формат: "club:{clubId}:room:{roomId}:bomb_pot_votes"
+// Пример: "club:1:room:3:bomb_pot_votes"
+// Тип: SET - userId игроков, проголосовавших за бомб-пот в следующей раздаче
+func (k *Keys) RoomBombPotVotes(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:bomb_pot_votes", clubID, roomID)
+}
+
+// RoomBlindClock - возвращает ключ для часов уровней блайндов комнаты
+// Формат: "club:{clubId}:room:{roomId}:blind_clock"
+// Пример: "club:1:room:3:blind_clock"
+// Тип: HASH - levels (JSON расписания), level_index, level_started_at, level_hands
+// Время начала уровня хранится абсолютным, поэтому часы продолжают идти после перезапуска движка
+func (k *Keys) RoomBlindClock(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:blind_clock", clubID, roomID)
+}
+
+// RoomHandHistory - возвращает ключ для истории раздач комнаты
+// Формат: "club:{clubId}:room:{roomId}:hand_history"
+// Пример: "club:1:room:3:hand_history"
+// Тип: LIST - хранит JSON истории раздач в хронологическом порядке
+func (k *Keys) RoomHandHistory(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:hand_history", clubID, roomID)
+}
+
+// RoomHandActions - возвращает ключ для действий игроков в текущей раздаче
+// Формат: "club:{clubId}:room:{roomId}:hand_actions"
+// Пример: "club:1:room:3:hand_actions"
+// Тип: LIST - JSON объекты HandAction в порядке совершения, удаляется в конце раздачи
+func (k *Keys) RoomHandActions(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:hand_actions", clubID, roomID)
+}
+
+// RoomAllInEquity - возвращает ключ для эквити игроков, зафиксированного в момент олл-ина
+// Формат: "club:{clubId}:room:{roomId}:all_in_equity"
+// Пример: "club:1:room:3:all_in_equity"
+// Тип: STRING - JSON AllInSnapshot (руки, доска и эквити на момент олл-ина), удаляется после вскрытия
+func (k *Keys) RoomAllInEquity(clubID, roomID string) string {
+	return fmt.Sprintf("club:%s:room:%s:all_in_equity", clubID, roomID)
+}
+
 // === КЛЮЧИ ИГРОКОВ ===
 
 // PlayerInfo - возвращает ключ для информации об игроке в комнате
@@ -121,6 +242,15 @@ func (k *Keys) PlayerInfo(clubID, roomID, userID string) string {
 	return fmt.Sprintf("club:%s:room:%s:player:%s", clubID, roomID, userID)
 }
 
+// PlayerPendingChips - возвращает ключ для фишек игрока, купленных во время раздачи
+// Формат: "club:{clubId}:room:{roomId}:player:{userId}:pending_chips"
+// Пример: "club:1:room:3:player:456:pending_chips"
+// Тип: HASH - операция покупки (rebuy, top_up, add_on) -> количество фишек, ожидающих зачисления
+// Сумма по операциям равна полю pending_chips игрока
+func (k *Keys) PlayerPendingChips(clubID, roomID, userID string) string {
+	return fmt.Sprintf("club:%s:room:%s:player:%s:pending_chips", clubID, roomID, userID)
+}
+
 // SpectatorInfo - возвращает ключ для информации о наблюдателе
 // Формат: "club:{clubId}:room:{roomId}:spectator:{userId}"
 // Пример: "club:1:room:3:spectator:456"
@@ -138,6 +268,96 @@ func (k *Keys) UserCurrentRoom(clubID, userID string) string {
 	return fmt.Sprintf("club:%s:user:%s:current_room", clubID, userID)
 }
 
+// UserWaitingLists - возвращает ключ для списка комнат, в листах ожидания которых стоит пользователь
+// Формат: "club:{clubId}:user:{userId}:waiting_lists"
+// Пример: "club:1:user:456:waiting_lists"
+// Тип: SET - хранит roomId
+func (k *Keys) UserWaitingLists(clubID, userID string) string {
+	return fmt.Sprintf("club:%s:user:%s:waiting_lists", clubID, userID)
+}
+
+// UserAllInEV - возвращает ключ для накопленных итогов олл-инов игрока в клубе
+// Формат: "club:{clubId}:user:{userId}:all_in_ev"
+// Пример: "club:1:user:456:all_in_ev"
+// Тип: HASH - hands (раздачи с олл-ином), net (фактический результат), ev_net (результат по эквити)
+func (k *Keys) UserAllInEV(clubID, userID string) string {
+	return fmt.Sprintf("club:%s:user:%s:all_in_ev", clubID, userID)
+}
+
+// UserStats - возвращает ключ для статистики игрока в клубе
+// Формат: "club:{clubId}:user:{userId}:stats"
+// Пример: "club:1:user:456:stats"
+// Тип: HASH - счетчики PlayerStats (hands, vpip, pfr, three_bets, calls, showdowns, net и т.д.)
+func (k *Keys) UserStats(clubID, userID string) string {
+	return fmt.Sprintf("club:%s:user:%s:stats", clubID, userID)
+}
+
+// === КЛЮЧИ ТУРНИРОВ ===
+
+// ClubTournaments - возвращает ключ для списка турниров клуба
+// Формат: "club:{clubId}:tournaments"
+// Пример: "club:1:tournaments"
+// Тип: ZSET - хранит tournamentId со временем создания (мс) как score
+func (k *Keys) ClubTournaments(clubID string) string {
+	return fmt.Sprintf("club:%s:tournaments", clubID)
+}
+
+// Tournament - возвращает ключ для информации о турнире
+// Формат: "club:{clubId}:tournament:{tournamentId}"
+// Пример: "club:1:tournament:7"
+// Тип: HASH - хранит type, status, buy_in, fee, starting_stack, blind_levels, prize_pool и т.д.
+func (k *Keys) Tournament(clubID, tournamentID string) string {
+	return fmt.Sprintf("club:%s:tournament:%s", clubID, tournamentID)
+}
+
+// TournamentEntries - возвращает ключ для участников турнира
+// Формат: "club:{clubId}:tournament:{tournamentId}:entries"
+// Пример: "club:1:tournament:7:entries"
+// Тип: HASH - userId -> JSON участника (статус, занятое место, приз)
+func (k *Keys) TournamentEntries(clubID, tournamentID string) string {
+	return fmt.Sprintf("club:%s:tournament:%s:entries", clubID, tournamentID)
+}
+
+// TournamentTables - возвращает ключ для столов турнира
+// Формат: "club:{clubId}:tournament:{tournamentId}:tables"
+// Пример: "club:1:tournament:7:tables"
+// Тип: SET - roomId столов, за которыми еще идет игра (закрытые столы удаляются)
+func (k *Keys) TournamentTables(clubID, tournamentID string) string {
+	return fmt.Sprintf("club:%s:tournament:%s:tables", clubID, tournamentID)
+}
+
+// TournamentHandForHand - возвращает ключ для столов, доигравших текущую раздачу рука в руку
+// Формат: "club:{clubId}:tournament:{tournamentId}:hand_for_hand"
+// Пример: "club:1:tournament:7:hand_for_hand"
+// Тип: SET - roomId столов, ожидающих остальные столы; очищается, когда доиграли все столы
+func (k *Keys) TournamentHandForHand(clubID, tournamentID string) string {
+	return fmt.Sprintf("club:%s:tournament:%s:hand_for_hand", clubID, tournamentID)
+}
+
+// TournamentDeal - возвращает ключ для предложения сделки за финальным столом
+// Формат: "club:{clubId}:tournament:{tournamentId}:deal"
+// Пример: "club:1:tournament:7:deal"
+// Тип: HASH - поле deal с JSON предложения (способ, стеки, выплаты) и согласие участников (userId -> 1)
+func (k *Keys) TournamentDeal(clubID, tournamentID string) string {
+	return fmt.Sprintf("club:%s:tournament:%s:deal", clubID, tournamentID)
+}
+
+// TournamentPurchases - возвращает ключ для ребаев и аддонов участников турнира
+// Формат: "club:{clubId}:tournament:{tournamentId}:purchases"
+// Пример: "club:1:tournament:7:purchases"
+// Тип: HASH - rebuys:{userId} -> количество ребаев, add_on:{userId} -> 1; право на покупку занимается здесь атомарно
+func (k *Keys) TournamentPurchases(clubID, tournamentID string) string {
+	return fmt.Sprintf("club:%s:tournament:%s:purchases", clubID, tournamentID)
+}
+
+// TournamentAudit - возвращает ключ для журнала аудита турнира
+// Формат: "club:{clubId}:tournament:{tournamentId}:audit"
+// Пример: "club:1:tournament:7:audit"
+// Тип: LIST - JSON записи о регистрациях, ребаях и аддонах с призовым фондом после каждой операции
+func (k *Keys) TournamentAudit(clubID, tournamentID string) string {
+	return fmt.Sprintf("club:%s:tournament:%s:audit", clubID, tournamentID)
+}
+
 // === ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ ===
 
 // IsRoomKey - проверяет, является ли ключ ключом комнаты
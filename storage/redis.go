@@ -150,6 +150,17 @@ func (r *RedisClient) Del(keys ...string) error {
 	return nil
 }
 
+// Incr - увеличивает целочисленное значение ключа на 1
+// Возвращает новое значение
+func (r *RedisClient) Incr(key string) (int64, error) {
+	val, err := r.client.Incr(r.ctx, key).Result()
+	if err != nil {
+		r.logger.RedisError(fmt.Sprintf("INCR %s", key), err)
+		return 0, err
+	}
+	return val, nil
+}
+
 // Exists - проверяет существование ключа
 func (r *RedisClient) Exists(key string) (bool, error) {
 	val, err := r.client.Exists(r.ctx, key).Result()
@@ -205,6 +216,38 @@ func (r *RedisClient) HMSet(key string, values map[string]interface{}) error {
 	return nil
 }
 
+// HIncrBy - увеличивает целочисленное поле hash на указанное значение
+// Возвращает новое значение поля
+func (r *RedisClient) HIncrBy(key, field string, increment int64) (int64, error) {
+	val, err := r.client.HIncrBy(r.ctx, key, field, increment).Result()
+	if err != nil {
+		r.logger.RedisError(fmt.Sprintf("HINCRBY %s %s", key, field), err)
+		return 0, err
+	}
+	return val, nil
+}
+
+// HSetNX - устанавливает поле hash, только если его еще нет
+// Возвращает true, если поле было установлено
+func (r *RedisClient) HSetNX(key string, field string, value interface{}) (bool, error) {
+	val, err := r.client.HSetNX(r.ctx, key, field, value).Result()
+	if err != nil {
+		r.logger.RedisError(fmt.Sprintf("HSETNX %s %s", key, field), err)
+		return false, err
+	}
+	return val, nil
+}
+
+// HDel - удаляет поле(я) из hash
+func (r *RedisClient) HDel(key string, fields ...string) error {
+	err := r.client.HDel(r.ctx, key, fields...).Err()
+	if err != nil {
+		r.logger.RedisError(fmt.Sprintf("HDEL %s", key), err)
+		return err
+	}
+	return nil
+}
+
 // === SET ОПЕРАЦИИ ===
 
 // SAdd - добавляет элемент(ы) в set
@@ -293,6 +336,28 @@ func (r *RedisClient) ZRange(key string, start, stop int64) ([]string, error) {
 	return val, nil
 }
 
+// ZRangeWithScores - получает элементы sorted set вместе с их score
+// start=0, stop=-1 возвращает все элементы
+func (r *RedisClient) ZRangeWithScores(key string, start, stop int64) ([]redis.Z, error) {
+	val, err := r.client.ZRangeWithScores(r.ctx, key, start, stop).Result()
+	if err != nil {
+		r.logger.RedisError(fmt.Sprintf("ZRANGE %s %d %d WITHSCORES", key, start, stop), err)
+		return nil, err
+	}
+	return val, nil
+}
+
+// ZRevRangeWithScores - получает элементы sorted set вместе с их score в порядке убывания score
+// start=0, stop=-1 возвращает все элементы
+func (r *RedisClient) ZRevRangeWithScores(key string, start, stop int64) ([]redis.Z, error) {
+	val, err := r.client.ZRevRangeWithScores(r.ctx, key, start, stop).Result()
+	if err != nil {
+		r.logger.RedisError(fmt.Sprintf("ZREVRANGE %s %d %d WITHSCORES", key, start, stop), err)
+		return nil, err
+	}
+	return val, nil
+}
+
 // ZCard - получает количество элементов в sorted set
 func (r *RedisClient) ZCard(key string) (int64, error) {
 	val, err := r.client.ZCard(r.ctx, key).Result()